@@ -44,6 +44,9 @@ func init() {
 	CreateCommand.AddToParent(Cmd)
 	StakingCommand.AddToParent(Cmd)
 	GetCommand.AddToParent(Cmd)
+	FundCommand.AddToParent(Cmd)
+	StorageCommand.AddToParent(Cmd)
+	Cmd.AddCommand(KeysCmd)
 }
 
 // AccountResult represent result from all account commands.
@@ -19,6 +19,8 @@
 package accounts
 
 import (
+	"context"
+
 	"github.com/onflow/flow-cli/pkg/flowkit"
 
 	"github.com/spf13/cobra"
@@ -46,6 +48,7 @@ var RemoveCommand = &command.Command{
 }
 
 func removeContract(
+	ctx context.Context,
 	args []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
@@ -59,7 +62,7 @@ func removeContract(
 		return nil, err
 	}
 
-	account, err := services.Accounts.RemoveContract(from, contractName)
+	account, err := services.Accounts.RemoveContract(ctx, from, contractName)
 	if err != nil {
 		return nil, err
 	}
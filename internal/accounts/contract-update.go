@@ -19,6 +19,7 @@
 package accounts
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/cadence"
@@ -51,6 +52,7 @@ var UpdateCommand = &command.Command{
 }
 
 func updateContract(
+	ctx context.Context,
 	args []string,
 	readerWriter flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
@@ -81,7 +83,8 @@ func updateContract(
 		return nil, fmt.Errorf("error parsing transaction arguments: %w", err)
 	}
 
-	account, err := srv.Accounts.AddContract(
+	account, err := srv.Accounts.AddContractWithContext(
+		ctx,
 		to,
 		&services.Contract{
 			Name:     name,
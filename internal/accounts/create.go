@@ -19,6 +19,7 @@
 package accounts
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -37,13 +38,15 @@ import (
 )
 
 type flagsCreate struct {
-	Signer    string   `default:"emulator-account" flag:"signer" info:"Account name from configuration used to sign the transaction"`
-	Keys      []string `flag:"key" info:"Public keys to attach to account"`
-	Weights   []int    `flag:"key-weight" info:"Weight for the key"`
-	SigAlgo   []string `default:"ECDSA_P256" flag:"sig-algo" info:"Signature algorithm used to generate the keys"`
-	HashAlgo  []string `default:"SHA3_256" flag:"hash-algo" info:"Hash used for the digest"`
-	Contracts []string `flag:"contract" info:"Contract to be deployed during account creation. <name:filename>"`
-	Include   []string `default:"" flag:"include" info:"Fields to include in the output"`
+	Signer      string   `default:"emulator-account" flag:"signer" info:"Account name from configuration used to sign the transaction"`
+	Keys        []string `flag:"key" info:"Public keys to attach to account"`
+	Weights     []int    `flag:"key-weight" info:"Weight for the key"`
+	SigAlgo     []string `default:"ECDSA_P256" flag:"sig-algo" info:"Signature algorithm used to generate the keys"`
+	HashAlgo    []string `default:"SHA3_256" flag:"hash-algo" info:"Hash used for the digest"`
+	Contracts   []string `flag:"contract" info:"Contract to be deployed during account creation. <name:filename>"`
+	EstimateGas bool     `default:"false" flag:"estimate-gas" info:"Estimate the gas limit by executing the transaction on an emulator, instead of using the maximum gas limit, transactions using the storage of their authorizers can't be estimated"`
+	GasMargin   uint64   `default:"20" flag:"gas-margin" info:"Safety margin added to the estimated gas limit, in percent"`
+	Include     []string `default:"" flag:"include" info:"Fields to include in the output"`
 }
 
 var createFlags = flagsCreate{}
@@ -59,6 +62,7 @@ var CreateCommand = &command.Command{
 }
 
 func create(
+	ctx context.Context,
 	_ []string,
 	loader flowkit.ReaderWriter,
 	_ command.GlobalFlags,
@@ -67,7 +71,7 @@ func create(
 ) (command.Result, error) {
 	// if user doesn't provide any flags go into interactive mode
 	if len(createFlags.Keys) == 0 {
-		_, err := createInteractive(state, loader)
+		_, err := createInteractive(ctx, state, loader)
 		if err != nil {
 			return nil, err
 		}
@@ -127,13 +131,21 @@ func create(
 		pubKeys = append(pubKeys, key)
 	}
 
-	account, err := services.Accounts.Create(
+	gasLimit := flowkit.MaxGasLimit
+	if createFlags.EstimateGas {
+		gasLimit = 0 // estimated by the service
+	}
+
+	account, err := services.Accounts.CreateWithContext(
+		ctx,
 		signer,
 		pubKeys,
 		keyWeights,
 		sigAlgos,
 		hashAlgos,
 		createFlags.Contracts,
+		gasLimit,
+		createFlags.GasMargin,
 	)
 
 	if err != nil {
@@ -146,7 +158,7 @@ func create(
 	}, nil
 }
 
-func createInteractive(state *flowkit.State, loader flowkit.ReaderWriter) (*flow.Account, error) {
+func createInteractive(ctx context.Context, state *flowkit.State, loader flowkit.ReaderWriter) (*flow.Account, error) {
 	log := output.NewStdoutLogger(output.InfoLog)
 
 	name := output.AccountNamePrompt(state.Accounts()) // todo check for duplicate names
@@ -184,7 +196,7 @@ func createInteractive(state *flowkit.State, loader flowkit.ReaderWriter) (*flow
 		return nil, err
 	}
 
-	startHeight, err := service.Blocks.GetLatestBlockHeight()
+	startHeight, err := service.Blocks.GetLatestBlockHeight(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -196,13 +208,16 @@ func createInteractive(state *flowkit.State, loader flowkit.ReaderWriter) (*flow
 		if err != nil {
 			return nil, err
 		}
-		account, err := service.Accounts.Create(
+		account, err := service.Accounts.CreateWithContext(
+			ctx,
 			signer,
 			[]crypto.PublicKey{key.PublicKey()},
 			[]int{flow.AccountKeyWeightThreshold},
 			[]crypto.SignatureAlgorithm{crypto.ECDSA_P256},
 			[]crypto.HashAlgorithm{crypto.SHA3_256},
 			nil,
+			flowkit.MaxGasLimit,
+			services.DefaultGasMargin,
 		)
 		if err != nil {
 			return nil, err
@@ -240,7 +255,7 @@ func createInteractive(state *flowkit.State, loader flowkit.ReaderWriter) (*flow
 		_ = util.OpenBrowserWindow(link)
 		log.Info(output.Italic(fmt.Sprintf("You can also navigate to the link manually: %s\n", link)))
 
-		addr, err := getAccountCreatedAddressWithPubKey(service, key.PublicKey(), startHeight)
+		addr, err := getAccountCreatedAddressWithPubKey(ctx, service, key.PublicKey(), startHeight)
 		if err != nil {
 			return nil, err
 		}
@@ -249,7 +264,7 @@ func createInteractive(state *flowkit.State, loader flowkit.ReaderWriter) (*flow
 		log.StopProgress()
 	}
 
-	onChainAccount, err := service.Accounts.Get(address)
+	onChainAccount, err := service.Accounts.GetWithContext(ctx, address)
 	if err != nil {
 		return nil, err
 	}
@@ -289,16 +304,17 @@ func createInteractive(state *flowkit.State, loader flowkit.ReaderWriter) (*flow
 // getAccountCreatedAddressWithPubKey monitors the network for account creation events, if the event
 // contains the public key we are interested in then it extracts the newly created address from the event payload.
 func getAccountCreatedAddressWithPubKey(
+	ctx context.Context,
 	service *services.Services,
 	pubKey crypto.PublicKey,
 	startHeight uint64,
 ) (*flow.Address, error) {
-	lastHeight, err := service.Blocks.GetLatestBlockHeight()
+	lastHeight, err := service.Blocks.GetLatestBlockHeight(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	flowEvents, _ := service.Events.Get([]string{flow.EventAccountKeyAdded}, startHeight, lastHeight, 20, 1) // ignore AN errors since we will retry anyway
+	flowEvents, _ := service.Events.Get(ctx, []string{flow.EventAccountKeyAdded}, startHeight, lastHeight, 20, 1) // ignore AN errors since we will retry anyway
 
 	var address *flow.Address
 	for _, block := range flowEvents {
@@ -315,7 +331,7 @@ func getAccountCreatedAddressWithPubKey(
 		}
 
 		time.Sleep(time.Second * 2)
-		address, err = getAccountCreatedAddressWithPubKey(service, pubKey, startHeight)
+		address, err = getAccountCreatedAddressWithPubKey(ctx, service, pubKey, startHeight)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,98 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/config"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+	"github.com/onflow/flow-cli/pkg/flowkit/util"
+)
+
+type flagsFund struct {
+	Include []string `default:"" flag:"include" info:"Fields to include in the output. Valid values: contracts."`
+}
+
+var fundFlags = flagsFund{}
+
+var FundCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:   "fund <name|address> <amount>",
+		Short: "Fund an account on the emulator with FLOW minted by the service account",
+		Example: `flow accounts fund alice 100
+flow accounts fund 0x01cf0e2f2f715450 10.5`,
+		Args: cobra.ExactArgs(2),
+	},
+	Flags: &fundFlags,
+	RunS:  fund,
+}
+
+func fund(
+	ctx context.Context,
+	args []string,
+	_ flowkit.ReaderWriter,
+	globalFlags command.GlobalFlags,
+	services *services.Services,
+	state *flowkit.State,
+) (command.Result, error) {
+	if globalFlags.Network == config.DefaultMainnetNetwork().Name {
+		return nil, fmt.Errorf("funding accounts is not supported on %s", globalFlags.Network)
+	}
+
+	var address flow.Address
+	if account, err := state.Accounts().ByName(args[0]); err == nil {
+		address = account.Address()
+	} else {
+		var valid bool
+		address, valid = util.ParseAddress(args[0])
+		if !valid {
+			return nil, fmt.Errorf("%s is not an account name or address", args[0])
+		}
+	}
+
+	// allow whole amounts, UFix64 values require a fractional part
+	amountArg := args[1]
+	if !strings.Contains(amountArg, ".") {
+		amountArg += ".0"
+	}
+
+	amount, err := cadence.NewUFix64(amountArg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount %s: %w", args[1], err)
+	}
+
+	account, err := services.Accounts.Fund(ctx, address, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountResult{
+		Account: account,
+		include: fundFlags.Include,
+	}, nil
+}
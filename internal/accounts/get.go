@@ -19,6 +19,9 @@
 package accounts
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/onflow/flow-go-sdk"
 
 	"github.com/spf13/cobra"
@@ -29,23 +32,29 @@ import (
 )
 
 type flagsGet struct {
-	Include []string `default:"" flag:"include" info:"Fields to include in the output. Valid values: contracts."`
+	Include     []string `default:"" flag:"include" info:"Fields to include in the output. Valid values: contracts."`
+	BlockHeight uint64   `flag:"block-height" info:"Block height to get the account at"`
+	BlockID     string   `default:"" flag:"block-id" info:"Block ID to get the account at"`
 }
 
 var getFlags = flagsGet{}
 
+// getCmd is a variable so get can check whether the block height flag is set, even to 0.
+var getCmd = &cobra.Command{
+	Use:     "get <address>",
+	Short:   "Gets an account by address",
+	Example: "flow accounts get f8d6e0586b0a20c7",
+	Args:    cobra.ExactArgs(1),
+}
+
 var GetCommand = &command.Command{
-	Cmd: &cobra.Command{
-		Use:     "get <address>",
-		Short:   "Gets an account by address",
-		Example: "flow accounts get f8d6e0586b0a20c7",
-		Args:    cobra.ExactArgs(1),
-	},
+	Cmd:   getCmd,
 	Flags: &getFlags,
 	Run:   get,
 }
 
 func get(
+	ctx context.Context,
 	args []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
@@ -53,7 +62,28 @@ func get(
 ) (command.Result, error) {
 	address := flow.HexToAddress(args[0])
 
-	account, err := services.Accounts.Get(address)
+	heightSet := getCmd.Flags().Changed("block-height")
+	if heightSet && getFlags.BlockID != "" {
+		return nil, fmt.Errorf("can't use both block-height and block-id flags")
+	}
+
+	var blockID flow.Identifier
+	var err error
+	if getFlags.BlockID != "" {
+		blockID, err = flowkit.ParseID(getFlags.BlockID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block ID: %w", err)
+		}
+	}
+
+	var account *flow.Account
+	if heightSet {
+		account, err = services.Accounts.GetAtBlockHeight(ctx, address, getFlags.BlockHeight)
+	} else if getFlags.BlockID != "" {
+		account, err = services.Accounts.GetAtBlockID(ctx, address, blockID)
+	} else {
+		account, err = services.Accounts.GetWithContext(ctx, address)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,98 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+)
+
+type flagsAddKey struct {
+	Signer   string   `default:"emulator-account" flag:"signer" info:"Account name from configuration the key is added to"`
+	Weight   int      `default:"1000" flag:"weight" info:"Weight of the key"`
+	SigAlgo  string   `default:"ECDSA_P256" flag:"sig-algo" info:"Signature algorithm of the key"`
+	HashAlgo string   `default:"SHA3_256" flag:"hash-algo" info:"Hash algorithm used with the key"`
+	Include  []string `default:"" flag:"include" info:"Fields to include in the output. Valid values: contracts."`
+}
+
+var addKeyFlags = flagsAddKey{}
+
+var AddKeyCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:     "add <public key>",
+		Short:   "Add a key to an account",
+		Example: `flow accounts keys add d651f1931a2...8745 --signer alice --weight 500`,
+		Args:    cobra.ExactArgs(1),
+	},
+	Flags: &addKeyFlags,
+	RunS:  addKey,
+}
+
+func addKey(
+	ctx context.Context,
+	args []string,
+	_ flowkit.ReaderWriter,
+	_ command.GlobalFlags,
+	services *services.Services,
+	state *flowkit.State,
+) (command.Result, error) {
+	signer, err := state.Accounts().ByName(addKeyFlags.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	sigAlgo := crypto.StringToSignatureAlgorithm(addKeyFlags.SigAlgo)
+	if sigAlgo == crypto.UnknownSignatureAlgorithm {
+		return nil, fmt.Errorf("invalid signature algorithm: %s", addKeyFlags.SigAlgo)
+	}
+
+	hashAlgo := crypto.StringToHashAlgorithm(addKeyFlags.HashAlgo)
+	if hashAlgo == crypto.UnknownHashAlgorithm {
+		return nil, fmt.Errorf("invalid hash algorithm: %s", addKeyFlags.HashAlgo)
+	}
+
+	publicKey, err := crypto.DecodePublicKeyHex(sigAlgo, strings.TrimPrefix(args[0], "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed decoding public key: %s with error: %w", args[0], err)
+	}
+
+	account, err := services.Accounts.AddKey(ctx, signer, &flow.AccountKey{
+		PublicKey: publicKey,
+		SigAlgo:   sigAlgo,
+		HashAlgo:  hashAlgo,
+		Weight:    addKeyFlags.Weight,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountResult{
+		Account: account,
+		include: addKeyFlags.Include,
+	}, nil
+}
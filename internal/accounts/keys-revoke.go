@@ -0,0 +1,78 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+)
+
+type flagsRevokeKey struct {
+	Signer  string   `default:"emulator-account" flag:"signer" info:"Account name from configuration the key is revoked from"`
+	Include []string `default:"" flag:"include" info:"Fields to include in the output. Valid values: contracts."`
+}
+
+var revokeKeyFlags = flagsRevokeKey{}
+
+var RevokeKeyCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:     "revoke <key index>",
+		Short:   "Revoke a key of an account",
+		Example: `flow accounts keys revoke 2 --signer alice`,
+		Args:    cobra.ExactArgs(1),
+	},
+	Flags: &revokeKeyFlags,
+	RunS:  revokeKey,
+}
+
+func revokeKey(
+	ctx context.Context,
+	args []string,
+	_ flowkit.ReaderWriter,
+	_ command.GlobalFlags,
+	services *services.Services,
+	state *flowkit.State,
+) (command.Result, error) {
+	index, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid key index: %s", args[0])
+	}
+
+	signer, err := state.Accounts().ByName(revokeKeyFlags.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := services.Accounts.RevokeKey(ctx, signer, index)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountResult{
+		Account: account,
+		include: revokeKeyFlags.Include,
+	}, nil
+}
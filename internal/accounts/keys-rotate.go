@@ -0,0 +1,168 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/output"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+	"github.com/onflow/flow-cli/pkg/flowkit/util"
+)
+
+type flagsRotateKey struct {
+	Signer     string   `default:"emulator-account" flag:"signer" info:"Account name from configuration the key is rotated for"`
+	PrivateKey string   `default:"" flag:"private-key" info:"Private key to rotate to, a new key is generated if not provided"`
+	SigAlgo    string   `default:"ECDSA_P256" flag:"sig-algo" info:"Signature algorithm of the new key"`
+	HashAlgo   string   `default:"SHA3_256" flag:"hash-algo" info:"Hash algorithm used with the new key"`
+	Include    []string `default:"" flag:"include" info:"Fields to include in the output. Valid values: contracts."`
+}
+
+var rotateKeyFlags = flagsRotateKey{}
+
+var RotateKeyCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:   "rotate",
+		Short: "Replace the key of an account in the configuration with a new key",
+		Example: `#rotate to a generated key
+flow accounts keys rotate --signer alice
+
+#resume an interrupted rotation
+flow accounts keys rotate --signer alice`,
+		Args: cobra.NoArgs,
+	},
+	Flags: &rotateKeyFlags,
+	RunS:  rotateKey,
+}
+
+func rotateKey(
+	ctx context.Context,
+	_ []string,
+	readerWriter flowkit.ReaderWriter,
+	globalFlags command.GlobalFlags,
+	srv *services.Services,
+	state *flowkit.State,
+) (command.Result, error) {
+	signer, err := state.Accounts().ByName(rotateKeyFlags.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	rotationPath := command.KeyRotationPath(globalFlags.ConfigPaths)
+	rotation, err := srv.Accounts.PendingKeyRotation(rotationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if rotation != nil {
+		if rotation.Account != signer.Name() {
+			return nil, fmt.Errorf(
+				"rotation of the key of account %s is pending, resume it with --signer %s first",
+				rotation.Account,
+				rotation.Account,
+			)
+		}
+		if rotateKeyFlags.PrivateKey != "" {
+			return nil, fmt.Errorf("rotation of the key of account %s is pending, resume it without a new private key", signer.Name())
+		}
+
+		srv.Logger.Info(fmt.Sprintf("Resuming the pending rotation of the key of account %s", signer.Name()))
+	} else {
+		rotation, err = newKeyRotation(srv, signer)
+		if err != nil {
+			return nil, err
+		}
+
+		// the rotation file holds the new private key until the configuration is updated
+		err = util.AddToGitIgnore(rotationPath, readerWriter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rotated, err := srv.Accounts.RotateKey(ctx, signer, rotation, rotationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = state.SaveEdited(globalFlags.ConfigPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	// the configuration holds the new key, so the rotation doesn't need to be resumed
+	err = srv.Accounts.ClearKeyRotation(rotationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	srv.Logger.Info(fmt.Sprintf(
+		"%s Key %d of account %s revoked, the account uses key %d in the configuration",
+		output.SuccessEmoji(),
+		rotation.RevokeIndex,
+		rotated.Name(),
+		rotated.Key().Index(),
+	))
+
+	account, err := srv.Accounts.GetWithContext(ctx, rotated.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountResult{
+		Account: account,
+		include: rotateKeyFlags.Include,
+	}, nil
+}
+
+// newKeyRotation creates a rotation to the private key from the flags or a generated private key.
+func newKeyRotation(srv *services.Services, account *flowkit.Account) (*flowkit.KeyRotation, error) {
+	sigAlgo := crypto.StringToSignatureAlgorithm(rotateKeyFlags.SigAlgo)
+	if sigAlgo == crypto.UnknownSignatureAlgorithm {
+		return nil, fmt.Errorf("invalid signature algorithm: %s", rotateKeyFlags.SigAlgo)
+	}
+
+	hashAlgo := crypto.StringToHashAlgorithm(rotateKeyFlags.HashAlgo)
+	if hashAlgo == crypto.UnknownHashAlgorithm {
+		return nil, fmt.Errorf("invalid hash algorithm: %s", rotateKeyFlags.HashAlgo)
+	}
+
+	var privateKey crypto.PrivateKey
+	var err error
+	if rotateKeyFlags.PrivateKey != "" {
+		privateKey, err = crypto.DecodePrivateKeyHex(sigAlgo, strings.TrimPrefix(rotateKeyFlags.PrivateKey, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+	} else {
+		privateKey, err = srv.Keys.Generate("", sigAlgo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return flowkit.NewKeyRotation(account, privateKey, hashAlgo), nil
+}
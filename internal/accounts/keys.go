@@ -0,0 +1,37 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var KeysCmd = &cobra.Command{
+	Use:              "keys <add|revoke|rotate>",
+	Short:            "Manage the keys of an account",
+	Example:          "flow accounts keys rotate --signer alice",
+	Args:             cobra.ExactArgs(1),
+	TraverseChildren: true,
+}
+
+func init() {
+	AddKeyCommand.AddToParent(KeysCmd)
+	RevokeKeyCommand.AddToParent(KeysCmd)
+	RotateKeyCommand.AddToParent(KeysCmd)
+}
@@ -20,6 +20,7 @@ package accounts
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-go-sdk"
@@ -48,6 +49,7 @@ var StakingCommand = &command.Command{
 }
 
 func stakingInfo(
+	ctx context.Context,
 	args []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
@@ -55,7 +57,7 @@ func stakingInfo(
 ) (command.Result, error) {
 	address := flow.HexToAddress(args[0])
 
-	staking, delegation, err := services.Accounts.StakingInfo(address)
+	staking, delegation, err := services.Accounts.StakingInfo(ctx, address)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,169 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+	"github.com/onflow/flow-cli/pkg/flowkit/util"
+)
+
+type flagsStorage struct {
+	BlockHeight uint64 `flag:"block-height" info:"Block height to read the account storage at"`
+}
+
+var storageFlags = flagsStorage{}
+
+// storageCmd is referenced by storage to tell a block height of 0 from an unset flag.
+var storageCmd = &cobra.Command{
+	Use:     "storage <address>",
+	Short:   "List the paths, capabilities and token vaults in the account storage",
+	Example: "flow accounts storage f8d6e0586b0a20c7",
+	Args:    cobra.ExactArgs(1),
+}
+
+var StorageCommand = &command.Command{
+	Cmd:   storageCmd,
+	Flags: &storageFlags,
+	Run:   storage,
+}
+
+func storage(
+	ctx context.Context,
+	args []string,
+	_ flowkit.ReaderWriter,
+	globalFlags command.GlobalFlags,
+	services *services.Services,
+) (command.Result, error) {
+	address := flow.HexToAddress(args[0])
+
+	chain, err := util.GetAddressNetwork(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine network from address, check the address and network")
+	}
+
+	script := flowkit.NewAccountStorageScript(util.EnvFromNetwork(chain))
+	scriptArgs := []cadence.Value{cadence.NewAddress(address)}
+
+	var value cadence.Value
+	if storageCmd.Flags().Changed("block-height") {
+		value, err = services.Scripts.ExecuteAtBlockHeight(ctx, script, scriptArgs, "", globalFlags.Network, storageFlags.BlockHeight)
+	} else {
+		value, err = services.Scripts.ExecuteWithContext(ctx, script, scriptArgs, "", globalFlags.Network)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	accountStorage, err := flowkit.NewAccountStorageFromValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StorageResult{
+		address: address,
+		storage: accountStorage,
+	}, nil
+}
+
+type StorageResult struct {
+	address flow.Address
+	storage *flowkit.AccountStorage
+}
+
+func (r *StorageResult) JSON() interface{} {
+	items := make([]interface{}, 0, len(r.storage.Items))
+	for _, item := range r.storage.Items {
+		result := map[string]interface{}{
+			"domain": item.Domain,
+			"path":   item.Path,
+			"type":   item.Type,
+		}
+		if item.Target != "" {
+			result["target"] = item.Target
+		}
+		if item.Balance != nil {
+			result["balance"] = item.Balance.String()
+		}
+		items = append(items, result)
+	}
+
+	return map[string]interface{}{
+		"address":  r.address,
+		"used":     r.storage.Used,
+		"capacity": r.storage.Capacity,
+		"items":    items,
+	}
+}
+
+func (r *StorageResult) String() string {
+	var b bytes.Buffer
+	writer := util.CreateTabWriter(&b)
+
+	_, _ = fmt.Fprintf(writer, "Address\t 0x%s\n", r.address)
+	_, _ = fmt.Fprintf(writer, "Storage Used\t %d bytes\n", r.storage.Used)
+	_, _ = fmt.Fprintf(writer, "Storage Capacity\t %d bytes\n", r.storage.Capacity)
+
+	vaults := r.storage.Vaults()
+	_, _ = fmt.Fprintf(writer, "\nVaults\t %d\n", len(vaults))
+	for _, vault := range vaults {
+		_, _ = fmt.Fprintf(writer, "\t%s\t %s\n", vault.Type, vault.Balance)
+	}
+
+	for _, domain := range []string{flowkit.StorageDomainStorage, flowkit.StorageDomainPublic, flowkit.StorageDomainPrivate} {
+		var items []flowkit.AccountStorageItem
+		for _, item := range r.storage.Items {
+			if item.Domain == domain {
+				items = append(items, item)
+			}
+		}
+
+		_, _ = fmt.Fprintf(writer, "\nPaths in /%s\t %d\n", domain, len(items))
+		for _, item := range items {
+			if item.Target != "" {
+				_, _ = fmt.Fprintf(writer, "\t%s\t %s -> %s\n", item.Path, item.Type, item.Target)
+			} else {
+				_, _ = fmt.Fprintf(writer, "\t%s\t %s\n", item.Path, item.Type)
+			}
+		}
+	}
+
+	_ = writer.Flush()
+	return b.String()
+}
+
+func (r *StorageResult) Oneliner() string {
+	return fmt.Sprintf(
+		"Address: 0x%s, Storage Used: %d, Storage Capacity: %d, Paths: %d, Vaults: %d",
+		r.address,
+		r.storage.Used,
+		r.storage.Capacity,
+		len(r.storage.Items),
+		len(r.storage.Vaults()),
+	)
+}
@@ -19,6 +19,7 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -64,6 +65,7 @@ type ExampleConfig struct {
 }
 
 func create(
+	_ context.Context,
 	args []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
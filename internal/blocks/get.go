@@ -19,6 +19,8 @@
 package blocks
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 
 	"github.com/onflow/flow-cli/internal/command"
@@ -45,12 +47,14 @@ var GetCommand = &command.Command{
 }
 
 func get(
+	ctx context.Context,
 	args []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
 	services *services.Services,
 ) (command.Result, error) {
 	block, events, collections, err := services.Blocks.GetBlock(
+		ctx,
 		args[0], // block id
 		blockFlags.Events,
 		command.ContainsFlag(blockFlags.Include, "transactions"),
@@ -19,6 +19,8 @@
 package collections
 
 import (
+	"context"
+
 	"github.com/onflow/flow-go-sdk"
 	"github.com/spf13/cobra"
 
@@ -43,6 +45,7 @@ var GetCommand = &command.Command{
 }
 
 func get(
+	ctx context.Context,
 	args []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
@@ -50,7 +53,7 @@ func get(
 ) (command.Result, error) {
 	id := flow.HexToID(args[0])
 
-	collection, err := services.Collections.Get(id)
+	collection, err := services.Collections.Get(ctx, id)
 	if err != nil {
 		return nil, err
 	}
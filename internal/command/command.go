@@ -19,12 +19,15 @@
 package command
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"runtime/debug"
 	"strings"
 	"time"
@@ -44,6 +47,7 @@ import (
 
 // Run the command with arguments.
 type Run func(
+	ctx context.Context,
 	args []string,
 	readerWriter flowkit.ReaderWriter,
 	globalFlags GlobalFlags,
@@ -52,6 +56,7 @@ type Run func(
 
 // RunWithState runs the command with arguments and state.
 type RunWithState func(
+	ctx context.Context,
 	args []string,
 	readerWriter flowkit.ReaderWriter,
 	globalFlags GlobalFlags,
@@ -107,26 +112,49 @@ func (c Command) AddToParent(parent *cobra.Command) {
 		host, hostNetworkKey, err := resolveHost(state, Flags.Host, Flags.HostNetworkKey, Flags.Network)
 		handleError("Host Error", err)
 
-		clientGateway, err := createGateway(host, hostNetworkKey)
+		var clientGateway gateway.Gateway
+		if Flags.Replay != "" {
+			clientGateway, err = createReplayGateway(Flags.Replay)
+		} else {
+			clientGateway, err = createGateway(host, hostNetworkKey, resolveRetry(state, Flags.Host, Flags.Network), Flags.Timeout)
+		}
 		handleError("Gateway Error", err)
 
+		if Flags.Record != "" {
+			cassette, err := os.Create(Flags.Record)
+			handleError("Record Error", err)
+			defer cassette.Close()
+
+			clientGateway = gateway.NewRecordingGateway(clientGateway, cassette)
+		}
+
 		logger := createLogger(Flags.Log, Flags.Format)
 
+		// record transactions sent from the project, replayed sessions are never sent to the network
+		if state != nil && Flags.Replay == "" {
+			history := flowkit.NewTransactionHistory(loader.Fs, TransactionHistoryPath(Flags.ConfigPaths))
+			clientGateway = gateway.NewHistoryGateway(clientGateway, history, HistoryNetwork(Flags.Host, Flags.Network), logger)
+		}
+
 		// initialize services
 		service := services.NewServices(clientGateway, state, logger)
 
+		// cancel any in-flight network calls when the user interrupts the command
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
 		checkVersion(logger)
 
 		// run command based on requirements for state
 		var result Result
 		if c.Run != nil {
-			result, err = c.Run(args, loader, Flags, service)
+			result, err = c.Run(ctx, args, loader, Flags, service)
 		} else if c.RunS != nil {
 			if confErr != nil {
 				handleError("Config Error", confErr)
 			}
 
-			result, err = c.RunS(args, loader, Flags, service, state)
+			result, err = c.RunS(ctx, args, loader, Flags, service, state)
 		} else {
 			panic("command implementation needs to provide run functionality")
 		}
@@ -148,6 +176,10 @@ func (c Command) AddToParent(parent *cobra.Command) {
 		// output result
 		err = outputResult(formattedResult, Flags.Save, Flags.Format, Flags.Filter)
 		handleError("Output Error", err)
+
+		if exitCoder, ok := result.(ExitCoder); ok && exitCoder.ExitCode() != 0 {
+			os.Exit(exitCoder.ExitCode())
+		}
 	}
 
 	bindFlags(c)
@@ -155,13 +187,94 @@ func (c Command) AddToParent(parent *cobra.Command) {
 }
 
 // createGateway creates a gateway to be used, defaults to grpc but can support others.
-func createGateway(host, hostNetworkKey string) (gateway.Gateway, error) {
+//
+// If a timeout is provided each call is bounded by it, and if a retry policy is provided
+// the gateway is wrapped to retry transient errors, so each attempt gets the full timeout.
+func createGateway(host, hostNetworkKey string, retry *config.Retry, timeout time.Duration) (gateway.Gateway, error) {
+	var gw gateway.Gateway
+	var err error
+
 	// create secure grpc client if hostNetworkKey provided
 	if hostNetworkKey != "" {
-		return gateway.NewSecureGrpcGateway(host, hostNetworkKey)
+		gw, err = gateway.NewSecureGrpcGateway(host, hostNetworkKey)
+	} else {
+		gw, err = gateway.NewGrpcGateway(host)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout > 0 {
+		gw = gateway.NewTimeoutGateway(gw, timeout)
+	}
+
+	if retry != nil {
+		gw = gateway.NewRetryGateway(gw, *retry)
+	}
+
+	return gw, nil
+}
+
+// createReplayGateway creates a gateway serving the network calls recorded in the cassette file.
+func createReplayGateway(cassettePath string) (gateway.Gateway, error) {
+	cassette, err := os.Open(cassettePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette: %w", err)
+	}
+	defer cassette.Close()
+
+	return gateway.NewReplayGateway(cassette)
+}
+
+// TransactionHistoryPath returns the path of the transaction history next to the project configuration.
+func TransactionHistoryPath(configPaths []string) string {
+	return projectFilePath(configPaths, flowkit.DefaultTransactionHistoryPath)
+}
+
+// KeyRotationPath returns the path of the pending key rotation next to the project configuration.
+func KeyRotationPath(configPaths []string) string {
+	return projectFilePath(configPaths, flowkit.DefaultKeyRotationPath)
+}
+
+// ContractsLockPath returns the path of the lock of the pulled contracts next to the project configuration.
+func ContractsLockPath(configPaths []string) string {
+	return projectFilePath(configPaths, flowkit.DefaultContractsLockPath)
+}
+
+// projectFilePath returns the path of the file next to the project configuration,
+// which is the last configuration path since the global configuration comes first.
+func projectFilePath(configPaths []string, filename string) string {
+	if len(configPaths) == 0 {
+		return filename
+	}
+
+	return filepath.Join(filepath.Dir(configPaths[len(configPaths)-1]), filename)
+}
+
+// HistoryNetwork returns the network name the transactions are recorded with in the history,
+// the host is used when the host flag is provided since it doesn't belong to a configured network.
+func HistoryNetwork(hostFlag, networkFlag string) string {
+	if hostFlag != "" {
+		return hostFlag
+	}
+
+	return networkFlag
+}
+
+// resolveRetry returns the retry policy of the network from the configuration if one is set.
+//
+// The policy is not used when the host flag is provided since the host doesn't belong to a configured network.
+func resolveRetry(state *flowkit.State, hostFlag, networkFlag string) *config.Retry {
+	if state == nil || hostFlag != "" {
+		return nil
+	}
+
+	network, err := state.Networks().ByName(networkFlag)
+	if err != nil {
+		return nil
 	}
 
-	return gateway.NewGrpcGateway(host)
+	return network.Retry
 }
 
 // resolveHost from the flags provided.
@@ -21,6 +21,7 @@ package command
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/psiemens/sconfig"
 	"github.com/spf13/cobra"
@@ -40,6 +41,9 @@ type GlobalFlags struct {
 	Network        string
 	Yes            bool
 	ConfigPaths    []string
+	Record         string
+	Replay         string
+	Timeout        time.Duration
 }
 
 // Flags initialized to default values.
@@ -53,6 +57,9 @@ var Flags = GlobalFlags{
 	Log:            logLevelInfo,
 	Yes:            false,
 	ConfigPaths:    config.DefaultPaths(),
+	Record:         "",
+	Replay:         "",
+	Timeout:        0,
 }
 
 // InitFlags init all the global persistent flags.
@@ -128,6 +135,30 @@ func InitFlags(cmd *cobra.Command) {
 		Flags.Yes,
 		"Approve any prompts",
 	)
+
+	cmd.PersistentFlags().StringVarP(
+		&Flags.Record,
+		"record",
+		"",
+		Flags.Record,
+		"Record network calls to a cassette file",
+	)
+
+	cmd.PersistentFlags().StringVarP(
+		&Flags.Replay,
+		"replay",
+		"",
+		Flags.Replay,
+		"Replay network calls from a cassette file instead of connecting to the host",
+	)
+
+	cmd.PersistentFlags().DurationVarP(
+		&Flags.Timeout,
+		"timeout",
+		"",
+		Flags.Timeout,
+		"Timeout of each network call, e.g. \"30s\", no timeout if zero",
+	)
 }
 
 // bindFlags bind all the flags needed.
@@ -42,6 +42,12 @@ type Result interface {
 	JSON() interface{}
 }
 
+// ExitCoder is implemented by results which exit with a non-zero code after they are printed,
+// so checks done by a command can fail scripts and CI jobs.
+type ExitCoder interface {
+	ExitCode() int
+}
+
 // ContainsFlag checks if output flag is present for the provided field.
 func ContainsFlag(flags []string, field string) bool {
 	for _, n := range flags {
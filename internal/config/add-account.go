@@ -19,6 +19,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
@@ -54,6 +55,7 @@ var AddAccountCommand = &command.Command{
 }
 
 func addAccount(
+	_ context.Context,
 	_ []string,
 	_ flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
@@ -19,6 +19,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
@@ -53,6 +54,7 @@ var AddContractCommand = &command.Command{
 }
 
 func addContract(
+	_ context.Context,
 	_ []string,
 	_ flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
@@ -19,6 +19,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
@@ -51,6 +52,7 @@ var AddDeploymentCommand = &command.Command{
 }
 
 func addDeployment(
+	_ context.Context,
 	_ []string,
 	_ flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
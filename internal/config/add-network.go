@@ -19,6 +19,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 
@@ -53,6 +54,7 @@ var AddNetworkCommand = &command.Command{
 }
 
 func addNetwork(
+	_ context.Context,
 	_ []string,
 	_ flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
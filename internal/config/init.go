@@ -20,6 +20,7 @@ package config
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-go-sdk/crypto"
@@ -52,6 +53,7 @@ var InitCommand = &command.Command{
 }
 
 func Initialise(
+	_ context.Context,
 	_ []string,
 	readerWriter flowkit.ReaderWriter,
 	_ command.GlobalFlags,
@@ -19,6 +19,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
@@ -46,6 +47,7 @@ var MetricsSettings = &command.Command{
 }
 
 func handleMetricsSettings(
+	_ context.Context,
 	args []string,
 	loader flowkit.ReaderWriter,
 	_ command.GlobalFlags,
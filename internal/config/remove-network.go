@@ -19,6 +19,8 @@
 package config
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 
 	"github.com/onflow/flow-cli/internal/command"
@@ -43,6 +45,7 @@ var RemoveNetworkCommand = &command.Command{
 }
 
 func removeNetwork(
+	_ context.Context,
 	args []string,
 	_ flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
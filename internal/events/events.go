@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/onflow/flow-cli/pkg/flowkit"
 	"github.com/onflow/flow-cli/pkg/flowkit/util"
 
 	"github.com/onflow/cadence"
@@ -40,6 +41,8 @@ var Cmd = &cobra.Command{
 
 func init() {
 	GetCommand.AddToParent(Cmd)
+	WatchCommand.AddToParent(Cmd)
+	ExportCommand.AddToParent(Cmd)
 }
 
 type EventResult struct {
@@ -53,15 +56,7 @@ func (e *EventResult) JSON() interface{} {
 	for _, blockEvent := range e.BlockEvents {
 		if len(blockEvent.Events) > 0 {
 			for _, event := range blockEvent.Events {
-				result = append(result, map[string]interface{}{
-					"blockID":       blockEvent.Height,
-					"index":         event.EventIndex,
-					"type":          event.Type,
-					"transactionId": event.TransactionID.String(),
-					"values": json.RawMessage(
-						jsoncdc.MustEncode(event.Value),
-					),
-				})
+				result = append(result, eventJSON(blockEvent.Height, event))
 			}
 		}
 	}
@@ -69,6 +64,18 @@ func (e *EventResult) JSON() interface{} {
 	return result
 }
 
+func eventJSON(height uint64, event flow.Event) map[string]interface{} {
+	return map[string]interface{}{
+		"blockID":       height,
+		"index":         event.EventIndex,
+		"type":          event.Type,
+		"transactionId": event.TransactionID.String(),
+		"values": json.RawMessage(
+			jsoncdc.MustEncode(event.Value),
+		),
+	}
+}
+
 func (e *EventResult) String() string {
 	var b bytes.Buffer
 	writer := util.CreateTabWriter(&b)
@@ -116,6 +123,9 @@ func eventString(writer io.Writer, event flow.Event) {
 	_, _ = fmt.Fprintf(writer, "\n    Index\t%d\n", event.EventIndex)
 	_, _ = fmt.Fprintf(writer, "    Type\t%s\n", event.Type)
 	_, _ = fmt.Fprintf(writer, "    Tx ID\t%s\n", event.TransactionID)
+	if summary := CoreEventSummary(event); summary != "" {
+		_, _ = fmt.Fprintf(writer, "    Summary\t%s\n", summary)
+	}
 	_, _ = fmt.Fprintf(writer, "    Values\n")
 
 	for i, field := range event.Value.EventType.Fields {
@@ -123,6 +133,21 @@ func eventString(writer io.Writer, event flow.Event) {
 		printField(writer, field, value)
 	}
 }
+
+// CoreEventSummary returns a short description of a core protocol event, or an empty string for other events.
+func CoreEventSummary(event flow.Event) string {
+	if !flowkit.IsCoreEvent(event.Type) {
+		return ""
+	}
+
+	decoded, err := flowkit.DecodeCoreEvent(flowkit.NewEvent(event))
+	if err != nil || decoded == nil {
+		return ""
+	}
+
+	return decoded.String()
+}
+
 func printValues(writer io.Writer, fieldIdentifier, typedId, valueString string) {
 	_, _ = fmt.Fprintf(writer, "\t\t- %s (%s): %s \n", fieldIdentifier, typedId, valueString)
 }
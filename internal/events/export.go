@@ -0,0 +1,402 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+)
+
+const (
+	exportFormatNDJSON = "ndjson"
+	exportFormatCSV    = "csv"
+)
+
+type flagsExport struct {
+	Start      uint64 `flag:"start" info:"Start block height"`
+	End        uint64 `flag:"end" info:"End block height"`
+	File       string `default:"events.ndjson" flag:"file" info:"File the events are exported to"`
+	Format     string `default:"ndjson" flag:"format" info:"Export format, options: \"ndjson\", \"csv\""`
+	Checkpoint string `default:"" flag:"checkpoint" info:"Checkpoint file used to resume the export, defaults to the export file with the .checkpoint extension"`
+	Workers    int    `default:"10" flag:"workers" info:"Number of workers to use when fetching events in parallel"`
+	Batch      uint64 `default:"25" flag:"batch" info:"Number of blocks each worker will fetch at first, adjusted to what the access node can serve"`
+}
+
+var exportFlags = flagsExport{}
+
+// exportCmd is a variable so export can check whether the start and end heights are set, since 0 is a valid height.
+var exportCmd = &cobra.Command{
+	Use:   "export <event_name>",
+	Short: "Export events in a block range to a file",
+	Args:  cobra.MinimumNArgs(1),
+	Example: `#export events to a newline delimited JSON file, run the same command again to resume an interrupted export
+flow events export A.1654653399040a61.FlowToken.TokensDeposited --start 11559500 --end 11659500 --file deposits.ndjson --network mainnet
+
+#export events to a CSV file with a column for each event field
+flow events export A.1654653399040a61.FlowToken.TokensDeposited --start 11559500 --end 11659500 --file deposits.csv --format csv --network mainnet
+`,
+}
+
+var ExportCommand = &command.Command{
+	Cmd:   exportCmd,
+	Flags: &exportFlags,
+	Run:   export,
+}
+
+func export(
+	ctx context.Context,
+	args []string,
+	_ flowkit.ReaderWriter,
+	_ command.GlobalFlags,
+	srv *services.Services,
+) (command.Result, error) {
+	if !exportCmd.Flags().Changed("start") || !exportCmd.Flags().Changed("end") {
+		return nil, fmt.Errorf("please provide both start and end for the range to export")
+	}
+
+	if exportFlags.Format != exportFormatNDJSON && exportFlags.Format != exportFormatCSV {
+		return nil, fmt.Errorf("invalid format %s, options: \"ndjson\", \"csv\"", exportFlags.Format)
+	}
+
+	checkpointPath := exportFlags.Checkpoint
+	if checkpointPath == "" {
+		checkpointPath = exportFlags.File + ".checkpoint"
+	}
+
+	checkpoint, err := loadCheckpoint(checkpointPath, args, exportFlags.Start, exportFlags.End)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := openExportFile(exportFlags.File, checkpoint.FileSize)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var writer services.EventsWriter
+	if exportFlags.Format == exportFormatCSV {
+		writer, err = newCSVEventsWriter(file)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		writer = &ndjsonEventsWriter{file: file, encoder: json.NewEncoder(file)}
+	}
+
+	err = srv.Events.Export(
+		ctx,
+		checkpoint.ExportCheckpoint,
+		exportFlags.Batch,
+		exportFlags.Workers,
+		writer,
+		func(completed *services.ExportCheckpoint) error {
+			// the events are already synced by the writer, so the file size includes all the completed ranges
+			size, err := file.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+
+			return saveCheckpoint(checkpointPath, &exportCheckpoint{ExportCheckpoint: completed, FileSize: size})
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w\nrun the same command again to resume the export", err)
+	}
+
+	return &ExportResult{
+		file:        exportFlags.File,
+		events:      args,
+		startHeight: exportFlags.Start,
+		endHeight:   exportFlags.End,
+	}, nil
+}
+
+// exportCheckpoint is the export checkpoint with the size of the export file when it was saved.
+//
+// The events written after the last saved checkpoint are truncated when the export is resumed,
+// so an export interrupted between writing events and saving the checkpoint doesn't duplicate them.
+type exportCheckpoint struct {
+	*services.ExportCheckpoint
+	FileSize int64 `json:"fileSize"`
+}
+
+// loadCheckpoint loads the checkpoint of a previous export if it exists, otherwise a new checkpoint is returned.
+func loadCheckpoint(path string, events []string, start uint64, end uint64) (*exportCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &exportCheckpoint{ExportCheckpoint: services.NewExportCheckpoint(events, start, end)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export checkpoint: %w", err)
+	}
+
+	var checkpoint exportCheckpoint
+	err = json.Unmarshal(data, &checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse export checkpoint %s: %w", path, err)
+	}
+	if checkpoint.ExportCheckpoint == nil {
+		return nil, fmt.Errorf("failed to parse export checkpoint %s: checkpoint is empty", path)
+	}
+
+	if checkpoint.StartHeight != start || checkpoint.EndHeight != end || !reflect.DeepEqual(checkpoint.Events, events) {
+		return nil, fmt.Errorf(
+			"checkpoint %s belongs to a different export, remove it to start a new export", path,
+		)
+	}
+
+	return &checkpoint, nil
+}
+
+// saveCheckpoint writes the checkpoint to a temporary file first so an interruption never leaves a partial checkpoint.
+func saveCheckpoint(path string, checkpoint *exportCheckpoint) error {
+	data, err := json.MarshalIndent(checkpoint, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	err = os.WriteFile(tmpPath, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// openExportFile opens the export file and truncates it to the size recorded in the checkpoint,
+// removing the events written after the checkpoint was saved. A new export starts with an empty file.
+func openExportFile(path string, size int64) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open export file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to open export file: %w", err)
+	}
+
+	if info.Size() < size {
+		_ = file.Close()
+		return nil, fmt.Errorf(
+			"export file %s is smaller than recorded in the checkpoint, remove the checkpoint to start a new export", path,
+		)
+	}
+
+	err = file.Truncate(size)
+	if err == nil {
+		_, err = file.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to truncate export file to the checkpoint: %w", err)
+	}
+
+	return file, nil
+}
+
+type ExportResult struct {
+	file        string
+	events      []string
+	startHeight uint64
+	endHeight   uint64
+}
+
+func (r *ExportResult) JSON() interface{} {
+	return map[string]interface{}{
+		"file":        r.file,
+		"events":      r.events,
+		"startHeight": r.startHeight,
+		"endHeight":   r.endHeight,
+	}
+}
+
+func (r *ExportResult) String() string {
+	return fmt.Sprintf("Events exported to %s", r.file)
+}
+
+func (r *ExportResult) Oneliner() string {
+	return fmt.Sprintf("File: %s, Start Height: %d, End Height: %d", r.file, r.startHeight, r.endHeight)
+}
+
+// exportedEvent is a single exported event with its values flattened.
+type exportedEvent struct {
+	BlockHeight      uint64            `json:"blockHeight"`
+	BlockID          string            `json:"blockId"`
+	BlockTimestamp   time.Time         `json:"blockTimestamp"`
+	TransactionID    string            `json:"transactionId"`
+	TransactionIndex int               `json:"transactionIndex"`
+	EventIndex       int               `json:"eventIndex"`
+	Type             string            `json:"type"`
+	Values           map[string]string `json:"values"`
+}
+
+func exportedEvents(blockEvents []flow.BlockEvents) []exportedEvent {
+	var exported []exportedEvent
+	for _, blockEvent := range blockEvents {
+		for _, event := range blockEvent.Events {
+			e := flowkit.NewEvent(event)
+			exported = append(exported, exportedEvent{
+				BlockHeight:      blockEvent.Height,
+				BlockID:          blockEvent.BlockID.String(),
+				BlockTimestamp:   blockEvent.BlockTimestamp,
+				TransactionID:    event.TransactionID.String(),
+				TransactionIndex: event.TransactionIndex,
+				EventIndex:       event.EventIndex,
+				Type:             event.Type,
+				Values:           e.FlatValues(),
+			})
+		}
+	}
+
+	return exported
+}
+
+// ndjsonEventsWriter writes each event as a JSON object on its own line.
+type ndjsonEventsWriter struct {
+	file    *os.File
+	encoder *json.Encoder
+}
+
+func (w *ndjsonEventsWriter) Write(blockEvents []flow.BlockEvents) error {
+	for _, event := range exportedEvents(blockEvents) {
+		err := w.encoder.Encode(event)
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.file.Sync()
+}
+
+var csvHeader = []string{
+	"blockHeight",
+	"blockId",
+	"blockTimestamp",
+	"transactionId",
+	"transactionIndex",
+	"eventIndex",
+	"type",
+}
+
+// csvEventsWriter writes each event as a CSV row with a column for each flattened event value.
+//
+// The value columns are taken from the first exported event, so all the exported events must have the same fields.
+type csvEventsWriter struct {
+	file         *os.File
+	writer       *csv.Writer
+	valueColumns []string
+}
+
+// newCSVEventsWriter returns a new CSV writer, if the file already has a header its value columns are reused.
+func newCSVEventsWriter(file *os.File) (*csvEventsWriter, error) {
+	w := &csvEventsWriter{
+		file:   file,
+		writer: csv.NewWriter(file),
+	}
+
+	header, err := csv.NewReader(io.NewSectionReader(file, 0, 1<<62)).Read()
+	if errors.Is(err, io.EOF) {
+		return w, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header of the export file: %w", err)
+	}
+
+	if len(header) < len(csvHeader) {
+		return nil, fmt.Errorf("export file has an invalid CSV header")
+	}
+
+	w.valueColumns = header[len(csvHeader):]
+	return w, nil
+}
+
+func (w *csvEventsWriter) Write(blockEvents []flow.BlockEvents) error {
+	for _, event := range exportedEvents(blockEvents) {
+		if w.valueColumns == nil {
+			err := w.writeHeader(event)
+			if err != nil {
+				return err
+			}
+		}
+
+		row := []string{
+			strconv.FormatUint(event.BlockHeight, 10),
+			event.BlockID,
+			event.BlockTimestamp.Format(time.RFC3339Nano),
+			event.TransactionID,
+			strconv.Itoa(event.TransactionIndex),
+			strconv.Itoa(event.EventIndex),
+			event.Type,
+		}
+
+		for _, column := range w.valueColumns {
+			row = append(row, event.Values[column])
+			delete(event.Values, column)
+		}
+
+		if len(event.Values) > 0 {
+			return fmt.Errorf(
+				"event %s has fields which are not in the CSV columns, export events with different fields using the ndjson format",
+				event.Type,
+			)
+		}
+
+		err := w.writer.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return err
+	}
+
+	return w.file.Sync()
+}
+
+func (w *csvEventsWriter) writeHeader(event exportedEvent) error {
+	w.valueColumns = make([]string, 0, len(event.Values))
+	for name := range event.Values {
+		w.valueColumns = append(w.valueColumns, name)
+	}
+	sort.Strings(w.valueColumns)
+
+	return w.writer.Write(append(append([]string{}, csvHeader...), w.valueColumns...))
+}
@@ -19,9 +19,11 @@
 package events
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
+	"github.com/onflow/flow-go-sdk"
 	"github.com/spf13/cobra"
 
 	"github.com/onflow/flow-cli/internal/command"
@@ -34,7 +36,8 @@ type flagsEvents struct {
 	End     uint64 `flag:"end" info:"End block height"`
 	Last    uint64 `default:"10" flag:"last" info:"Fetch number of blocks relative to the last block. Ignored if the start flag is set. Used as a default if no flags are provided"`
 	Workers int    `default:"10" flag:"workers" info:"Number of workers to use when fetching events in parallel"`
-	Batch   uint64 `default:"25" flag:"batch" info:"Number of blocks each worker will fetch"`
+	Batch   uint64 `default:"25" flag:"batch" info:"Number of blocks each worker will fetch at first, adjusted to what the access node can serve"`
+	Where   string `default:"" flag:"where" info:"Only include events with values matching the filter expression, e.g. 'amount > 10.0'"`
 }
 
 var eventsFlags = flagsEvents{}
@@ -55,6 +58,9 @@ flow events get A.1654653399040a61.FlowToken.TokensDeposited --last 20 --network
 
 #if you want to fetch multiple event types that is done by sending in more events. Even fetching will be done in parallel.
 flow events get A.1654653399040a61.FlowToken.TokensDeposited A.1654653399040a61.FlowToken.TokensWithdrawn
+
+#only include deposits to an account of more than 10 tokens
+flow events get A.1654653399040a61.FlowToken.TokensDeposited --where 'to == 0x01cf0e2f2f715450 && amount > 10.0'
 	`,
 	},
 	Flags: &eventsFlags,
@@ -62,11 +68,21 @@ flow events get A.1654653399040a61.FlowToken.TokensDeposited A.1654653399040a61.
 }
 
 func get(
+	ctx context.Context,
 	args []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
 	services *services.Services,
 ) (command.Result, error) {
+	var filter *flowkit.EventFilter
+	if eventsFlags.Where != "" {
+		var err error
+		filter, err = flowkit.ParseEventFilter(eventsFlags.Where)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var err error
 	start := eventsFlags.Start
 	end := eventsFlags.End
@@ -85,7 +101,7 @@ func get(
 			if len(args) == 3 {
 				endV25 := args[2]
 				if endV25 == "latest" {
-					latest, err := services.Blocks.GetLatestBlockHeight()
+					latest, err := services.Blocks.GetLatestBlockHeight(ctx)
 					if err != nil {
 						return nil, err
 					}
@@ -104,7 +120,7 @@ func get(
 	// handle if not passing start and end
 	if start == 0 && end == 0 {
 		//cannot use := here as it will not overrwrite end in the outer scope if you do
-		end, err = services.Blocks.GetLatestBlockHeight()
+		end, err = services.Blocks.GetLatestBlockHeight(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -118,7 +134,12 @@ func get(
 		return nil, fmt.Errorf("please provide either both start and end for range or only last flag")
 	}
 
-	events, err := services.Events.Get(args, start, end, eventsFlags.Batch, eventsFlags.Workers)
+	var events []flow.BlockEvents
+	if filter != nil {
+		events, err = services.Events.GetFiltered(ctx, args, start, end, eventsFlags.Batch, eventsFlags.Workers, filter)
+	} else {
+		events, err = services.Events.Get(ctx, args, start, end, eventsFlags.Batch, eventsFlags.Workers)
+	}
 	if err != nil {
 		return nil, err
 	}
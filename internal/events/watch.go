@@ -0,0 +1,164 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+	"github.com/onflow/flow-cli/pkg/flowkit/util"
+)
+
+type flagsWatch struct {
+	Start    uint64 `flag:"start" info:"Start block height, defaults to the block after the latest sealed block"`
+	Workers  int    `default:"10" flag:"workers" info:"Number of workers to use when fetching events in parallel"`
+	Batch    uint64 `default:"25" flag:"batch" info:"Number of blocks each worker will fetch at first, adjusted to what the access node can serve"`
+	Interval uint64 `default:"1" flag:"interval" info:"Seconds to wait between checks for new sealed blocks"`
+	Where    string `default:"" flag:"where" info:"Only include events with values matching the filter expression, e.g. 'amount > 10.0'"`
+}
+
+var watchFlags = flagsWatch{}
+
+var WatchCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:   "watch <event_name>",
+		Short: "Watch events as new blocks are sealed",
+		Args:  cobra.MinimumNArgs(1),
+		Example: `#print events as they are emitted, stop with Ctrl-C
+flow events watch A.1654653399040a61.FlowToken.TokensDeposited --network mainnet
+
+#print events starting from a past block height as newline delimited JSON
+flow events watch A.1654653399040a61.FlowToken.TokensDeposited --start 11559500 --output json
+
+#print only deposits to an account
+flow events watch A.1654653399040a61.FlowToken.TokensDeposited --where 'to == 0x01cf0e2f2f715450'
+	`,
+	},
+	Flags: &watchFlags,
+	Run:   watch,
+}
+
+func watch(
+	ctx context.Context,
+	args []string,
+	_ flowkit.ReaderWriter,
+	globalFlags command.GlobalFlags,
+	services *services.Services,
+) (command.Result, error) {
+	var filter *flowkit.EventFilter
+	if watchFlags.Where != "" {
+		var err error
+		filter, err = flowkit.ParseEventFilter(watchFlags.Where)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if watchFlags.Interval == 0 {
+		return nil, fmt.Errorf("interval must be at least 1 second")
+	}
+
+	start := watchFlags.Start
+	if start == 0 {
+		latest, err := services.Blocks.GetLatestBlockHeight(ctx)
+		if err != nil {
+			return nil, err
+		}
+		start = latest + 1
+	}
+
+	printEvents := printWatchedText
+	if globalFlags.Format == "json" {
+		printEvents = printWatchedJSON
+	}
+
+	err := services.Events.Watch(
+		ctx,
+		args,
+		start,
+		watchFlags.Batch,
+		watchFlags.Workers,
+		time.Duration(watchFlags.Interval)*time.Second,
+		func(blockEvents []flow.BlockEvents) error {
+			if filter != nil {
+				var err error
+				blockEvents, err = filter.FilterBlockEvents(blockEvents)
+				if err != nil {
+					return err
+				}
+			}
+			return printEvents(os.Stdout, blockEvents)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// events were already printed as they arrived
+	return nil, nil
+}
+
+// printWatchedText prints the events in the same format as the get command.
+func printWatchedText(writer io.Writer, blockEvents []flow.BlockEvents) error {
+	var b bytes.Buffer
+	tabWriter := util.CreateTabWriter(&b)
+
+	for _, blockEvent := range blockEvents {
+		if len(blockEvent.Events) > 0 {
+			_, _ = fmt.Fprintf(tabWriter, "Events Block #%v:", blockEvent.Height)
+			eventsString(tabWriter, blockEvent.Events)
+			_, _ = fmt.Fprintf(tabWriter, "\n")
+		}
+	}
+
+	err := tabWriter.Flush()
+	if err != nil {
+		return err
+	}
+
+	_, err = b.WriteTo(writer)
+	return err
+}
+
+// printWatchedJSON prints each event as a JSON object on its own line.
+func printWatchedJSON(writer io.Writer, blockEvents []flow.BlockEvents) error {
+	encoder := json.NewEncoder(writer)
+
+	for _, blockEvent := range blockEvents {
+		for _, event := range blockEvent.Events {
+			err := encoder.Encode(eventJSON(blockEvent.Height, event))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -19,6 +19,7 @@
 package keys
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -51,6 +52,7 @@ var DecodeCommand = &command.Command{
 }
 
 func decode(
+	_ context.Context,
 	args []string,
 	readerWriter flowkit.ReaderWriter,
 	_ command.GlobalFlags,
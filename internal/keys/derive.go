@@ -19,6 +19,7 @@
 package keys
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-go-sdk/crypto"
@@ -47,6 +48,7 @@ var DeriveCommand = &command.Command{
 }
 
 func derive(
+	_ context.Context,
 	args []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
@@ -19,6 +19,7 @@
 package keys
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-go-sdk/crypto"
@@ -50,6 +51,7 @@ var GenerateCommand = &command.Command{
 }
 
 func generate(
+	_ context.Context,
 	_ []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
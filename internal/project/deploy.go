@@ -19,6 +19,8 @@
 package project
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 
 	"github.com/onflow/flow-cli/internal/command"
@@ -29,7 +31,9 @@ import (
 )
 
 type flagsDeploy struct {
-	Update bool `flag:"update" default:"false" info:"use update flag to update existing contracts"`
+	Update      bool   `flag:"update" default:"false" info:"use update flag to update existing contracts"`
+	EstimateGas bool   `flag:"estimate-gas" default:"false" info:"Estimate the gas limit of each deployment by executing it on an emulator, instead of using the maximum gas limit, transactions using the storage of their authorizers can't be estimated"`
+	GasMargin   uint64 `flag:"gas-margin" default:"20" info:"Safety margin added to the estimated gas limits, in percent"`
 }
 
 var deployFlags = flagsDeploy{}
@@ -45,6 +49,7 @@ var DeployCommand = &command.Command{
 }
 
 func deploy(
+	ctx context.Context,
 	_ []string,
 	_ flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
@@ -61,7 +66,18 @@ func deploy(
 
 	}
 
-	c, err := services.Project.Deploy(globalFlags.Network, deployFlags.Update)
+	gasLimit := flowkit.MaxGasLimit
+	if deployFlags.EstimateGas {
+		gasLimit = 0 // estimated by the service
+	}
+
+	c, err := services.Project.Deploy(
+		ctx,
+		globalFlags.Network,
+		deployFlags.Update,
+		gasLimit,
+		deployFlags.GasMargin,
+	)
 	if err != nil {
 		return nil, err
 	}
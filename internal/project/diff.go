@@ -0,0 +1,172 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package project
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+	"github.com/onflow/flow-cli/pkg/flowkit/util"
+)
+
+const (
+	diffUnchanged     = "unchanged"
+	diffChanged       = "changed"
+	diffNotDeployed   = "not deployed"
+	diffNotConfigured = "missing from deployment config"
+)
+
+var DiffCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:     "diff",
+		Short:   "Show the differences between the project contracts and the deployed contracts",
+		Example: "flow project diff --network testnet",
+	},
+	Flags: &struct{}{},
+	RunS:  diff,
+}
+
+func diff(
+	ctx context.Context,
+	_ []string,
+	_ flowkit.ReaderWriter,
+	globalFlags command.GlobalFlags,
+	services *services.Services,
+	_ *flowkit.State,
+) (command.Result, error) {
+	diffs, err := services.Project.Diff(ctx, globalFlags.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DiffResult{
+		network:   globalFlags.Network,
+		contracts: make([]diffContract, 0, len(diffs)),
+	}
+	for _, d := range diffs {
+		contract := diffContract{
+			name:    d.Name,
+			account: d.Account,
+			address: d.Address.String(),
+			status:  diffUnchanged,
+		}
+
+		switch {
+		case !d.Configured:
+			contract.status = diffNotConfigured
+		case !d.Deployed:
+			contract.status = diffNotDeployed
+		case d.Changed():
+			contract.status = diffChanged
+			contract.unified, err = d.Unified()
+			if err != nil {
+				return nil, fmt.Errorf("failed to diff contract %s: %w", d.Name, err)
+			}
+		}
+
+		result.contracts = append(result.contracts, contract)
+	}
+
+	return result, nil
+}
+
+type diffContract struct {
+	name    string
+	account string
+	address string
+	status  string
+	unified string
+}
+
+type DiffResult struct {
+	network   string
+	contracts []diffContract
+}
+
+// ExitCode is non-zero when any contract differs, so the command can gate CI jobs.
+func (r *DiffResult) ExitCode() int {
+	for _, contract := range r.contracts {
+		if contract.status != diffUnchanged {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (r *DiffResult) JSON() interface{} {
+	contracts := make([]interface{}, 0, len(r.contracts))
+	for _, contract := range r.contracts {
+		c := map[string]string{
+			"name":    contract.name,
+			"account": contract.account,
+			"address": contract.address,
+			"status":  contract.status,
+		}
+		if contract.unified != "" {
+			c["diff"] = contract.unified
+		}
+		contracts = append(contracts, c)
+	}
+
+	return map[string]interface{}{
+		"network":     r.network,
+		"differences": r.ExitCode() != 0,
+		"contracts":   contracts,
+	}
+}
+
+func (r *DiffResult) String() string {
+	var b bytes.Buffer
+	writer := util.CreateTabWriter(&b)
+
+	_, _ = fmt.Fprintf(writer, "Contracts on %s\t %d\n", r.network, len(r.contracts))
+	for _, contract := range r.contracts {
+		_, _ = fmt.Fprintf(writer, "\t%s\t %s\t 0x%s\t %s\n", contract.name, contract.account, contract.address, contract.status)
+	}
+	_ = writer.Flush()
+
+	for _, contract := range r.contracts {
+		if contract.unified != "" {
+			_, _ = fmt.Fprintf(&b, "\n%s", contract.unified)
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (r *DiffResult) Oneliner() string {
+	var differences []string
+	for _, contract := range r.contracts {
+		if contract.status != diffUnchanged {
+			differences = append(differences, fmt.Sprintf("%s: %s", contract.name, contract.status))
+		}
+	}
+
+	if len(differences) == 0 {
+		return fmt.Sprintf("Contracts: %d, no differences", len(r.contracts))
+	}
+	return fmt.Sprintf("Contracts: %d, Differences: %s", len(r.contracts), strings.Join(differences, ", "))
+}
@@ -30,5 +30,7 @@ var Cmd = &cobra.Command{
 
 func init() {
 	DeployCommand.AddToParent(Cmd)
+	DiffCommand.AddToParent(Cmd)
+	PullCommand.AddToParent(Cmd)
 	Cmd.AddCommand(EmulatorCommand)
 }
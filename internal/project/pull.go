@@ -0,0 +1,135 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package project
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+	"github.com/onflow/flow-cli/pkg/flowkit/util"
+)
+
+type flagsPull struct {
+	Dir    string `default:"./imports" flag:"dir" info:"Directory the pulled contracts are written to"`
+	Update bool   `default:"false" flag:"update" info:"Pull contracts which changed on-chain since they were locked"`
+}
+
+var pullFlags = flagsPull{}
+
+var PullCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:   "pull <address> <contract name>",
+		Short: "Pull a deployed contract and the contracts it imports into the project",
+		Example: `#pull a contract and its imports from testnet
+flow project pull 0x9a0766d93b6608b7 FungibleToken --network testnet
+
+#pull the contracts again after they were updated on-chain
+flow project pull 0x9a0766d93b6608b7 FungibleToken --network testnet --update`,
+		Args: cobra.ExactArgs(2),
+	},
+	Flags: &pullFlags,
+	RunS:  pull,
+}
+
+func pull(
+	ctx context.Context,
+	args []string,
+	_ flowkit.ReaderWriter,
+	globalFlags command.GlobalFlags,
+	srv *services.Services,
+	state *flowkit.State,
+) (command.Result, error) {
+	address := flow.HexToAddress(args[0])
+	name := args[1]
+
+	err := os.MkdirAll(pullFlags.Dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", pullFlags.Dir, err)
+	}
+
+	contracts, err := srv.Project.Pull(
+		ctx,
+		globalFlags.Network,
+		address,
+		name,
+		pullFlags.Dir,
+		command.ContractsLockPath(globalFlags.ConfigPaths),
+		pullFlags.Update,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = state.SaveEdited(globalFlags.ConfigPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PullResult{
+		network:   globalFlags.Network,
+		contracts: contracts,
+	}, nil
+}
+
+type PullResult struct {
+	network   string
+	contracts []flowkit.LockedContract
+}
+
+func (r *PullResult) JSON() interface{} {
+	contracts := make([]interface{}, 0, len(r.contracts))
+	for _, contract := range r.contracts {
+		contracts = append(contracts, map[string]string{
+			"name":     contract.Name,
+			"address":  contract.Address,
+			"source":   contract.Source,
+			"codeHash": contract.CodeHash,
+		})
+	}
+
+	return map[string]interface{}{
+		"network":   r.network,
+		"contracts": contracts,
+	}
+}
+
+func (r *PullResult) String() string {
+	var b bytes.Buffer
+	writer := util.CreateTabWriter(&b)
+
+	_, _ = fmt.Fprintf(writer, "Contracts pulled from %s\t %d\n", r.network, len(r.contracts))
+	for _, contract := range r.contracts {
+		_, _ = fmt.Fprintf(writer, "\t%s\t 0x%s\t %s\n", contract.Name, contract.Address, contract.Source)
+	}
+
+	_ = writer.Flush()
+	return b.String()
+}
+
+func (r *PullResult) Oneliner() string {
+	return fmt.Sprintf("Network: %s, Contracts: %d", r.network, len(r.contracts))
+}
@@ -19,6 +19,7 @@
 package quick
 
 import (
+	"context"
 	"sync"
 
 	"github.com/onflow/flow-cli/internal/command"
@@ -35,11 +36,11 @@ type flagsRun struct {
 
 var runFlags = flagsRun{}
 
-func DeployHelper(args []string, globalFlags command.GlobalFlags, services *services.Services, wg *sync.WaitGroup) {
+func DeployHelper(ctx context.Context, args []string, globalFlags command.GlobalFlags, services *services.Services, wg *sync.WaitGroup) {
 
 	for {
 		//check if the server has started
-		_, err := services.Status.Ping(globalFlags.Network)
+		_, err := services.Status.Ping(ctx, globalFlags.Network)
 		if err == nil {
 			// if the emulator is running run the deploy command
 			project.DeployCommand.Cmd.Run(project.DeployCommand.Cmd, args)
@@ -69,6 +70,7 @@ var RunCommand = &command.Command{
 	},
 	Flags: &runFlags,
 	Run: func(
+		ctx context.Context,
 		args []string,
 		_ flowkit.ReaderWriter,
 		globalFlags command.GlobalFlags,
@@ -78,7 +80,7 @@ var RunCommand = &command.Command{
 		// set number of goroutines
 		waitGroup.Add(2)
 		go EmulatorHelper(args, globalFlags, services, &waitGroup)
-		go DeployHelper(args, globalFlags, services, &waitGroup)
+		go DeployHelper(ctx, args, globalFlags, services, &waitGroup)
 		// wait until completion of the goroutines
 		waitGroup.Wait()
 
@@ -19,9 +19,11 @@
 package scripts
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
 	"github.com/spf13/cobra"
 
 	"github.com/onflow/flow-cli/internal/command"
@@ -30,24 +32,30 @@ import (
 )
 
 type flagsScripts struct {
-	ArgsJSON string   `default:"" flag:"args-json" info:"arguments in JSON-Cadence format"`
-	Arg      []string `default:"" flag:"arg" info:"⚠️  Deprecated: use command arguments"`
+	ArgsJSON    string   `default:"" flag:"args-json" info:"arguments in JSON-Cadence format"`
+	Arg         []string `default:"" flag:"arg" info:"⚠️  Deprecated: use command arguments"`
+	BlockHeight uint64   `flag:"block-height" info:"block height to execute the script at"`
+	BlockID     string   `default:"" flag:"block-id" info:"block ID to execute the script at"`
 }
 
 var scriptFlags = flagsScripts{}
 
+// executeCmd is declared on its own so execute can check the flags set on it without an initialization cycle.
+var executeCmd = &cobra.Command{
+	Use:     "execute <filename> [<argument> <argument> ...]",
+	Short:   "Execute a script",
+	Example: `flow scripts execute script.cdc "Meow" "Woof"`,
+	Args:    cobra.MinimumNArgs(1),
+}
+
 var ExecuteCommand = &command.Command{
-	Cmd: &cobra.Command{
-		Use:     "execute <filename> [<argument> <argument> ...]",
-		Short:   "Execute a script",
-		Example: `flow scripts execute script.cdc "Meow" "Woof"`,
-		Args:    cobra.MinimumNArgs(1),
-	},
+	Cmd:   executeCmd,
 	Flags: &scriptFlags,
 	Run:   execute,
 }
 
 func execute(
+	ctx context.Context,
 	args []string,
 	readerWriter flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
@@ -75,12 +83,47 @@ func execute(
 		return nil, fmt.Errorf("error parsing script arguments: %w", err)
 	}
 
-	value, err := services.Scripts.Execute(
-		code,
-		scriptArgs,
-		filename,
-		globalFlags.Network,
-	)
+	heightSet := executeCmd.Flags().Changed("block-height")
+	if heightSet && scriptFlags.BlockID != "" {
+		return nil, fmt.Errorf("can't use both block-height and block-id flags")
+	}
+
+	var blockID flow.Identifier
+	if scriptFlags.BlockID != "" {
+		blockID, err = flowkit.ParseID(scriptFlags.BlockID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block ID: %w", err)
+		}
+	}
+
+	var value cadence.Value
+	if heightSet {
+		value, err = services.Scripts.ExecuteAtBlockHeight(
+			ctx,
+			code,
+			scriptArgs,
+			filename,
+			globalFlags.Network,
+			scriptFlags.BlockHeight,
+		)
+	} else if scriptFlags.BlockID != "" {
+		value, err = services.Scripts.ExecuteAtBlockID(
+			ctx,
+			code,
+			scriptArgs,
+			filename,
+			globalFlags.Network,
+			blockID,
+		)
+	} else {
+		value, err = services.Scripts.ExecuteWithContext(
+			ctx,
+			code,
+			scriptArgs,
+			filename,
+			globalFlags.Network,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +49,7 @@ var GenerateCommand = &command.Command{
 }
 
 func sign(
+	ctx context.Context,
 	args []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
@@ -62,7 +63,7 @@ func sign(
 		return nil, err
 	}
 
-	s, err := acc.Key().Signer(context.Background())
+	s, err := acc.Key().Signer(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -20,6 +20,7 @@ package signatures
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"fmt"
 	"strings"
@@ -53,6 +54,7 @@ var VerifyCommand = &command.Command{
 }
 
 func verify(
+	_ context.Context,
 	args []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
@@ -19,6 +19,7 @@
 package snapshot
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 
@@ -41,6 +42,7 @@ var SaveCommand = &command.Command{
 }
 
 func save(
+	ctx context.Context,
 	args []string,
 	readerWriter flowkit.ReaderWriter,
 	_ command.GlobalFlags,
@@ -48,7 +50,7 @@ func save(
 ) (command.Result, error) {
 	fileName := args[0]
 
-	snapshotBytes, err := services.Snapshot.GetLatestProtocolStateSnapshot()
+	snapshotBytes, err := services.Snapshot.GetLatestProtocolStateSnapshot(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -20,6 +20,7 @@ package status
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -46,13 +47,14 @@ var Command = &command.Command{
 }
 
 func status(
+	ctx context.Context,
 	_ []string,
 	_ flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
 	services *services.Services,
 	_ *flowkit.State,
 ) (command.Result, error) {
-	accessNode, err := services.Status.Ping(globalFlags.Network)
+	accessNode, err := services.Status.Ping(ctx, globalFlags.Network)
 
 	return &Result{
 		network:    globalFlags.Network,
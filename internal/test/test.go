@@ -20,6 +20,7 @@ package test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -50,6 +51,7 @@ var TestCommand = &command.Command{
 }
 
 func run(
+	_ context.Context,
 	args []string,
 	readerWriter flowkit.ReaderWriter,
 	_ command.GlobalFlags,
@@ -19,6 +19,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -50,6 +51,7 @@ var DevWallet = &command.Command{
 }
 
 func wallet(
+	_ context.Context,
 	_ []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
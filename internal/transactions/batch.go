@@ -0,0 +1,473 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/output"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+	"github.com/onflow/flow-cli/pkg/flowkit/util"
+)
+
+type flagsBatch struct {
+	Signer            string `default:"emulator-account" flag:"signer" info:"Account name from configuration used to sign the transactions without signer roles in the manifest"`
+	GasLimit          uint64 `default:"1000" flag:"gas-limit" info:"Gas limit of the transactions without a gas limit in the manifest"`
+	Concurrency       int    `default:"1" flag:"concurrency" info:"Maximum number of transactions sent at the same time, each with a different proposer, by default the transactions are sent sequentially"`
+	ContinueOnFailure bool   `default:"false" flag:"continue-on-failure" info:"Continue sending the transactions after a transaction fails"`
+}
+
+var batchFlags = flagsBatch{}
+
+var BatchCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:   "batch <manifest filename>",
+		Short: "Send the transactions listed in a manifest file",
+		Args:  cobra.ExactArgs(1),
+		Example: `flow transactions batch fixtures.yaml --network testnet
+
+#send up to 5 transactions at the same time and don't stop on failures
+flow transactions batch fixtures.json --concurrency 5 --continue-on-failure`,
+	},
+	Flags: &batchFlags,
+	RunS:  batch,
+}
+
+// batchManifest lists the transactions sent by the batch command, in YAML or JSON format.
+type batchManifest struct {
+	Transactions []batchStep `json:"transactions" yaml:"transactions"`
+}
+
+// batchStep is a transaction of the manifest with its signer roles and expected outcome.
+//
+// The signer is the proposer, payer and authorizer unless the roles are set, and
+// the file path is relative to the manifest file.
+type batchStep struct {
+	Name        string      `json:"name" yaml:"name"`
+	File        string      `json:"file" yaml:"file"`
+	Args        []string    `json:"args" yaml:"args"`
+	ArgsJSON    string      `json:"argsJson" yaml:"argsJson"`
+	Signer      string      `json:"signer" yaml:"signer"`
+	Proposer    string      `json:"proposer" yaml:"proposer"`
+	Payer       string      `json:"payer" yaml:"payer"`
+	Authorizers []string    `json:"authorizers" yaml:"authorizers"`
+	GasLimit    uint64      `json:"gasLimit" yaml:"gasLimit"`
+	Expect      batchExpect `json:"expect" yaml:"expect"`
+}
+
+// batchExpect are the assertions on the transaction result, by default the transaction must succeed.
+type batchExpect struct {
+	Success *bool    `json:"success" yaml:"success"`
+	Events  []string `json:"events" yaml:"events"`
+}
+
+func batch(
+	ctx context.Context,
+	args []string,
+	readerWriter flowkit.ReaderWriter,
+	globalFlags command.GlobalFlags,
+	services *services.Services,
+	state *flowkit.State,
+) (command.Result, error) {
+	if batchFlags.Concurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be at least 1")
+	}
+
+	manifestFilename := args[0]
+	manifest, err := loadBatchManifest(readerWriter, manifestFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	// all the transactions are prepared before sending any, so manifest errors don't leave a partial batch
+	transactions := make([]*batchTransaction, len(manifest.Transactions))
+	for i, step := range manifest.Transactions {
+		transactions[i], err = step.prepare(readerWriter, state, filepath.Dir(manifestFilename))
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction %d in the manifest: %w", i+1, err)
+		}
+	}
+
+	// transactions proposed with the same key concurrently would get the same sequence number
+	if batchFlags.Concurrency > 1 {
+		err = checkProposalKeys(transactions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := sendBatch(
+		transactions,
+		batchFlags.Concurrency,
+		batchFlags.ContinueOnFailure,
+		func(tx *batchTransaction) *batchStepResult {
+			return tx.send(ctx, services, globalFlags.Network)
+		},
+	)
+
+	return &BatchResult{steps: results}, nil
+}
+
+// sendBatch sends the transactions in manifest order with at most concurrency transactions in flight.
+//
+// After a transaction fails the transactions which weren't sent yet are skipped, unless continueOnFailure is set.
+func sendBatch(
+	transactions []*batchTransaction,
+	concurrency int,
+	continueOnFailure bool,
+	send func(tx *batchTransaction) *batchStepResult,
+) []*batchStepResult {
+	results := make([]*batchStepResult, len(transactions))
+	limit := make(chan struct{}, concurrency)
+	var failed int32
+	var wg sync.WaitGroup
+
+	for i, tx := range transactions {
+		limit <- struct{}{}
+
+		if atomic.LoadInt32(&failed) > 0 && !continueOnFailure {
+			<-limit
+			results[i] = &batchStepResult{name: tx.name, skipped: true}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, tx *batchTransaction) {
+			defer wg.Done()
+			defer func() { <-limit }()
+
+			results[i] = send(tx)
+			if results[i].err != nil {
+				atomic.StoreInt32(&failed, 1)
+			}
+		}(i, tx)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// checkProposalKeys returns an error if two transactions are proposed with the same account key,
+// since the proposal key sequence number only allows one pending transaction per key.
+func checkProposalKeys(transactions []*batchTransaction) error {
+	proposedBy := make(map[string]string)
+	for _, tx := range transactions {
+		key := fmt.Sprintf("%s/%d", tx.proposer.Address(), tx.proposer.Key().Index())
+		if name, ok := proposedBy[key]; ok {
+			return fmt.Errorf(
+				"transactions %s and %s are proposed with the same key %d of account %s and can't be sent concurrently, use --concurrency 1 or different proposers",
+				name,
+				tx.name,
+				tx.proposer.Key().Index(),
+				tx.proposer.Address(),
+			)
+		}
+		proposedBy[key] = tx.name
+	}
+
+	return nil
+}
+
+// loadBatchManifest reads the manifest file, files with the .json extension are parsed as JSON and others as YAML.
+func loadBatchManifest(readerWriter flowkit.ReaderWriter, filename string) (*batchManifest, error) {
+	data, err := readerWriter.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error loading manifest file: %w", err)
+	}
+
+	var manifest batchManifest
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		err = json.Unmarshal(data, &manifest)
+	} else {
+		err = yaml.Unmarshal(data, &manifest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest file %s: %w", filename, err)
+	}
+
+	if len(manifest.Transactions) == 0 {
+		return nil, fmt.Errorf("manifest file %s has no transactions", filename)
+	}
+
+	return &manifest, nil
+}
+
+// batchTransaction is a manifest transaction with its code, arguments and signer accounts loaded.
+type batchTransaction struct {
+	name        string
+	filename    string
+	code        []byte
+	args        []cadence.Value
+	proposer    *flowkit.Account
+	authorizers []*flowkit.Account
+	payer       *flowkit.Account
+	gasLimit    uint64
+	expect      batchExpect
+}
+
+func (s batchStep) prepare(readerWriter flowkit.ReaderWriter, state *flowkit.State, dir string) (*batchTransaction, error) {
+	if s.File == "" {
+		return nil, fmt.Errorf("missing transaction file")
+	}
+
+	filename := s.File
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(dir, filename)
+	}
+
+	code, err := readerWriter.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error loading transaction file: %w", err)
+	}
+
+	var args []cadence.Value
+	if s.ArgsJSON != "" {
+		args, err = flowkit.ParseArguments(nil, s.ArgsJSON)
+	} else {
+		args, err = flowkit.ParseArgumentsWithoutType(filename, code, s.Args)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing transaction arguments: %w", err)
+	}
+
+	signerName := s.Signer
+	if signerName == "" {
+		signerName = batchFlags.Signer
+	}
+
+	signer, err := accountByName(state, signerName)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &batchTransaction{
+		name:        s.Name,
+		filename:    filename,
+		code:        code,
+		args:        args,
+		proposer:    signer,
+		authorizers: []*flowkit.Account{signer},
+		payer:       signer,
+		gasLimit:    s.GasLimit,
+		expect:      s.Expect,
+	}
+
+	if tx.name == "" {
+		tx.name = s.File
+	}
+
+	if tx.gasLimit == 0 {
+		tx.gasLimit = batchFlags.GasLimit
+	}
+
+	if s.Proposer != "" {
+		tx.proposer, err = accountByName(state, s.Proposer)
+		if err != nil {
+			return nil, fmt.Errorf("error loading proposer: %w", err)
+		}
+	}
+
+	if s.Payer != "" {
+		tx.payer, err = accountByName(state, s.Payer)
+		if err != nil {
+			return nil, fmt.Errorf("error loading payer: %w", err)
+		}
+	}
+
+	if len(s.Authorizers) > 0 {
+		tx.authorizers = make([]*flowkit.Account, len(s.Authorizers))
+		for i, name := range s.Authorizers {
+			tx.authorizers[i], err = accountByName(state, name)
+			if err != nil {
+				return nil, fmt.Errorf("error loading authorizer: %w", err)
+			}
+		}
+	}
+
+	return tx, nil
+}
+
+// send sends the transaction, waits for it to be sealed and checks the expected outcome.
+func (t *batchTransaction) send(ctx context.Context, services *services.Services, network string) *batchStepResult {
+	tx, result, err := services.Transactions.SendWithRoles(
+		ctx,
+		t.proposer,
+		t.authorizers,
+		t.payer,
+		t.code,
+		t.filename,
+		t.gasLimit,
+		0, // the gas limit is always set, so it's never estimated
+		t.args,
+		network,
+		flow.TransactionStatusSealed,
+	)
+	if err != nil {
+		return &batchStepResult{name: t.name, tx: tx, err: err}
+	}
+
+	return &batchStepResult{
+		name:   t.name,
+		tx:     tx,
+		result: result,
+		err:    t.expect.check(result),
+	}
+}
+
+// check returns an error if the transaction result doesn't match the expected outcome.
+func (e batchExpect) check(result *flow.TransactionResult) error {
+	success := e.Success == nil || *e.Success
+	if success && result.Error != nil {
+		return fmt.Errorf("transaction failed: %w", result.Error)
+	}
+	if !success && result.Error == nil {
+		return fmt.Errorf("transaction succeeded, but it was expected to fail")
+	}
+
+	for _, eventType := range e.Events {
+		emitted := false
+		for _, event := range result.Events {
+			if event.Type == eventType {
+				emitted = true
+				break
+			}
+		}
+
+		if !emitted {
+			return fmt.Errorf("expected event %s was not emitted", eventType)
+		}
+	}
+
+	return nil
+}
+
+type batchStepResult struct {
+	name    string
+	tx      *flow.Transaction
+	result  *flow.TransactionResult
+	err     error
+	skipped bool
+}
+
+func (r *batchStepResult) status() string {
+	switch {
+	case r.skipped:
+		return "skipped"
+	case r.err != nil:
+		return "failed"
+	default:
+		return "passed"
+	}
+}
+
+type BatchResult struct {
+	steps []*batchStepResult
+}
+
+// ExitCode is non-zero when any transaction failed, so a failing batch fails scripts and CI jobs.
+func (r *BatchResult) ExitCode() int {
+	for _, step := range r.steps {
+		if step.err != nil {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (r *BatchResult) JSON() interface{} {
+	result := make([]interface{}, 0, len(r.steps))
+	for _, step := range r.steps {
+		stepResult := map[string]interface{}{
+			"name":   step.name,
+			"status": step.status(),
+		}
+		if step.tx != nil {
+			stepResult["id"] = step.tx.ID().String()
+		}
+		if step.err != nil {
+			stepResult["error"] = step.err.Error()
+		}
+		result = append(result, stepResult)
+	}
+
+	return result
+}
+
+func (r *BatchResult) String() string {
+	var b bytes.Buffer
+	writer := util.CreateTabWriter(&b)
+
+	passed, failed, skipped := 0, 0, 0
+	_, _ = fmt.Fprintf(writer, "Name\tID\tStatus\tError\n")
+	for _, step := range r.steps {
+		id := "-"
+		if step.tx != nil {
+			id = step.tx.ID().String()
+		}
+
+		status := output.OkEmoji()
+		errorMessage := ""
+		switch step.status() {
+		case "skipped":
+			status = "skipped"
+			skipped++
+		case "failed":
+			status = output.ErrorEmoji()
+			errorMessage = strings.ReplaceAll(step.err.Error(), "\n", " ")
+			failed++
+		default:
+			passed++
+		}
+
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", step.name, id, status, errorMessage)
+	}
+
+	_, _ = fmt.Fprintf(writer, "\n%d passed, %d failed, %d skipped\n", passed, failed, skipped)
+
+	_ = writer.Flush()
+	return b.String()
+}
+
+func (r *BatchResult) Oneliner() string {
+	steps := make([]string, 0, len(r.steps))
+	for _, step := range r.steps {
+		id := ""
+		if step.tx != nil {
+			id = step.tx.ID().String()
+		}
+		steps = append(steps, fmt.Sprintf("%s: %s %s", step.name, id, step.status()))
+	}
+
+	return strings.Join(steps, ", ")
+}
@@ -0,0 +1,218 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/tests"
+)
+
+func setupBatch(t *testing.T) (*flowkit.State, flowkit.ReaderWriter) {
+	readerWriter := tests.ReaderWriter()
+	state, err := flowkit.Init(readerWriter, crypto.ECDSA_P256, crypto.SHA3_256)
+	require.NoError(t, err)
+	state.Accounts().AddOrUpdate(tests.Alice())
+	state.Accounts().AddOrUpdate(tests.Bob())
+
+	batchFlags = flagsBatch{Signer: "emulator-account", GasLimit: 1000, Concurrency: 1}
+
+	return state, &readerWriter
+}
+
+func TestBatchManifest(t *testing.T) {
+	t.Run("Parse YAML Manifest", func(t *testing.T) {
+		state, readerWriter := setupBatch(t)
+		_ = readerWriter.WriteFile("fixtures/simple.cdc", tests.TransactionSimple.Source, 0644)
+		_ = readerWriter.WriteFile("fixtures/batch.yaml", []byte(`
+transactions:
+  - file: simple.cdc
+  - name: roles
+    file: simple.cdc
+    proposer: Alice
+    payer: Bob
+    authorizers: [Alice, Bob]
+    gasLimit: 9999
+    expect:
+      success: false
+      events: [A.01.Token.Minted]
+`), 0644)
+
+		manifest, err := loadBatchManifest(readerWriter, "fixtures/batch.yaml")
+		require.NoError(t, err)
+		require.Len(t, manifest.Transactions, 2)
+
+		tx, err := manifest.Transactions[0].prepare(readerWriter, state, "fixtures")
+		require.NoError(t, err)
+		serviceAccount, _ := state.EmulatorServiceAccount()
+		assert.Equal(t, "simple.cdc", tx.name)
+		assert.Equal(t, tests.TransactionSimple.Source, tx.code)
+		assert.Equal(t, serviceAccount.Address(), tx.proposer.Address())
+		assert.Equal(t, serviceAccount.Address(), tx.payer.Address())
+		assert.Len(t, tx.authorizers, 1)
+		assert.Equal(t, uint64(1000), tx.gasLimit)
+
+		tx, err = manifest.Transactions[1].prepare(readerWriter, state, "fixtures")
+		require.NoError(t, err)
+		assert.Equal(t, "roles", tx.name)
+		assert.Equal(t, tests.Alice().Address(), tx.proposer.Address())
+		assert.Equal(t, tests.Bob().Address(), tx.payer.Address())
+		require.Len(t, tx.authorizers, 2)
+		assert.Equal(t, tests.Bob().Address(), tx.authorizers[1].Address())
+		assert.Equal(t, uint64(9999), tx.gasLimit)
+		assert.False(t, *tx.expect.Success)
+		assert.Equal(t, []string{"A.01.Token.Minted"}, tx.expect.Events)
+	})
+
+	t.Run("Parse JSON Manifest", func(t *testing.T) {
+		_, readerWriter := setupBatch(t)
+		_ = readerWriter.WriteFile("batch.json", []byte(`{"transactions": [{"file": "simple.cdc", "signer": "Alice"}]}`), 0644)
+
+		manifest, err := loadBatchManifest(readerWriter, "batch.json")
+		require.NoError(t, err)
+		require.Len(t, manifest.Transactions, 1)
+		assert.Equal(t, "Alice", manifest.Transactions[0].Signer)
+	})
+
+	t.Run("Fail Empty Manifest", func(t *testing.T) {
+		_, readerWriter := setupBatch(t)
+		_ = readerWriter.WriteFile("batch.yaml", []byte("transactions: []"), 0644)
+
+		_, err := loadBatchManifest(readerWriter, "batch.yaml")
+		assert.EqualError(t, err, "manifest file batch.yaml has no transactions")
+	})
+
+	t.Run("Fail Invalid Transactions", func(t *testing.T) {
+		state, readerWriter := setupBatch(t)
+
+		_, err := batchStep{}.prepare(readerWriter, state, "")
+		assert.EqualError(t, err, "missing transaction file")
+
+		_, err = batchStep{File: "missing.cdc"}.prepare(readerWriter, state, "")
+		assert.ErrorContains(t, err, "error loading transaction file")
+
+		_, err = batchStep{File: tests.TransactionSimple.Filename, Payer: "Charlie"}.prepare(readerWriter, state, "")
+		assert.ErrorContains(t, err, "error loading payer")
+	})
+}
+
+func TestBatchExpect(t *testing.T) {
+	failure := false
+	failed := &flow.TransactionResult{Error: fmt.Errorf("panic")}
+	succeeded := &flow.TransactionResult{Events: []flow.Event{{Type: "A.01.Token.Minted"}}}
+
+	assert.NoError(t, batchExpect{}.check(succeeded))
+	assert.EqualError(t, batchExpect{}.check(failed), "transaction failed: panic")
+	assert.NoError(t, batchExpect{Success: &failure}.check(failed))
+	assert.EqualError(
+		t,
+		batchExpect{Success: &failure}.check(succeeded),
+		"transaction succeeded, but it was expected to fail",
+	)
+	assert.NoError(t, batchExpect{Events: []string{"A.01.Token.Minted"}}.check(succeeded))
+	assert.EqualError(
+		t,
+		batchExpect{Events: []string{"A.01.Token.Burned"}}.check(succeeded),
+		"expected event A.01.Token.Burned was not emitted",
+	)
+}
+
+func batchTransactions(proposers ...*flowkit.Account) []*batchTransaction {
+	transactions := make([]*batchTransaction, len(proposers))
+	for i, proposer := range proposers {
+		transactions[i] = &batchTransaction{name: fmt.Sprintf("tx%d", i), proposer: proposer}
+	}
+	return transactions
+}
+
+func TestSendBatch(t *testing.T) {
+	t.Run("Skip After Failure", func(t *testing.T) {
+		transactions := batchTransactions(tests.Alice(), tests.Alice(), tests.Alice())
+
+		results := sendBatch(transactions, 1, false, func(tx *batchTransaction) *batchStepResult {
+			if tx.name == "tx1" {
+				return &batchStepResult{name: tx.name, err: fmt.Errorf("failed")}
+			}
+			return &batchStepResult{name: tx.name}
+		})
+
+		assert.Equal(t, "passed", results[0].status())
+		assert.Equal(t, "failed", results[1].status())
+		assert.Equal(t, "skipped", results[2].status())
+		assert.Equal(t, 1, (&BatchResult{steps: results}).ExitCode())
+	})
+
+	t.Run("Continue On Failure", func(t *testing.T) {
+		transactions := batchTransactions(tests.Alice(), tests.Alice(), tests.Alice())
+
+		var sent int32
+		results := sendBatch(transactions, 1, true, func(tx *batchTransaction) *batchStepResult {
+			atomic.AddInt32(&sent, 1)
+			return &batchStepResult{name: tx.name, err: fmt.Errorf("failed")}
+		})
+
+		assert.Equal(t, int32(3), sent)
+		for _, result := range results {
+			assert.Equal(t, "failed", result.status())
+		}
+	})
+
+	t.Run("Limit Concurrency", func(t *testing.T) {
+		transactions := batchTransactions(tests.Alice(), tests.Alice(), tests.Alice(), tests.Alice(), tests.Alice())
+
+		var mu sync.Mutex
+		inFlight, maxInFlight := 0, 0
+		results := sendBatch(transactions, 2, false, func(tx *batchTransaction) *batchStepResult {
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			return &batchStepResult{name: tx.name}
+		})
+
+		assert.Equal(t, 2, maxInFlight)
+		assert.Len(t, results, 5)
+		assert.Equal(t, 0, (&BatchResult{steps: results}).ExitCode())
+	})
+
+	t.Run("Reject Shared Proposal Keys", func(t *testing.T) {
+		assert.NoError(t, checkProposalKeys(batchTransactions(tests.Alice(), tests.Bob())))
+
+		err := checkProposalKeys(batchTransactions(tests.Alice(), tests.Bob(), tests.Alice()))
+		assert.ErrorContains(t, err, "transactions tx0 and tx2 are proposed with the same key 0 of account 0000000000000001")
+	})
+}
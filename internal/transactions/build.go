@@ -19,6 +19,7 @@
 package transactions
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/cadence"
@@ -39,22 +40,36 @@ type flagsBuild struct {
 	Payer            string   `default:"emulator-account" flag:"payer" info:"transaction payer"`
 	Authorizer       []string `default:"emulator-account" flag:"authorizer" info:"transaction authorizer"`
 	GasLimit         uint64   `default:"1000" flag:"gas-limit" info:"transaction gas limit"`
+	EstimateGas      bool     `default:"false" flag:"estimate-gas" info:"Estimate the gas limit by executing the transaction on an emulator, instead of using the gas limit flag, transactions using the storage of their authorizers can't be estimated"`
+	GasMargin        uint64   `default:"20" flag:"gas-margin" info:"Safety margin added to the estimated gas limit, in percent"`
+	Bundle           bool     `default:"false" flag:"bundle" info:"Output the transaction as a JSON bundle instead of an RLP payload"`
+	Offline          bool     `default:"false" flag:"offline" info:"Build the transaction without network access, using the reference file or the reference block ID and sequence number flags"`
+	Reference        string   `default:"" flag:"reference" info:"Transaction reference file exported on an online machine with the transactions reference command"`
+	ReferenceBlockID string   `default:"" flag:"reference-block-id" info:"Reference block ID of an offline build"`
+	SequenceNumber   uint64   `default:"0" flag:"sequence-number" info:"Proposer key sequence number of an offline build"`
 }
 
 var buildFlags = flagsBuild{}
 
 var BuildCommand = &command.Command{
 	Cmd: &cobra.Command{
-		Use:     "build <code filename>  [<argument> <argument> ...]",
-		Short:   "Build an unsigned transaction",
-		Example: `flow transactions build ./transaction.cdc "Hello" --proposer alice --authorizer alice --payer bob`,
-		Args:    cobra.MinimumNArgs(1),
+		Use:   "build <code filename>  [<argument> <argument> ...]",
+		Short: "Build an unsigned transaction",
+		Example: `flow transactions build ./transaction.cdc "Hello" --proposer alice --authorizer alice --payer bob
+
+#build a JSON transaction bundle which can be reviewed before signing
+flow transactions build ./transaction.cdc "Hello" --proposer alice --authorizer alice --payer bob --bundle --save built.json
+
+#build a transaction on a machine without network access, from a reference exported on an online machine
+flow transactions build ./transaction.cdc "Hello" --proposer alice --authorizer alice --payer bob --offline --reference reference.json`,
+		Args: cobra.MinimumNArgs(1),
 	},
 	Flags: &buildFlags,
 	RunS:  build,
 }
 
 func build(
+	ctx context.Context,
 	args []string,
 	readerWriter flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
@@ -102,20 +117,59 @@ func build(
 		return nil, fmt.Errorf("error parsing transaction arguments: %w", err)
 	}
 
-	build, err := services.Transactions.Build(
-		proposer,
-		authorizers,
-		payer,
-		buildFlags.ProposerKeyIndex,
-		code,
-		filename,
-		buildFlags.GasLimit,
-		transactionArgs,
-		globalFlags.Network,
-		globalFlags.Yes,
-	)
-	if err != nil {
-		return nil, err
+	gasLimit := buildFlags.GasLimit
+	if buildFlags.EstimateGas {
+		gasLimit = 0 // estimated by the service
+	}
+
+	var build *flowkit.Transaction
+	if buildFlags.Offline {
+		reference, err := offlineReference(readerWriter, proposer)
+		if err != nil {
+			return nil, err
+		}
+
+		build, err = services.Transactions.BuildOffline(
+			reference,
+			authorizers,
+			payer,
+			code,
+			filename,
+			gasLimit,
+			transactionArgs,
+			globalFlags.Network,
+			globalFlags.Yes,
+		)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		build, err = services.Transactions.BuildWithContext(
+			ctx,
+			proposer,
+			authorizers,
+			payer,
+			buildFlags.ProposerKeyIndex,
+			code,
+			filename,
+			gasLimit,
+			buildFlags.GasMargin,
+			transactionArgs,
+			globalFlags.Network,
+			globalFlags.Yes,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if buildFlags.Bundle {
+		bundle, err := flowkit.NewTransactionBundle(build, state.Accounts())
+		if err != nil {
+			return nil, err
+		}
+
+		return &BundleResult{bundle: bundle}, nil
 	}
 
 	return &TransactionResult{
@@ -124,6 +178,55 @@ func build(
 	}, nil
 }
 
+// offlineReference returns the transaction reference from the reference file, or from the reference flags.
+func offlineReference(readerWriter flowkit.ReaderWriter, proposer flow.Address) (*flowkit.TransactionReference, error) {
+	if buildFlags.EstimateGas {
+		return nil, fmt.Errorf("gas can't be estimated when building offline, use the gas limit flag")
+	}
+
+	if buildFlags.Reference == "" {
+		if buildFlags.ReferenceBlockID == "" {
+			return nil, fmt.Errorf("offline build requires the reference file or the reference block ID and sequence number flags")
+		}
+
+		return &flowkit.TransactionReference{
+			ReferenceBlockID: buildFlags.ReferenceBlockID,
+			Proposer: flowkit.TransactionBundleProposer{
+				TransactionBundleAccount: flowkit.TransactionBundleAccount{Address: proposer.Hex()},
+				KeyIndex:                 buildFlags.ProposerKeyIndex,
+				SequenceNumber:           buildFlags.SequenceNumber,
+			},
+		}, nil
+	}
+
+	data, err := readerWriter.ReadFile(buildFlags.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("error loading transaction reference file: %w", err)
+	}
+
+	reference, err := flowkit.ParseTransactionReference(data)
+	if err != nil {
+		return nil, err
+	}
+
+	referenceProposer, err := reference.ProposerAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	if referenceProposer != proposer || reference.Proposer.KeyIndex != buildFlags.ProposerKeyIndex {
+		return nil, fmt.Errorf(
+			"transaction reference was exported for proposer %s key index %d, but the transaction proposer is %s key index %d",
+			referenceProposer,
+			reference.Proposer.KeyIndex,
+			proposer,
+			buildFlags.ProposerKeyIndex,
+		)
+	}
+
+	return reference, nil
+}
+
 func getAddress(address string, state *flowkit.State) (flow.Address, error) {
 	addr, valid := util.ParseAddress(address)
 	if valid {
@@ -19,6 +19,7 @@
 package transactions
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
@@ -47,6 +48,7 @@ var DecodeCommand = &command.Command{
 }
 
 func decode(
+	_ context.Context,
 	args []string,
 	readerWriter flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
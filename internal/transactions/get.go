@@ -19,6 +19,7 @@
 package transactions
 
 import (
+	"context"
 	"strings"
 
 	"github.com/onflow/flow-go-sdk"
@@ -32,6 +33,7 @@ import (
 
 type flagsGet struct {
 	Sealed  bool     `default:"true" flag:"sealed" info:"Wait for a sealed result"`
+	WaitFor string   `default:"" flag:"wait-for" info:"Wait for the transaction to reach the status. Valid values: finalized, executed, sealed. Overrides the sealed flag."`
 	Include []string `default:"" flag:"include" info:"Fields to include in the output. Valid values: signatures, code, payload."`
 	Exclude []string `default:"" flag:"exclude" info:"Fields to exclude from the output. Valid values: events."`
 }
@@ -51,6 +53,7 @@ var GetCommand = &command.Command{
 }
 
 func get(
+	ctx context.Context,
 	args []string,
 	_ flowkit.ReaderWriter,
 	_ command.GlobalFlags,
@@ -58,7 +61,18 @@ func get(
 ) (command.Result, error) {
 	id := flow.HexToID(strings.TrimPrefix(args[0], "0x"))
 
-	tx, result, err := services.Transactions.GetStatus(id, getFlags.Sealed)
+	waitFor := flow.TransactionStatusUnknown
+	if getFlags.WaitFor != "" {
+		var err error
+		waitFor, err = parseWaitFor(getFlags.WaitFor)
+		if err != nil {
+			return nil, err
+		}
+	} else if getFlags.Sealed {
+		waitFor = flow.TransactionStatusSealed
+	}
+
+	tx, result, err := services.Transactions.GetStatusWithContext(ctx, id, waitFor)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,208 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/output"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+	"github.com/onflow/flow-cli/pkg/flowkit/util"
+)
+
+type flagsHistory struct {
+	Network string `default:"" flag:"network-filter" info:"Only list the transactions sent on the network"`
+	Account string `default:"" flag:"account" info:"Only list the transactions of the account name or address"`
+	Since   string `default:"" flag:"since" info:"Only list the transactions sent since the date (YYYY-MM-DD) or time (RFC3339)"`
+	Until   string `default:"" flag:"until" info:"Only list the transactions sent until the date (YYYY-MM-DD) or time (RFC3339)"`
+	Status  string `default:"" flag:"status" info:"Only list the transactions with the status. Valid values: pending, finalized, executed, sealed, expired, failed"`
+	Refresh bool   `default:"false" flag:"refresh" info:"Fetch the latest status of the transactions sent on the current network which are not sealed or expired"`
+}
+
+var historyFlags = flagsHistory{}
+
+var HistoryCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:   "history",
+		Short: "List the transactions sent from the project",
+		Example: `#list the failed transactions of alice sent on testnet
+flow transactions history --network-filter testnet --account alice --status failed
+
+#fetch the latest status of the pending transactions sent on testnet
+flow transactions history --network testnet --refresh`,
+		Args: cobra.NoArgs,
+	},
+	Flags: &historyFlags,
+	RunS:  history,
+}
+
+func history(
+	ctx context.Context,
+	_ []string,
+	_ flowkit.ReaderWriter,
+	globalFlags command.GlobalFlags,
+	srv *services.Services,
+	state *flowkit.State,
+) (command.Result, error) {
+	filter, err := parseHistoryFilter(state)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionHistory := flowkit.NewTransactionHistory(afero.NewOsFs(), command.TransactionHistoryPath(globalFlags.ConfigPaths))
+
+	if historyFlags.Refresh {
+		network := command.HistoryNetwork(globalFlags.Host, globalFlags.Network)
+		pending, err := transactionHistory.Search(flowkit.TransactionHistoryFilter{Network: network})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range pending {
+			if entry.Final() {
+				continue
+			}
+
+			// the history gateway updates the entry with the fetched result
+			_, _, err := srv.Transactions.GetStatusWithContext(ctx, flow.HexToID(entry.ID), flow.TransactionStatusUnknown)
+			if err != nil {
+				return nil, fmt.Errorf("failed to refresh transaction %s: %w", entry.ID, err)
+			}
+		}
+	}
+
+	entries, err := transactionHistory.Search(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HistoryResult{entries: entries}, nil
+}
+
+// parseHistoryFilter creates the history filter from the flags, account names are resolved from the configuration.
+func parseHistoryFilter(state *flowkit.State) (flowkit.TransactionHistoryFilter, error) {
+	filter := flowkit.TransactionHistoryFilter{
+		Network: historyFlags.Network,
+		Status:  strings.ToLower(historyFlags.Status),
+	}
+
+	if historyFlags.Account != "" {
+		account, err := state.Accounts().ByName(historyFlags.Account)
+		if err == nil {
+			filter.Account = account.Address().Hex()
+		} else {
+			address := flow.HexToAddress(historyFlags.Account)
+			if address == flow.EmptyAddress {
+				return filter, fmt.Errorf("account %s is not an account name or address", historyFlags.Account)
+			}
+			filter.Account = address.Hex()
+		}
+	}
+
+	var err error
+	if historyFlags.Since != "" {
+		filter.Since, err = parseHistoryTime(historyFlags.Since, false)
+		if err != nil {
+			return filter, err
+		}
+	}
+	if historyFlags.Until != "" {
+		filter.Until, err = parseHistoryTime(historyFlags.Until, true)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
+
+// parseHistoryTime parses a date or a time, a date is the end of the day when used as the upper bound.
+func parseHistoryTime(value string, endOfDay bool) (time.Time, error) {
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err == nil {
+		if endOfDay {
+			return date.Add(24*time.Hour - time.Nanosecond), nil
+		}
+		return date, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %s, use YYYY-MM-DD or RFC3339", value)
+	}
+
+	return t, nil
+}
+
+type HistoryResult struct {
+	entries []*flowkit.TransactionHistoryEntry
+}
+
+func (r *HistoryResult) JSON() interface{} {
+	return r.entries
+}
+
+func (r *HistoryResult) String() string {
+	if len(r.entries) == 0 {
+		return "No transactions found in the history"
+	}
+
+	var b bytes.Buffer
+	writer := util.CreateTabWriter(&b)
+
+	_, _ = fmt.Fprintf(writer, "Sent At\tNetwork\tID\tPayer\tStatus\n")
+	for _, entry := range r.entries {
+		status := entry.Status
+		if entry.Error != "" {
+			status = fmt.Sprintf("%s %s (failed)", output.ErrorEmoji(), entry.Status)
+		}
+
+		_, _ = fmt.Fprintf(
+			writer,
+			"%s\t%s\t%s\t0x%s\t%s\n",
+			entry.SentAt.Local().Format(time.RFC3339),
+			entry.Network,
+			entry.ID,
+			entry.Payer,
+			status,
+		)
+	}
+
+	_ = writer.Flush()
+	return b.String()
+}
+
+func (r *HistoryResult) Oneliner() string {
+	ids := make([]string, 0, len(r.entries))
+	for _, entry := range r.entries {
+		ids = append(ids, fmt.Sprintf("%s (%s)", entry.ID, entry.Status))
+	}
+
+	return strings.Join(ids, ", ")
+}
@@ -0,0 +1,98 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+)
+
+type flagsReference struct {
+	Proposer         string `default:"emulator-account" flag:"proposer" info:"transaction proposer"`
+	ProposerKeyIndex int    `default:"0" flag:"proposer-key-index" info:"proposer key index"`
+}
+
+var referenceFlags = flagsReference{}
+
+var ReferenceCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:   "reference",
+		Short: "Export the reference block and proposer sequence number to build a transaction offline",
+		Example: `#export the reference on an online machine and build the transaction with it on an offline machine
+flow transactions reference --proposer alice --network mainnet --save reference.json
+flow transactions build ./transaction.cdc --proposer alice --authorizer alice --payer alice --offline --reference reference.json --network mainnet`,
+		Args: cobra.NoArgs,
+	},
+	Flags: &referenceFlags,
+	RunS:  reference,
+}
+
+func reference(
+	ctx context.Context,
+	_ []string,
+	_ flowkit.ReaderWriter,
+	_ command.GlobalFlags,
+	services *services.Services,
+	state *flowkit.State,
+) (command.Result, error) {
+	proposer, err := getAddress(referenceFlags.Proposer, state)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := services.Transactions.Reference(ctx, proposer, referenceFlags.ProposerKeyIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReferenceResult{reference: ref}, nil
+}
+
+// ReferenceResult outputs the transaction reference as JSON, so it can be saved to the reference file.
+type ReferenceResult struct {
+	reference *flowkit.TransactionReference
+}
+
+func (r *ReferenceResult) JSON() interface{} {
+	return r.reference
+}
+
+func (r *ReferenceResult) String() string {
+	b, err := r.reference.MarshalIndent()
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(b)
+}
+
+func (r *ReferenceResult) Oneliner() string {
+	b, err := json.Marshal(r.reference)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(b)
+}
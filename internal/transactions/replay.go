@@ -0,0 +1,183 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/output"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+	"github.com/onflow/flow-cli/pkg/flowkit/util"
+)
+
+var ReplayCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:   "replay <tx_id>",
+		Short: "Replay a transaction on an in-process emulator and compare the result",
+		Example: `#replay a failed testnet transaction with its Cadence logs
+flow transactions replay 07a8...b433 --network testnet`,
+		Args: cobra.ExactArgs(1),
+	},
+	Flags: &struct{}{},
+	Run:   replay,
+}
+
+func replay(
+	ctx context.Context,
+	args []string,
+	_ flowkit.ReaderWriter,
+	_ command.GlobalFlags,
+	services *services.Services,
+) (command.Result, error) {
+	id := flow.HexToID(strings.TrimPrefix(args[0], "0x"))
+
+	result, err := services.Transactions.Replay(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReplayResult{replay: result}, nil
+}
+
+type ReplayResult struct {
+	replay *services.TransactionReplay
+}
+
+func replayEvents(events []flow.Event) []string {
+	eventTypes := make([]string, 0, len(events))
+	for _, event := range events {
+		eventTypes = append(eventTypes, event.Type)
+	}
+	return eventTypes
+}
+
+func replayError(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func (r *ReplayResult) JSON() interface{} {
+	differences := make([]interface{}, 0)
+	for _, d := range r.replay.Differences() {
+		differences = append(differences, map[string]interface{}{
+			"field":    d.Field,
+			"result":   d.Result,
+			"replayed": d.Replayed,
+		})
+	}
+
+	return map[string]interface{}{
+		"id": r.replay.Transaction.ID().String(),
+		"result": map[string]interface{}{
+			"status": services.ReplayStatus(r.replay.Result.Error),
+			"error":  replayError(r.replay.Result.Error),
+			"events": replayEvents(r.replay.Result.Events),
+		},
+		"replayed": map[string]interface{}{
+			"status":          services.ReplayStatus(r.replay.Replayed.Error),
+			"error":           replayError(r.replay.Replayed.Error),
+			"events":          replayEvents(r.replay.Replayed.Events),
+			"logs":            r.replay.Replayed.Logs,
+			"computationUsed": r.replay.Replayed.ComputationUsed,
+		},
+		"differences": differences,
+	}
+}
+
+func (r *ReplayResult) String() string {
+	var b bytes.Buffer
+	writer := util.CreateTabWriter(&b)
+
+	statusBadge := func(err error) string {
+		if err != nil {
+			return output.ErrorEmoji()
+		}
+		return output.OkEmoji()
+	}
+
+	_, _ = fmt.Fprintf(writer, "ID\t%s\n", r.replay.Transaction.ID())
+	_, _ = fmt.Fprintf(writer, "Network Result\t%s %s\n", statusBadge(r.replay.Result.Error), services.ReplayStatus(r.replay.Result.Error))
+	_, _ = fmt.Fprintf(writer, "Replayed Result\t%s %s\n", statusBadge(r.replay.Replayed.Error), services.ReplayStatus(r.replay.Replayed.Error))
+	_, _ = fmt.Fprintf(writer, "Computation Used\t%d\n", r.replay.Replayed.ComputationUsed)
+
+	if r.replay.Result.Error != nil {
+		_, _ = fmt.Fprintf(writer, "\n%s Network Error\n%s\n", output.ErrorEmoji(), r.replay.Result.Error)
+	}
+	if r.replay.Replayed.Error != nil {
+		_, _ = fmt.Fprintf(writer, "\n%s Replayed Error\n%s\n", output.ErrorEmoji(), r.replay.Replayed.Error)
+	}
+
+	if len(r.replay.Replayed.Logs) == 0 {
+		_, _ = fmt.Fprintf(writer, "\nNo Cadence Logs\n")
+	} else {
+		_, _ = fmt.Fprintf(writer, "\nCadence Logs:\n")
+		for _, log := range r.replay.Replayed.Logs {
+			_, _ = fmt.Fprintf(writer, "    %s\n", log)
+		}
+	}
+
+	resultEvents := replayEvents(r.replay.Result.Events)
+	replayedEvents := replayEvents(r.replay.Replayed.Events)
+	if len(resultEvents) > 0 || len(replayedEvents) > 0 {
+		_, _ = fmt.Fprintf(writer, "\nEvents:\tNetwork\tReplayed\n")
+		for i := 0; i < len(resultEvents) || i < len(replayedEvents); i++ {
+			var result, replayed string
+			if i < len(resultEvents) {
+				result = resultEvents[i]
+			}
+			if i < len(replayedEvents) {
+				replayed = replayedEvents[i]
+			}
+			_, _ = fmt.Fprintf(writer, "    %d\t%s\t%s\n", i, result, replayed)
+		}
+	}
+
+	differences := r.replay.Differences()
+	if len(differences) == 0 {
+		_, _ = fmt.Fprintf(writer, "\n%s The replayed result matches the network result\n", output.OkEmoji())
+	} else {
+		_, _ = fmt.Fprintf(writer, "\nDifferences:\tNetwork\tReplayed\n")
+		for _, d := range differences {
+			_, _ = fmt.Fprintf(writer, "    %s\t%s\t%s\n", d.Field, d.Result, d.Replayed)
+		}
+	}
+
+	_ = writer.Flush()
+	return b.String()
+}
+
+func (r *ReplayResult) Oneliner() string {
+	return fmt.Sprintf(
+		"ID: %s, Network Result: %s, Replayed Result: %s, Differences: %d",
+		r.replay.Transaction.ID(),
+		services.ReplayStatus(r.replay.Result.Error),
+		services.ReplayStatus(r.replay.Replayed.Error),
+		len(r.replay.Differences()),
+	)
+}
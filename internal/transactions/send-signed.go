@@ -19,6 +19,7 @@
 package transactions
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
@@ -32,6 +33,7 @@ import (
 type flagsSendSigned struct {
 	Include []string `default:"" flag:"include" info:"Fields to include in the output. Valid values: signatures, code, payload."`
 	Exclude []string `default:"" flag:"exclude" info:"Fields to exclude from the output (events)"`
+	WaitFor string   `default:"sealed" flag:"wait-for" info:"Wait for the transaction to reach the status. Valid values: finalized, executed, sealed"`
 }
 
 var sendSignedFlags = flagsSendSigned{}
@@ -48,6 +50,7 @@ var SendSignedCommand = &command.Command{
 }
 
 func sendSigned(
+	ctx context.Context,
 	args []string,
 	readerWriter flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
@@ -61,7 +64,12 @@ func sendSigned(
 		return nil, fmt.Errorf("error loading transaction payload: %w", err)
 	}
 
-	tx, result, err := services.Transactions.SendSigned(code, globalFlags.Yes)
+	waitFor, err := parseWaitFor(sendSignedFlags.WaitFor)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, result, err := services.Transactions.SendSignedWithContext(ctx, code, globalFlags.Yes, waitFor)
 	if err != nil {
 		return nil, err
 	}
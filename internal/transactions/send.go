@@ -19,10 +19,9 @@
 package transactions
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/onflow/flow-cli/pkg/flowkit/config"
-
 	"github.com/spf13/cobra"
 
 	"github.com/onflow/cadence"
@@ -33,28 +32,41 @@ import (
 )
 
 type flagsSend struct {
-	ArgsJSON string   `default:"" flag:"args-json" info:"arguments in JSON-Cadence format"`
-	Arg      []string `default:"" flag:"arg" info:"⚠️  Deprecated: use command arguments"`
-	Signer   string   `default:"emulator-account" flag:"signer" info:"Account name from configuration used to sign the transaction"`
-	GasLimit uint64   `default:"1000" flag:"gas-limit" info:"transaction gas limit"`
-	Include  []string `default:"" flag:"include" info:"Fields to include in the output"`
-	Exclude  []string `default:"" flag:"exclude" info:"Fields to exclude from the output (events)"`
+	ArgsJSON    string   `default:"" flag:"args-json" info:"arguments in JSON-Cadence format"`
+	Arg         []string `default:"" flag:"arg" info:"⚠️  Deprecated: use command arguments"`
+	Signer      string   `default:"emulator-account" flag:"signer" info:"Account name from configuration used to sign the transaction"`
+	Proposer    string   `default:"" flag:"proposer" info:"Account name from configuration used as the proposer, defaults to the signer"`
+	Payer       string   `default:"" flag:"payer" info:"Account name from configuration used as the payer, defaults to the signer"`
+	Authorizer  []string `default:"" flag:"authorizer" info:"Account name from configuration used as an authorizer, repeat it for each authorizer, defaults to the signer"`
+	GasLimit    uint64   `default:"1000" flag:"gas-limit" info:"transaction gas limit"`
+	EstimateGas bool     `default:"false" flag:"estimate-gas" info:"Estimate the gas limit by executing the transaction on an emulator, instead of using the gas limit flag, transactions using the storage of their authorizers can't be estimated"`
+	GasMargin   uint64   `default:"20" flag:"gas-margin" info:"Safety margin added to the estimated gas limit, in percent"`
+	Include     []string `default:"" flag:"include" info:"Fields to include in the output"`
+	Exclude     []string `default:"" flag:"exclude" info:"Fields to exclude from the output (events)"`
+	WaitFor     string   `default:"sealed" flag:"wait-for" info:"Wait for the transaction to reach the status. Valid values: finalized, executed, sealed"`
 }
 
 var sendFlags = flagsSend{}
 
 var SendCommand = &command.Command{
 	Cmd: &cobra.Command{
-		Use:     "send <code filename> [<argument> <argument> ...]",
-		Short:   "Send a transaction",
-		Args:    cobra.MinimumNArgs(1),
-		Example: `flow transactions send tx.cdc "Hello world"`,
+		Use:   "send <code filename> [<argument> <argument> ...]",
+		Short: "Send a transaction",
+		Args:  cobra.MinimumNArgs(1),
+		Example: `flow transactions send tx.cdc "Hello world"
+
+#estimate the gas limit instead of using a fixed gas limit
+flow transactions send tx.cdc "Hello world" --estimate-gas --gas-margin 30
+
+#send a transaction with a separate proposer, payer and authorizers
+flow transactions send tx.cdc --proposer alice --payer bob --authorizer alice --authorizer charlie`,
 	},
 	Flags: &sendFlags,
 	RunS:  send,
 }
 
 func send(
+	ctx context.Context,
 	args []string,
 	readerWriter flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
@@ -63,13 +75,41 @@ func send(
 ) (command.Result, error) {
 	codeFilename := args[0]
 
-	transactionSigner := sendFlags.Signer
-	if sendFlags.Signer == config.DefaultEmulatorServiceAccountName { // use service account by default
-		transactionSigner = state.Config().Emulators.Default().ServiceAccount
+	// the signer is only the default for the roles which aren't provided
+	var signer *flowkit.Account
+	var err error
+	if sendFlags.Proposer == "" || sendFlags.Payer == "" || len(sendFlags.Authorizer) == 0 {
+		signer, err = accountByName(state, sendFlags.Signer)
+		if err != nil {
+			return nil, err
+		}
 	}
-	signer, err := state.Accounts().ByName(transactionSigner)
-	if err != nil {
-		return nil, err
+
+	proposer := signer
+	if sendFlags.Proposer != "" {
+		proposer, err = state.Accounts().ByName(sendFlags.Proposer)
+		if err != nil {
+			return nil, fmt.Errorf("error loading proposer: %w", err)
+		}
+	}
+
+	payer := signer
+	if sendFlags.Payer != "" {
+		payer, err = state.Accounts().ByName(sendFlags.Payer)
+		if err != nil {
+			return nil, fmt.Errorf("error loading payer: %w", err)
+		}
+	}
+
+	authorizers := []*flowkit.Account{signer}
+	if len(sendFlags.Authorizer) > 0 {
+		authorizers = make([]*flowkit.Account, len(sendFlags.Authorizer))
+		for i, name := range sendFlags.Authorizer {
+			authorizers[i], err = state.Accounts().ByName(name)
+			if err != nil {
+				return nil, fmt.Errorf("error loading authorizer: %w", err)
+			}
+		}
 	}
 
 	code, err := readerWriter.ReadFile(codeFilename)
@@ -92,13 +132,28 @@ func send(
 		return nil, fmt.Errorf("error parsing transaction arguments: %w", err)
 	}
 
-	tx, result, err := services.Transactions.Send(
-		signer,
+	waitFor, err := parseWaitFor(sendFlags.WaitFor)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit := sendFlags.GasLimit
+	if sendFlags.EstimateGas {
+		gasLimit = 0 // estimated by the service
+	}
+
+	tx, result, err := services.Transactions.SendWithRoles(
+		ctx,
+		proposer,
+		authorizers,
+		payer,
 		code,
 		codeFilename,
-		sendFlags.GasLimit,
+		gasLimit,
+		sendFlags.GasMargin,
 		transactionArgs,
 		globalFlags.Network,
+		waitFor,
 	)
 
 	if err != nil {
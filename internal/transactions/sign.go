@@ -19,6 +19,7 @@
 package transactions
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"sort"
@@ -35,22 +36,32 @@ type flagsSign struct {
 	Signer        []string `default:"emulator-account" flag:"signer" info:"name of the account used to sign"`
 	Include       []string `default:"" flag:"include" info:"Fields to include in the output. Valid values: signatures, code, payload."`
 	FromRemoteUrl string   `default:"" flag:"from-remote-url" info:"server URL where RLP can be fetched, signed RLP will be posted back to remote URL."`
+	KeyIndex      int      `default:"-1" flag:"key-index" info:"Index of the signer account key used to sign, defaults to the account key in the configuration"`
+	Bundle        bool     `default:"false" flag:"bundle" info:"Output the signed transaction as a JSON bundle, the default if a bundle is signed"`
+	ShowWeights   bool     `default:"false" flag:"show-weights" info:"Fetch the accounts from the network to report the signature weight of each account"`
 }
 
 var signFlags = flagsSign{}
 
 var SignCommand = &command.Command{
 	Cmd: &cobra.Command{
-		Use:     "sign [<built transaction filename> | --from-remote-url <url>]",
-		Short:   "Sign built transaction",
-		Example: "flow transactions sign ./built.rlp --signer alice",
-		Args:    cobra.MaximumNArgs(1),
+		Use:   "sign [<built transaction filename> | --from-remote-url <url>]",
+		Short: "Sign built transaction",
+		Example: `flow transactions sign ./built.rlp --signer alice
+
+#add a partial signature with the key at index 2 of an account with multiple keys
+flow transactions sign ./built.rlp --signer treasury --key-index 2
+
+#sign a JSON transaction bundle and save the signed bundle
+flow transactions sign ./built.json --signer alice --save signed.json`,
+		Args: cobra.MaximumNArgs(1),
 	},
 	Flags: &signFlags,
 	RunS:  sign,
 }
 
 func sign(
+	ctx context.Context,
 	args []string,
 	readerWriter flowkit.ReaderWriter,
 	globalFlags command.GlobalFlags,
@@ -89,6 +100,11 @@ func sign(
 	if err != nil {
 		return nil, err
 	}
+	isBundle := flowkit.IsTransactionBundle(payload)
+
+	if signFlags.KeyIndex >= 0 && len(signFlags.Signer) > 1 {
+		return nil, fmt.Errorf("--key-index can only be used with a single signer")
+	}
 
 	//validate all signers
 	for _, signerName := range signFlags.Signer {
@@ -105,7 +121,11 @@ func sign(
 	})
 
 	for _, signer := range signers {
-		signed, err = services.Transactions.Sign(signer, payload, globalFlags.Yes)
+		if signFlags.KeyIndex >= 0 {
+			signed, err = services.Transactions.SignWithKey(signer, signFlags.KeyIndex, payload, globalFlags.Yes)
+		} else {
+			signed, err = services.Transactions.Sign(signer, payload, globalFlags.Yes)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -122,8 +142,28 @@ func sign(
 		fmt.Printf("%s Signed RLP Posted successfully\n", output.SuccessEmoji())
 	}
 
-	return &TransactionResult{
+	// a signed bundle is output as a bundle, so it can be passed along to the next signer
+	if signFlags.Bundle || isBundle {
+		bundle, err := flowkit.NewTransactionBundle(signed, state.Accounts())
+		if err != nil {
+			return nil, err
+		}
+
+		return &BundleResult{bundle: bundle}, nil
+	}
+
+	result := &TransactionResult{
 		tx:      signed.FlowTransaction(),
 		include: signFlags.Include,
-	}, nil
+	}
+
+	// signing doesn't require network access, so the weights are only fetched when requested
+	if signFlags.ShowWeights {
+		result.weights, err = services.Transactions.SignatureWeights(ctx, signed)
+		if err != nil {
+			services.Logger.Info(fmt.Sprintf("⚠️  Failed to get the signature weights: %s", err))
+		}
+	}
+
+	return result, nil
 }
@@ -0,0 +1,175 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/output"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
+)
+
+type flagsSigningServer struct {
+	Listen    string `default:"127.0.0.1:8702" flag:"listen" info:"Address the signing server listens on, use 0.0.0.0:<port> to serve other machines on the network"`
+	PublicURL string `default:"" flag:"public-url" info:"Base URL co-signers reach the server at, e.g. http://192.168.1.10:8702, defaults to the listen address or the LAN IP when listening on all interfaces"`
+}
+
+var signingServerFlags = flagsSigningServer{}
+
+var SigningServerCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:   "signing-server [<built transaction filename> ...]",
+		Short: "Host transaction payloads for co-signers to sign them with --from-remote-url",
+		Example: `#host a built transaction, each co-signer signs it with the printed URL
+flow transactions signing-server built.rlp --listen 0.0.0.0:8702
+flow transactions sign --from-remote-url http://192.168.1.10:8702/payloads/<id> --signer alice
+
+#send the transaction once all the signatures are collected
+curl http://192.168.1.10:8702/payloads/<id>/envelope > signed.rlp
+flow transactions send-signed signed.rlp`,
+	},
+	Flags: &signingServerFlags,
+	RunS:  signingServer,
+}
+
+func signingServer(
+	ctx context.Context,
+	args []string,
+	readerWriter flowkit.ReaderWriter,
+	_ command.GlobalFlags,
+	srv *services.Services,
+	_ *flowkit.State,
+) (command.Result, error) {
+	server := srv.Transactions.SigningServer()
+
+	listener, err := net.Listen("tcp", signingServerFlags.Listen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the signing server: %w", err)
+	}
+	baseURL, err := signingServerURL(listener.Addr(), signingServerFlags.PublicURL)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	for _, filename := range args {
+		payload, err := readerWriter.ReadFile(filename)
+		if err != nil {
+			listener.Close()
+			return nil, fmt.Errorf("failed to read partial transaction from %s: %w", filename, err)
+		}
+
+		tx, err := flowkit.NewTransactionFromPayload(payload)
+		if err != nil {
+			listener.Close()
+			return nil, err
+		}
+
+		id, err := server.Add(ctx, tx)
+		if err != nil {
+			listener.Close()
+			return nil, err
+		}
+
+		srv.Logger.Info(fmt.Sprintf("Payload from %s available at %s/%s", filename, baseURL, id))
+	}
+
+	httpServer := &http.Server{Handler: server}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(listener)
+	}()
+
+	srv.Logger.Info(fmt.Sprintf(
+		"%s Signing server listening on %s, new payloads can be posted to it, press Ctrl+C to stop",
+		output.SuccessEmoji(),
+		baseURL,
+	))
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return nil, httpServer.Shutdown(shutdownCtx)
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil, nil
+		}
+		return nil, err
+	}
+}
+
+// signingServerURL returns the payloads URL co-signers reach the server at.
+//
+// The public URL is used if provided, otherwise the listen address is used
+// with the LAN IP in place of an unspecified host like 0.0.0.0, which isn't reachable by other machines.
+func signingServerURL(addr net.Addr, publicURL string) (string, error) {
+	if publicURL != "" {
+		parsed, err := url.Parse(publicURL)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return "", fmt.Errorf("invalid public URL %s, it must include the scheme and host, e.g. http://192.168.1.10:8702", publicURL)
+		}
+		return strings.TrimSuffix(publicURL, "/") + services.SigningServerPath, nil
+	}
+
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok || !tcpAddr.IP.IsUnspecified() {
+		return fmt.Sprintf("http://%s%s", addr, services.SigningServerPath), nil
+	}
+
+	ip, err := lanIP()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the LAN IP, provide the URL co-signers reach the server at with --public-url: %w", err)
+	}
+
+	host := net.JoinHostPort(ip.String(), strconv.Itoa(tcpAddr.Port))
+	return fmt.Sprintf("http://%s%s", host, services.SigningServerPath), nil
+}
+
+// lanIP returns the first non loopback IPv4 address of the machine.
+func lanIP() (net.IP, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if ip := ipNet.IP.To4(); ip != nil {
+			return ip, nil
+		}
+	}
+
+	return nil, errors.New("no network interface with an IPv4 address found")
+}
@@ -0,0 +1,57 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningServerURL(t *testing.T) {
+	t.Run("Listen Address", func(t *testing.T) {
+		url, err := signingServerURL(&net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8702}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "http://127.0.0.1:8702/payloads", url)
+	})
+
+	t.Run("Public URL", func(t *testing.T) {
+		url, err := signingServerURL(&net.TCPAddr{IP: net.IPv4zero, Port: 8702}, "https://signing.example.com/")
+		require.NoError(t, err)
+		assert.Equal(t, "https://signing.example.com/payloads", url)
+	})
+
+	t.Run("Invalid Public URL", func(t *testing.T) {
+		_, err := signingServerURL(&net.TCPAddr{IP: net.IPv4zero, Port: 8702}, "192.168.1.10:8702")
+		assert.Error(t, err)
+	})
+
+	t.Run("Unspecified Address", func(t *testing.T) {
+		ip, err := lanIP()
+		if err != nil {
+			t.Skip("no LAN IP available")
+		}
+
+		url, err := signingServerURL(&net.TCPAddr{IP: net.IPv4zero, Port: 8702}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "http://"+ip.String()+":8702/payloads", url)
+	})
+}
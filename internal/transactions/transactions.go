@@ -22,10 +22,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/onflow/flow-cli/internal/command"
 	"github.com/onflow/flow-cli/internal/events"
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/config"
 	"github.com/onflow/flow-cli/pkg/flowkit/output"
+	"github.com/onflow/flow-cli/pkg/flowkit/services"
 	"github.com/onflow/flow-cli/pkg/flowkit/util"
 
 	"github.com/onflow/flow-go-sdk"
@@ -46,6 +50,36 @@ func init() {
 	BuildCommand.AddToParent(Cmd)
 	SendSignedCommand.AddToParent(Cmd)
 	DecodeCommand.AddToParent(Cmd)
+	ReferenceCommand.AddToParent(Cmd)
+	BatchCommand.AddToParent(Cmd)
+	SigningServerCommand.AddToParent(Cmd)
+	ReplayCommand.AddToParent(Cmd)
+	HistoryCommand.AddToParent(Cmd)
+}
+
+// accountByName returns the account from the configuration, the default emulator account name is the emulator service account.
+func accountByName(state *flowkit.State, name string) (*flowkit.Account, error) {
+	if name == config.DefaultEmulatorServiceAccountName {
+		name = state.Config().Emulators.Default().ServiceAccount
+	}
+
+	return state.Accounts().ByName(name)
+}
+
+// parseWaitFor returns the transaction status to wait for from the wait-for flag value.
+func parseWaitFor(waitFor string) (flow.TransactionStatus, error) {
+	switch waitFor {
+	case "finalized":
+		return flow.TransactionStatusFinalized, nil
+	case "executed":
+		return flow.TransactionStatusExecuted, nil
+	case "sealed":
+		return flow.TransactionStatusSealed, nil
+	default:
+		return flow.TransactionStatusUnknown, fmt.Errorf(
+			"invalid wait-for value %s, valid values: finalized, executed, sealed", waitFor,
+		)
+	}
 }
 
 type TransactionResult struct {
@@ -53,6 +87,7 @@ type TransactionResult struct {
 	tx      *flow.Transaction
 	include []string
 	exclude []string
+	weights []*services.SignatureWeight
 }
 
 func (r *TransactionResult) JSON() interface{} {
@@ -62,18 +97,36 @@ func (r *TransactionResult) JSON() interface{} {
 	result["authorizers"] = fmt.Sprintf("%s", r.tx.Authorizers)
 	result["payer"] = r.tx.Payer.String()
 
+	if len(r.weights) > 0 {
+		weights := make([]interface{}, 0, len(r.weights))
+		for _, w := range r.weights {
+			weights = append(weights, map[string]interface{}{
+				"address":  w.Address.String(),
+				"roles":    w.Roles,
+				"weight":   w.Weight,
+				"required": w.Required,
+				"complete": w.Complete(),
+			})
+		}
+		result["signatureWeights"] = weights
+	}
+
 	if r.result != nil {
 		result["status"] = r.result.Status.String()
 
 		txEvents := make([]interface{}, 0, len(r.result.Events))
 		for _, event := range r.result.Events {
-			txEvents = append(txEvents, map[string]interface{}{
+			txEvent := map[string]interface{}{
 				"index": event.EventIndex,
 				"type":  event.Type,
 				"values": json.RawMessage(
 					event.Payload,
 				),
-			})
+			}
+			if summary := events.CoreEventSummary(event); summary != "" {
+				txEvent["summary"] = summary
+			}
+			txEvents = append(txEvents, txEvent)
 		}
 		result["events"] = txEvents
 
@@ -144,6 +197,21 @@ func (r *TransactionResult) String() string {
 		_, _ = fmt.Fprintf(writer, "\nSignatures (minimized, use --include signatures)")
 	}
 
+	if len(r.weights) > 0 {
+		_, _ = fmt.Fprintf(writer, "\n\nSignature Weights:\n")
+		for _, w := range r.weights {
+			status := "missing signatures"
+			if w.Complete() {
+				status = output.OkEmoji()
+			}
+			_, _ = fmt.Fprintf(
+				writer,
+				"    %s (%s)\t%d/%d\t%s\n",
+				w.Address, strings.Join(w.Roles, ", "), w.Weight, w.Required, status,
+			)
+		}
+	}
+
 	if r.result != nil && !command.ContainsFlag(r.exclude, "events") {
 		e := events.EventResult{
 			Events: r.result.Events,
@@ -195,3 +263,30 @@ func (r *TransactionResult) Oneliner() string {
 
 	return result
 }
+
+// BundleResult outputs the transaction as a JSON transaction bundle.
+type BundleResult struct {
+	bundle *flowkit.TransactionBundle
+}
+
+func (r *BundleResult) JSON() interface{} {
+	return r.bundle
+}
+
+func (r *BundleResult) String() string {
+	b, err := r.bundle.MarshalIndent()
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(b)
+}
+
+func (r *BundleResult) Oneliner() string {
+	b, err := json.Marshal(r.bundle)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(b)
+}
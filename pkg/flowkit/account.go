@@ -33,6 +33,8 @@ type Account struct {
 	name    string
 	address flow.Address
 	key     AccountKey
+	// additionalKeys are the keys of a multi-key account other than the default key.
+	additionalKeys []AccountKey
 }
 
 // NewAccount creates an empty account with the provided name.
@@ -45,53 +47,39 @@ func NewAccount(name string) *Account {
 // NewAccountFromOnChainAccount creates a new flowkit account definition
 // that mirrors an already-existing on-chain Flow account.
 //
-// This function requires the on-chain account to have exactly one public key
-// with full signing weight (1000). This ensures that the user has complete
-// and sole control over the on-chain account.
+// The private key must match one of the on-chain account keys which is not revoked, the
+// account key is set with the index of the matching key. Accounts with multiple keys or
+// keys with partial weight are supported, in which case transactions must also be signed
+// with other keys of the account to reach the signature weight threshold.
 func NewAccountFromOnChainAccount(
 	name string,
 	onChainAccount *flow.Account,
 	privateKey crypto.PrivateKey,
 ) (*Account, error) {
-	if len(onChainAccount.Keys) != 1 {
-		return nil, fmt.Errorf(
-			"expected on-chain account to have exactly one key, but got %d keys",
-			len(onChainAccount.Keys),
-		)
-	}
-
-	accountKey := onChainAccount.Keys[0]
-
-	if accountKey.Weight != flow.AccountKeyWeightThreshold {
-		return nil, fmt.Errorf(
-			"expected on-chain account to have full signing weight (%d), but got weight of %d",
-			flow.AccountKeyWeightThreshold,
-			accountKey.Weight,
-		)
-	}
-
 	offChainPublicKey := privateKey.PublicKey()
-	onChainPublicKey := accountKey.PublicKey
 
-	if !offChainPublicKey.Equals(onChainPublicKey) {
-		return nil, fmt.Errorf(
-			"expected on-chain account public key to match (%s), but got %s",
-			offChainPublicKey.String(),
-			onChainPublicKey.String(),
-		)
-	}
+	for _, accountKey := range onChainAccount.Keys {
+		if accountKey.Revoked || !offChainPublicKey.Equals(accountKey.PublicKey) {
+			continue
+		}
 
-	account := NewAccount(name).
-		SetAddress(onChainAccount.Address).
-		SetKey(
-			NewHexAccountKeyFromPrivateKey(
-				accountKey.Index,
-				accountKey.HashAlgo,
-				privateKey,
-			),
-		)
+		account := NewAccount(name).
+			SetAddress(onChainAccount.Address).
+			SetKey(
+				NewHexAccountKeyFromPrivateKey(
+					accountKey.Index,
+					accountKey.HashAlgo,
+					privateKey,
+				),
+			)
+
+		return account, nil
+	}
 
-	return account, nil
+	return nil, fmt.Errorf(
+		"expected on-chain account to have a key matching the public key %s",
+		offChainPublicKey.String(),
+	)
 }
 
 // Address get account address.
@@ -109,6 +97,37 @@ func (a *Account) Key() AccountKey {
 	return a.key
 }
 
+// Keys get all the account keys, the default key is first.
+func (a *Account) Keys() []AccountKey {
+	if a.key == nil {
+		return a.additionalKeys
+	}
+
+	return append([]AccountKey{a.key}, a.additionalKeys...)
+}
+
+// KeyByIndex get the account key with the on-chain key index.
+func (a *Account) KeyByIndex(index int) (AccountKey, error) {
+	for _, key := range a.Keys() {
+		if key.Index() == index {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("account %s has no key with index %d", a.name, index)
+}
+
+// AddKey adds a key to the account, the first key added is the default key.
+func (a *Account) AddKey(key AccountKey) *Account {
+	if a.key == nil {
+		a.key = key
+		return a
+	}
+
+	a.additionalKeys = append(a.additionalKeys, key)
+	return a
+}
+
 // SetAddress sets the account address.
 func (a *Account) SetAddress(address flow.Address) *Account {
 	a.address = address
@@ -156,11 +175,22 @@ func fromConfig(account config.Account) (*Account, error) {
 		return nil, err
 	}
 
-	return &Account{
+	acc := &Account{
 		name:    account.Name,
 		address: account.Address,
 		key:     key,
-	}, nil
+	}
+
+	// the first of the keys is the default key
+	for i := 1; i < len(account.Keys); i++ {
+		additionalKey, err := NewAccountKey(account.Keys[i])
+		if err != nil {
+			return nil, err
+		}
+		acc.AddKey(additionalKey)
+	}
+
+	return acc, nil
 }
 
 func toConfig(account Account, accountLocations map[string]string) config.Account {
@@ -172,11 +202,19 @@ func toConfig(account Account, accountLocations map[string]string) config.Accoun
 		}
 	}
 
-	return config.Account{
+	conf := config.Account{
 		Name:    account.name,
 		Address: account.address,
 		Key:     account.key.ToConfig(),
 	}
+
+	if len(account.additionalKeys) > 0 {
+		for _, key := range account.Keys() {
+			conf.Keys = append(conf.Keys, key.ToConfig())
+		}
+	}
+
+	return conf
 }
 
 func generateEmulatorServiceAccount(sigAlgo crypto.SignatureAlgorithm, hashAlgo crypto.HashAlgorithm) (*Account, error) {
@@ -0,0 +1,227 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence"
+	tmpl "github.com/onflow/flow-core-contracts/lib/go/templates"
+)
+
+// Account storage domains of the listed paths.
+const (
+	StorageDomainStorage = "storage"
+	StorageDomainPublic  = "public"
+	StorageDomainPrivate = "private"
+)
+
+const accountStorageTemplate = `
+import FungibleToken from 0xFUNGIBLETOKENADDRESS
+
+pub struct StorageItem {
+	pub let domain: String
+	pub let path: String
+	pub let type: String
+	pub let target: String?
+	pub let balance: UFix64?
+
+	init(domain: String, path: String, type: String, target: String?, balance: UFix64?) {
+		self.domain = domain
+		self.path = path
+		self.type = type
+		self.target = target
+		self.balance = balance
+	}
+}
+
+pub struct Storage {
+	pub let used: UInt64
+	pub let capacity: UInt64
+	pub let items: [StorageItem]
+
+	init(used: UInt64, capacity: UInt64, items: [StorageItem]) {
+		self.used = used
+		self.capacity = capacity
+		self.items = items
+	}
+}
+
+pub fun main(address: Address): Storage {
+	let account = getAuthAccount(address)
+	let items: [StorageItem] = []
+
+	account.forEachStored(fun (path: StoragePath, type: Type): Bool {
+		var balance: UFix64? = nil
+		if type.isSubtype(of: Type<@FungibleToken.Vault>()) {
+			balance = account.borrow<&FungibleToken.Vault>(from: path)?.balance
+		}
+
+		items.append(StorageItem(domain: "storage", path: path.toString(), type: type.identifier, target: nil, balance: balance))
+		return true
+	})
+
+	account.forEachPublic(fun (path: PublicPath, type: Type): Bool {
+		let target = account.getLinkTarget(path)
+		items.append(StorageItem(domain: "public", path: path.toString(), type: type.identifier, target: target?.toString(), balance: nil))
+		return true
+	})
+
+	account.forEachPrivate(fun (path: PrivatePath, type: Type): Bool {
+		let target = account.getLinkTarget(path)
+		items.append(StorageItem(domain: "private", path: path.toString(), type: type.identifier, target: target?.toString(), balance: nil))
+		return true
+	})
+
+	return Storage(used: account.storageUsed, capacity: account.storageCapacity, items: items)
+}
+`
+
+// AccountStorage is the content of the account storage.
+type AccountStorage struct {
+	Used     uint64               `json:"used"`
+	Capacity uint64               `json:"capacity"`
+	Items    []AccountStorageItem `json:"items"`
+}
+
+// AccountStorageItem is a value stored at a storage path or a capability linked at a public or private path.
+type AccountStorageItem struct {
+	Domain string `json:"domain"`
+	Path   string `json:"path"`
+	Type   string `json:"type"`
+	// Target is the path the capability is linked to, empty for stored values.
+	Target string `json:"target,omitempty"`
+	// Balance is the balance of a fungible token vault, nil for other values.
+	Balance *cadence.UFix64 `json:"balance,omitempty"`
+}
+
+// NewAccountStorageScript returns a script listing the storage of the account passed as the argument,
+// the fungible token vaults are resolved with the contracts of the network environment.
+func NewAccountStorageScript(env tmpl.Environment) []byte {
+	return []byte(tmpl.ReplaceAddresses(accountStorageTemplate, env))
+}
+
+// NewAccountStorageFromValue parses the value returned by the account storage script.
+func NewAccountStorageFromValue(value cadence.Value) (*AccountStorage, error) {
+	storageValue, ok := value.(cadence.Struct)
+	if !ok {
+		return nil, fmt.Errorf("account storage must be a cadence struct")
+	}
+	fields, err := structFields(storageValue)
+	if err != nil {
+		return nil, fmt.Errorf("account storage %w", err)
+	}
+
+	used, ok := fields["used"].(cadence.UInt64)
+	if !ok {
+		return nil, fmt.Errorf("account storage used must be a cadence UInt64")
+	}
+	capacity, ok := fields["capacity"].(cadence.UInt64)
+	if !ok {
+		return nil, fmt.Errorf("account storage capacity must be a cadence UInt64")
+	}
+	itemValues, ok := fields["items"].(cadence.Array)
+	if !ok {
+		return nil, fmt.Errorf("account storage items must be a cadence array")
+	}
+
+	storage := &AccountStorage{
+		Used:     uint64(used),
+		Capacity: uint64(capacity),
+		Items:    make([]AccountStorageItem, 0, len(itemValues.Values)),
+	}
+
+	for _, v := range itemValues.Values {
+		itemValue, ok := v.(cadence.Struct)
+		if !ok {
+			return nil, fmt.Errorf("account storage items must be a cadence array of structs")
+		}
+		itemFields, err := structFields(itemValue)
+		if err != nil {
+			return nil, fmt.Errorf("account storage item %w", err)
+		}
+
+		item := AccountStorageItem{
+			Domain: optionalString(itemFields["domain"]),
+			Path:   optionalString(itemFields["path"]),
+			Type:   optionalString(itemFields["type"]),
+			Target: optionalString(itemFields["target"]),
+		}
+
+		if balance, ok := itemFields["balance"].(cadence.Optional); ok && balance.Value != nil {
+			value, ok := balance.Value.(cadence.UFix64)
+			if !ok {
+				return nil, fmt.Errorf("account storage balance must be a cadence UFix64")
+			}
+			item.Balance = &value
+		}
+
+		storage.Items = append(storage.Items, item)
+	}
+
+	return storage, nil
+}
+
+// Vaults returns the fungible token vaults in the storage.
+func (s *AccountStorage) Vaults() []AccountStorageItem {
+	vaults := make([]AccountStorageItem, 0)
+	for _, item := range s.Items {
+		if item.Balance != nil {
+			vaults = append(vaults, item)
+		}
+	}
+
+	return vaults
+}
+
+// structFields returns the values of the struct fields by their names, the struct must have its type with all the fields.
+func structFields(value cadence.Struct) (map[string]cadence.Value, error) {
+	if value.StructType == nil {
+		return nil, fmt.Errorf("struct must have a type")
+	}
+	if len(value.Fields) != len(value.StructType.Fields) {
+		return nil, fmt.Errorf(
+			"struct has %d fields, its type %s has %d fields",
+			len(value.Fields),
+			value.StructType.QualifiedIdentifier,
+			len(value.StructType.Fields),
+		)
+	}
+
+	fields := make(map[string]cadence.Value, len(value.Fields))
+	for i, field := range value.StructType.Fields {
+		fields[field.Identifier] = value.Fields[i]
+	}
+
+	return fields, nil
+}
+
+// optionalString returns the string of the value, or the empty string if it isn't a string or it's nil.
+func optionalString(value cadence.Value) string {
+	if optional, ok := value.(cadence.Optional); ok {
+		value = optional.Value
+	}
+
+	str, ok := value.(cadence.String)
+	if !ok {
+		return ""
+	}
+
+	return string(str)
+}
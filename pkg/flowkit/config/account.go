@@ -30,6 +30,8 @@ type Account struct {
 	Name    string
 	Address flow.Address
 	Key     AccountKey
+	// Keys are all the keys of an account with multiple keys, the first key is also the Key.
+	Keys []AccountKey
 
 	// Location is the configuration file containing this account.
 	//
@@ -75,14 +75,64 @@ func transformSimpleToConfig(accountName string, a simpleAccount) (*config.Accou
 
 // transformAdvancedToConfig transforms advanced internal account to config account.
 func transformAdvancedToConfig(accountName string, a advancedAccount) (*config.Account, error) {
-	sigAlgo := crypto.StringToSignatureAlgorithm(a.Key.SigAlgo)
-	hashAlgo := crypto.StringToHashAlgorithm(a.Key.HashAlgo)
+	address, err := transformAddress(a.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := transformAdvancedKeyToConfig(accountName, a.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config.Account{
+		Name:    accountName,
+		Address: address,
+		Key:     *key,
+	}, nil
+}
+
+// transformMultiKeyToConfig transforms multi-key internal account to config account.
+func transformMultiKeyToConfig(accountName string, a multiKeyAccount) (*config.Account, error) {
+	address, err := transformAddress(a.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]config.AccountKey, 0, len(a.Keys))
+	indexes := make(map[int]bool)
+	for _, k := range a.Keys {
+		key, err := transformAdvancedKeyToConfig(accountName, k)
+		if err != nil {
+			return nil, err
+		}
+
+		if indexes[key.Index] {
+			return nil, fmt.Errorf("duplicate key index %d on account %s", key.Index, accountName)
+		}
+		indexes[key.Index] = true
+
+		keys = append(keys, *key)
+	}
 
-	if a.Key.Type != config.KeyTypeHex && a.Key.Type != config.KeyTypeGoogleKMS && a.Key.Type != config.KeyTypeBip44 {
+	return &config.Account{
+		Name:    accountName,
+		Address: address,
+		Key:     keys[0],
+		Keys:    keys,
+	}, nil
+}
+
+// transformAdvancedKeyToConfig transforms advanced internal key to config account key.
+func transformAdvancedKeyToConfig(accountName string, k advanceKey) (*config.AccountKey, error) {
+	sigAlgo := crypto.StringToSignatureAlgorithm(k.SigAlgo)
+	hashAlgo := crypto.StringToHashAlgorithm(k.HashAlgo)
+
+	if k.Type != config.KeyTypeHex && k.Type != config.KeyTypeGoogleKMS && k.Type != config.KeyTypeBip44 {
 		return nil, fmt.Errorf("invalid key type for account %s", accountName)
 	}
 
-	if a.Key.ResourceID != "" && a.Key.PrivateKey != "" {
+	if k.ResourceID != "" && k.PrivateKey != "" {
 		return nil, fmt.Errorf("only provide value for private key or resource ID on account %s", accountName)
 	}
 
@@ -94,26 +144,21 @@ func transformAdvancedToConfig(accountName string, a advancedAccount) (*config.A
 		return nil, fmt.Errorf("invalid hash algorithm for account %s", accountName)
 	}
 
-	address, err := transformAddress(a.Address)
-	if err != nil {
-		return nil, err
-	}
-
 	key := config.AccountKey{
-		Type:     a.Key.Type,
-		Index:    a.Key.Index,
+		Type:     k.Type,
+		Index:    k.Index,
 		SigAlgo:  sigAlgo,
 		HashAlgo: hashAlgo,
 	}
 
-	switch a.Key.Type {
+	switch k.Type {
 	case config.KeyTypeHex:
-		if a.Key.PrivateKey == "" {
+		if k.PrivateKey == "" {
 			return nil, fmt.Errorf("missing private key value for hex key type on account %s", accountName)
 		}
 		pKey, err := crypto.DecodePrivateKeyHex(
 			sigAlgo,
-			strings.TrimPrefix(a.Key.PrivateKey, "0x"),
+			strings.TrimPrefix(k.PrivateKey, "0x"),
 		)
 		if err != nil {
 			return nil, err
@@ -121,27 +166,23 @@ func transformAdvancedToConfig(accountName string, a advancedAccount) (*config.A
 
 		key.PrivateKey = pKey
 	case config.KeyTypeBip44:
-		if a.Key.Mnemonic == "" {
+		if k.Mnemonic == "" {
 			return nil, fmt.Errorf("missing mnemonic value for bip44 key type on account %s", accountName)
 		}
-		key.Mnemonic = a.Key.Mnemonic
-		key.DerivationPath = a.Key.DerivationPath
+		key.Mnemonic = k.Mnemonic
+		key.DerivationPath = k.DerivationPath
 		if key.DerivationPath == "" {
 			key.DerivationPath = "m/44'/539'/0'/0/0"
 		}
 
 	case config.KeyTypeGoogleKMS:
-		if a.Key.ResourceID == "" {
+		if k.ResourceID == "" {
 			return nil, fmt.Errorf("missing resource ID value for key on account %s", accountName)
 		}
-		key.ResourceID = a.Key.ResourceID
+		key.ResourceID = k.ResourceID
 	}
 
-	return &config.Account{
-		Name:    accountName,
-		Address: address,
-		Key:     key,
-	}, nil
+	return &key, nil
 }
 
 // transformToConfig transforms json structures to config structure.
@@ -156,6 +197,11 @@ func (j jsonAccounts) transformToConfig() (config.Accounts, error) {
 			if err != nil {
 				return nil, err
 			}
+		} else if len(a.MultiKey.Keys) > 0 {
+			account, err = transformMultiKeyToConfig(accountName, a.MultiKey)
+			if err != nil {
+				return nil, err
+			}
 		} else { // advanced format
 			account, err = transformAdvancedToConfig(accountName, a.Advanced)
 			if err != nil {
@@ -176,6 +222,8 @@ func transformAccountsToJSON(accounts config.Accounts) jsonAccounts {
 	for _, a := range accounts {
 		if a.Location != "" {
 			jsonAccounts[a.Name] = transformFromFileAccountToJSON(a)
+		} else if len(a.Keys) > 1 {
+			jsonAccounts[a.Name] = transformMultiKeyAccountToJSON(a)
 		} else if isDefaultKeyFormat(a.Key) && !a.UseAdvanceFormat {
 			jsonAccounts[a.Name] = transformSimpleAccountToJSON(a)
 		} else {
@@ -212,6 +260,20 @@ func transformAdvancedAccountToJSON(a config.Account) account {
 	}
 }
 
+func transformMultiKeyAccountToJSON(a config.Account) account {
+	keys := make([]advanceKey, 0, len(a.Keys))
+	for _, key := range a.Keys {
+		keys = append(keys, transformAdvancedKeyToJSON(key))
+	}
+
+	return account{
+		MultiKey: multiKeyAccount{
+			Address: a.Address.String(),
+			Keys:    keys,
+		},
+	}
+}
+
 func transformAdvancedKeyToJSON(key config.AccountKey) advanceKey {
 	advancedKey := advanceKey{
 		Type:     key.Type,
@@ -244,6 +306,7 @@ type account struct {
 	FromFile fromFileAccount
 	Simple   simpleAccount
 	Advanced advancedAccount
+	MultiKey multiKeyAccount
 }
 
 type fromFileAccount struct {
@@ -260,6 +323,12 @@ type advancedAccount struct {
 	Key     advanceKey `json:"key"`
 }
 
+// multiKeyAccount is an account with multiple keys, e.g. used for multi-signature transactions.
+type multiKeyAccount struct {
+	Address string       `json:"address"`
+	Keys    []advanceKey `json:"keys"`
+}
+
 type advanceKey struct {
 	Type     config.KeyType `json:"type"`
 	Index    int            `json:"index"`
@@ -295,6 +364,7 @@ const (
 	advancedFormat       FormatType = 1
 	simpleFormatPre022   FormatType = 2 // pre v.022 format
 	advancedFormatPre022 FormatType = 3 // pre v.022 format
+	multiKeyFormat       FormatType = 4
 )
 
 func decideFormat(b []byte) (FormatType, error) {
@@ -305,9 +375,17 @@ func decideFormat(b []byte) (FormatType, error) {
 	}
 
 	if raw["keys"] != nil {
-		switch raw["keys"].(type) {
+		switch keys := raw["keys"].(type) {
 		case string:
 			return simpleFormatPre022, nil
+		case []interface{}:
+			// pre v0.22 keys have the private key in the context
+			for _, key := range keys {
+				if k, ok := key.(map[string]interface{}); ok && k["context"] != nil {
+					return advancedFormatPre022, nil
+				}
+			}
+			return multiKeyFormat, nil
 		default:
 			return advancedFormatPre022, nil
 		}
@@ -356,6 +434,14 @@ func (j *account) UnmarshalJSON(b []byte) error {
 		var advanced advancedAccount
 		err = json.Unmarshal(b, &advanced)
 		j.Advanced = advanced
+
+	case multiKeyFormat:
+		var multiKey multiKeyAccount
+		err = json.Unmarshal(b, &multiKey)
+		if err == nil && len(multiKey.Keys) == 0 {
+			err = fmt.Errorf("account keys must not be empty")
+		}
+		j.MultiKey = multiKey
 	}
 
 	return err
@@ -370,5 +456,9 @@ func (j account) MarshalJSON() ([]byte, error) {
 		return json.Marshal(j.Simple)
 	}
 
+	if len(j.MultiKey.Keys) > 0 {
+		return json.Marshal(j.MultiKey)
+	}
+
 	return json.Marshal(j.Advanced)
 }
@@ -438,6 +438,59 @@ func Test_SupportForOldFormatWithMultipleKeys(t *testing.T) {
 	assert.Equal(t, key.PrivateKey.String(), "0xdd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
 }
 
+func Test_ConfigAccountMultipleKeys(t *testing.T) {
+	b := []byte(`{"treasury":{"address":"3c1162386b0a245f","keys":[{"type":"hex","index":0,"signatureAlgorithm":"ECDSA_P256","hashAlgorithm":"SHA3_256","privateKey":"1272967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"},{"type":"google-kms","index":2,"signatureAlgorithm":"ECDSA_P256","hashAlgorithm":"SHA3_256","resourceID":"projects/flow/locations/us/keyRings/foo/bar/cryptoKeyVersions/1"}]}}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	conf, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+
+	account, err := conf.ByName("treasury")
+	assert.NoError(t, err)
+	assert.Len(t, account.Keys, 2)
+	assert.Equal(t, account.Keys[0], account.Key)
+	assert.Equal(t, 0, account.Keys[0].Index)
+	assert.Equal(t, "0x1272967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47", account.Keys[0].PrivateKey.String())
+	assert.Equal(t, 2, account.Keys[1].Index)
+	assert.Equal(t, config.KeyTypeGoogleKMS, account.Keys[1].Type)
+
+	j := transformAccountsToJSON(conf)
+	x, err := json.Marshal(j)
+	assert.NoError(t, err)
+	assert.Equal(t, string(b), string(x))
+}
+
+func Test_ConfigAccountMultipleKeysDuplicateIndex(t *testing.T) {
+	b := []byte(`{
+		"treasury": {
+			"address": "3c1162386b0a245f",
+			"keys": [{
+				"type": "hex",
+				"index": 1,
+				"signatureAlgorithm": "ECDSA_P256",
+				"hashAlgorithm": "SHA3_256",
+				"privateKey": "1272967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+			}, {
+				"type": "hex",
+				"index": 1,
+				"signatureAlgorithm": "ECDSA_P256",
+				"hashAlgorithm": "SHA3_256",
+				"privateKey": "2272967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+			}]
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	_, err = jsonAccounts.transformToConfig()
+	assert.EqualError(t, err, "duplicate key index 1 on account treasury")
+}
+
 func Test_ConfigInvalidKey(t *testing.T) {
 	b := []byte(`{
 		"test": {
@@ -21,6 +21,7 @@ package json
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/onflow/flow-cli/pkg/flowkit/config"
 	"github.com/onflow/flow-cli/pkg/flowkit/util"
@@ -33,16 +34,28 @@ func (j jsonNetworks) transformToConfig() (config.Networks, error) {
 	networks := make(config.Networks, 0)
 
 	for networkName, n := range j {
-		if n.Advanced.Key != "" && n.Advanced.Host != "" {
-			err := util.ValidateECDSAP256Pub(n.Advanced.Key)
-			if err != nil {
-				return nil, fmt.Errorf("invalid key %s for network with name %s", n.Advanced.Key, networkName)
+		if n.Advanced.Host != "" && (n.Advanced.Key != "" || n.Advanced.Retry != nil) {
+			if n.Advanced.Key != "" {
+				err := util.ValidateECDSAP256Pub(n.Advanced.Key)
+				if err != nil {
+					return nil, fmt.Errorf("invalid key %s for network with name %s", n.Advanced.Key, networkName)
+				}
+			}
+
+			var retry *config.Retry
+			if n.Advanced.Retry != nil {
+				r, err := n.Advanced.Retry.transformToConfig()
+				if err != nil {
+					return nil, fmt.Errorf("invalid retry policy for network with name %s: %w", networkName, err)
+				}
+				retry = &r
 			}
 
 			networks = append(networks, config.Network{
-				Name: networkName,
-				Host: n.Advanced.Host,
-				Key:  n.Advanced.Key,
+				Name:  networkName,
+				Host:  n.Advanced.Host,
+				Key:   n.Advanced.Key,
+				Retry: retry,
 			})
 		} else if n.Simple.Host != "" {
 			networks = append(networks, config.Network{
@@ -62,7 +75,7 @@ func transformNetworksToJSON(networks config.Networks) jsonNetworks {
 	jsonNetworks := jsonNetworks{}
 
 	for _, n := range networks {
-		if n.Key != "" {
+		if n.Key != "" || n.Retry != nil {
 			jsonNetworks[n.Name] = transformAdvancedNetworkToJSON(n)
 		} else {
 			jsonNetworks[n.Name] = transformSimpleNetworkToJSON(n)
@@ -81,10 +94,20 @@ func transformSimpleNetworkToJSON(n config.Network) jsonNetwork {
 }
 
 func transformAdvancedNetworkToJSON(n config.Network) jsonNetwork {
+	var retry *jsonRetry
+	if n.Retry != nil {
+		retry = &jsonRetry{
+			Attempts:       n.Retry.Attempts,
+			InitialBackoff: n.Retry.InitialBackoff.String(),
+			MaxBackoff:     n.Retry.MaxBackoff.String(),
+		}
+	}
+
 	return jsonNetwork{
 		Advanced: advancedNetwork{
-			Host: n.Host,
-			Key:  n.Key,
+			Host:  n.Host,
+			Key:   n.Key,
+			Retry: retry,
 		},
 	}
 }
@@ -99,8 +122,45 @@ type simpleNetwork struct {
 }
 
 type advancedNetwork struct {
-	Host string `json:"host"`
-	Key  string `json:"key"`
+	Host  string     `json:"host"`
+	Key   string     `json:"key,omitempty"`
+	Retry *jsonRetry `json:"retry,omitempty"`
+}
+
+type jsonRetry struct {
+	Attempts       int    `json:"attempts"`
+	InitialBackoff string `json:"initialBackoff,omitempty"`
+	MaxBackoff     string `json:"maxBackoff,omitempty"`
+}
+
+// transformToConfig transforms json retry policy to config structure, missing values use the defaults.
+func (j jsonRetry) transformToConfig() (config.Retry, error) {
+	retry := config.DefaultRetry()
+
+	if j.Attempts < 0 {
+		return retry, fmt.Errorf("attempts must not be negative")
+	}
+	if j.Attempts > 0 {
+		retry.Attempts = j.Attempts
+	}
+
+	if j.InitialBackoff != "" {
+		backoff, err := time.ParseDuration(j.InitialBackoff)
+		if err != nil {
+			return retry, fmt.Errorf("invalid initial backoff: %w", err)
+		}
+		retry.InitialBackoff = backoff
+	}
+
+	if j.MaxBackoff != "" {
+		backoff, err := time.ParseDuration(j.MaxBackoff)
+		if err != nil {
+			return retry, fmt.Errorf("invalid max backoff: %w", err)
+		}
+		retry.MaxBackoff = backoff
+	}
+
+	return retry, nil
 }
 
 func (j *jsonNetwork) UnmarshalJSON(b []byte) error {
@@ -117,6 +177,7 @@ func (j *jsonNetwork) UnmarshalJSON(b []byte) error {
 	if err == nil {
 		j.Advanced.Host = advanced.Host
 		j.Advanced.Key = advanced.Key
+		j.Advanced.Retry = advanced.Retry
 	}
 
 	return err
@@ -20,6 +20,7 @@ package json
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -153,3 +154,46 @@ func Test_TransformConfigAdvanced(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func Test_ConfigNetworkRetry(t *testing.T) {
+	t.Run("should parse retry policy with defaults", func(t *testing.T) {
+		b := []byte(`{"testnet":{"host":"access.testnet.nodes.onflow.org:9000","retry":{"attempts":3,"maxBackoff":"2s"}}}`)
+		var jsonNetworks jsonNetworks
+		err := json.Unmarshal(b, &jsonNetworks)
+		assert.NoError(t, err)
+
+		conf, err := jsonNetworks.transformToConfig()
+		assert.NoError(t, err)
+
+		testnet, err := conf.ByName("testnet")
+		assert.NoError(t, err)
+		assert.Equal(t, "access.testnet.nodes.onflow.org:9000", testnet.Host)
+		assert.Equal(t, "", testnet.Key)
+		assert.Equal(t, 3, testnet.Retry.Attempts)
+		assert.Equal(t, 500*time.Millisecond, testnet.Retry.InitialBackoff)
+		assert.Equal(t, 2*time.Second, testnet.Retry.MaxBackoff)
+	})
+
+	t.Run("should transform retry policy to json", func(t *testing.T) {
+		b := []byte(`{"testnet":{"host":"access.testnet.nodes.onflow.org:9000","retry":{"attempts":3,"initialBackoff":"1s","maxBackoff":"5s"}}}`)
+		var jsonNetworks jsonNetworks
+		err := json.Unmarshal(b, &jsonNetworks)
+		assert.NoError(t, err)
+
+		networks, err := jsonNetworks.transformToConfig()
+		assert.NoError(t, err)
+
+		x, _ := json.Marshal(transformNetworksToJSON(networks))
+		assert.Equal(t, string(b), string(x))
+	})
+
+	t.Run("should return error for invalid backoff", func(t *testing.T) {
+		b := []byte(`{"testnet":{"host":"access.testnet.nodes.onflow.org:9000","retry":{"attempts":3,"maxBackoff":"soon"}}}`)
+		var jsonNetworks jsonNetworks
+		err := json.Unmarshal(b, &jsonNetworks)
+		assert.NoError(t, err)
+
+		_, err = jsonNetworks.transformToConfig()
+		assert.Error(t, err)
+	})
+}
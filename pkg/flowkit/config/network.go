@@ -20,15 +20,33 @@ package config
 
 import (
 	"fmt"
+	"time"
 )
 
 type Networks []Network
 
 // Network defines the configuration for a Flow network.
 type Network struct {
-	Name string
-	Host string
-	Key  string
+	Name  string
+	Host  string
+	Key   string
+	Retry *Retry
+}
+
+// Retry defines how calls to the network access node are retried on transient errors.
+type Retry struct {
+	Attempts       int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetry get default retry policy.
+func DefaultRetry() Retry {
+	return Retry{
+		Attempts:       5,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+	}
 }
 
 // ByName get network by name.
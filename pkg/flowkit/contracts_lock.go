@@ -0,0 +1,135 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// DefaultContractsLockPath is the path of the lock file of the pulled contracts, relative to the project configuration.
+const DefaultContractsLockPath = "flow.lock.json"
+
+// LockedContract is a contract pulled from a network with the hash of its code at the time it was pulled.
+type LockedContract struct {
+	Name     string `json:"name"`
+	Network  string `json:"network"`
+	Address  string `json:"address"`
+	Source   string `json:"source"`
+	CodeHash string `json:"codeHash"`
+}
+
+// NewLockedContract creates a locked contract for the code deployed to the address on the network.
+func NewLockedContract(name string, network string, address flow.Address, source string, code []byte) LockedContract {
+	return LockedContract{
+		Name:     name,
+		Network:  network,
+		Address:  address.Hex(),
+		Source:   source,
+		CodeHash: ContractCodeHash(code),
+	}
+}
+
+// ContractCodeHash returns the hex encoded SHA2-256 hash of the contract code.
+func ContractCodeHash(code []byte) string {
+	hash := sha256.Sum256(code)
+	return hex.EncodeToString(hash[:])
+}
+
+// Matches checks if the code is the code the contract was locked with.
+func (c *LockedContract) Matches(code []byte) bool {
+	return c.CodeHash == ContractCodeHash(code)
+}
+
+// ContractsLock records the code hashes of the contracts pulled from networks,
+// so pulling them again is reproducible and updates of the deployed contracts are detected.
+type ContractsLock struct {
+	Contracts []LockedContract `json:"contracts"`
+}
+
+// ParseContractsLock parses the JSON encoded lock.
+func ParseContractsLock(data []byte) (*ContractsLock, error) {
+	var lock ContractsLock
+	err := json.Unmarshal(data, &lock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse contracts lock: %w", err)
+	}
+
+	for _, contract := range lock.Contracts {
+		if _, err := hexToAddress(contract.Address); err != nil {
+			return nil, fmt.Errorf("invalid address of contract %s in the contracts lock: %w", contract.Name, err)
+		}
+	}
+
+	return &lock, nil
+}
+
+// ByAddress returns the locked contract deployed to the address on the network, nil if it's not locked.
+func (l *ContractsLock) ByAddress(network string, address flow.Address, name string) *LockedContract {
+	for i, contract := range l.Contracts {
+		if contract.Network == network && contract.Name == name && contract.Address == address.Hex() {
+			return &l.Contracts[i]
+		}
+	}
+
+	return nil
+}
+
+// AddOrUpdate adds the contract or updates it if it's already locked.
+//
+// The contracts are kept sorted, so the lock doesn't change when the same contracts are pulled again.
+func (l *ContractsLock) AddOrUpdate(contract LockedContract) {
+	existing := l.ByAddress(contract.Network, flow.HexToAddress(contract.Address), contract.Name)
+	if existing != nil {
+		*existing = contract
+		return
+	}
+
+	l.Contracts = append(l.Contracts, contract)
+	sort.SliceStable(l.Contracts, func(i, j int) bool {
+		a, b := l.Contracts[i], l.Contracts[j]
+		if a.Network != b.Network {
+			return a.Network < b.Network
+		}
+		if a.Address != b.Address {
+			return a.Address < b.Address
+		}
+		return a.Name < b.Name
+	})
+}
+
+// MarshalIndent returns the lock as indented JSON.
+func (l *ContractsLock) MarshalIndent() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(l)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,74 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit_test
+
+import (
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/tests"
+)
+
+func TestContractsLock(t *testing.T) {
+	address := flow.HexToAddress("0ae53cb6e3f42a79")
+	code := tests.ContractSimple.Source
+
+	t.Run("Round Trip", func(t *testing.T) {
+		lock := &flowkit.ContractsLock{}
+		lock.AddOrUpdate(flowkit.NewLockedContract("Simple", "testnet", address, "./imports/Simple.cdc", code))
+		lock.AddOrUpdate(flowkit.NewLockedContract("Hello", "testnet", address, "./imports/Hello.cdc", tests.ContractHelloString.Source))
+		lock.AddOrUpdate(flowkit.NewLockedContract("Simple", "emulator", address, "./imports/Simple.cdc", code))
+
+		data, err := lock.MarshalIndent()
+		require.NoError(t, err)
+
+		parsed, err := flowkit.ParseContractsLock(data)
+		require.NoError(t, err)
+		assert.Equal(t, lock, parsed)
+
+		require.Len(t, parsed.Contracts, 3)
+		assert.Equal(t, "emulator", parsed.Contracts[0].Network)
+		assert.Equal(t, "Hello", parsed.Contracts[1].Name)
+		assert.Equal(t, "Simple", parsed.Contracts[2].Name)
+	})
+
+	t.Run("Update Contract", func(t *testing.T) {
+		lock := &flowkit.ContractsLock{}
+		lock.AddOrUpdate(flowkit.NewLockedContract("Simple", "testnet", address, "./imports/Simple.cdc", code))
+
+		locked := lock.ByAddress("testnet", address, "Simple")
+		require.NotNil(t, locked)
+		assert.True(t, locked.Matches(code))
+		assert.False(t, locked.Matches(tests.ContractSimpleUpdated.Source))
+		assert.Nil(t, lock.ByAddress("mainnet", address, "Simple"))
+
+		lock.AddOrUpdate(flowkit.NewLockedContract("Simple", "testnet", address, "./imports/Simple.cdc", tests.ContractSimpleUpdated.Source))
+		require.Len(t, lock.Contracts, 1)
+		assert.True(t, lock.Contracts[0].Matches(tests.ContractSimpleUpdated.Source))
+	})
+
+	t.Run("Invalid Lock", func(t *testing.T) {
+		_, err := flowkit.ParseContractsLock([]byte(`{"contracts": [{"name": "Simple", "address": "0x01"}]}`))
+		assert.ErrorContains(t, err, "invalid address of contract Simple in the contracts lock")
+	})
+}
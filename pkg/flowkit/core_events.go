@@ -0,0 +1,410 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// Core contract events are emitted from the address the contract is deployed to on each network,
+// so they are matched by the contract and event name.
+const (
+	tokensDepositedEventSuffix = ".FlowToken.TokensDeposited"
+	tokensWithdrawnEventSuffix = ".FlowToken.TokensWithdrawn"
+	feesDeductedEventSuffix    = ".FlowFees.FeesDeducted"
+)
+
+// CoreEvent is a decoded core protocol event.
+//
+// Use a type switch to access the event fields, e.g. *AccountCreatedEvent or *TokensDepositedEvent.
+type CoreEvent interface {
+	// String returns a short description of the event.
+	String() string
+}
+
+// AccountCreatedEvent is emitted when a new account is created.
+type AccountCreatedEvent struct {
+	Address flow.Address
+}
+
+func (e *AccountCreatedEvent) String() string {
+	return fmt.Sprintf("Account 0x%s created", e.Address.Hex())
+}
+
+// AccountKeyEvent is emitted when a key is added to or removed from an account.
+type AccountKeyEvent struct {
+	Address flow.Address
+	// PublicKey is the encoded public key.
+	PublicKey []byte
+	// KeyIndex is the index of the key on the account, nil if the network doesn't emit it with the event.
+	KeyIndex *int
+	Removed  bool
+}
+
+// HasPublicKey returns true if the event is for the provided public key.
+func (e *AccountKeyEvent) HasPublicKey(publicKey crypto.PublicKey) bool {
+	return bytes.Equal(e.PublicKey, publicKey.Encode())
+}
+
+func (e *AccountKeyEvent) String() string {
+	if e.Removed {
+		return fmt.Sprintf("Key 0x%x removed from account 0x%s", e.PublicKey, e.Address.Hex())
+	}
+	return fmt.Sprintf("Key 0x%x added to account 0x%s", e.PublicKey, e.Address.Hex())
+}
+
+// AccountContractEvent is emitted when a contract is added to, updated on or removed from an account.
+type AccountContractEvent struct {
+	Address  flow.Address
+	Contract string
+	CodeHash []byte
+	// Action is one of "added", "updated" or "removed".
+	Action string
+}
+
+func (e *AccountContractEvent) String() string {
+	return fmt.Sprintf("Contract %s %s on account 0x%s", e.Contract, e.Action, e.Address.Hex())
+}
+
+// TokensDepositedEvent is emitted when FLOW tokens are deposited into a vault.
+type TokensDepositedEvent struct {
+	Amount cadence.UFix64
+	// To is nil if the vault isn't stored in an account.
+	To *flow.Address
+}
+
+func (e *TokensDepositedEvent) String() string {
+	if e.To == nil {
+		return fmt.Sprintf("%s FLOW deposited", e.Amount)
+	}
+	return fmt.Sprintf("%s FLOW deposited to 0x%s", e.Amount, e.To.Hex())
+}
+
+// TokensWithdrawnEvent is emitted when FLOW tokens are withdrawn from a vault.
+type TokensWithdrawnEvent struct {
+	Amount cadence.UFix64
+	// From is nil if the vault isn't stored in an account.
+	From *flow.Address
+}
+
+func (e *TokensWithdrawnEvent) String() string {
+	if e.From == nil {
+		return fmt.Sprintf("%s FLOW withdrawn", e.Amount)
+	}
+	return fmt.Sprintf("%s FLOW withdrawn from 0x%s", e.Amount, e.From.Hex())
+}
+
+// FeesDeductedEvent is emitted when the transaction fees are deducted from the payer.
+type FeesDeductedEvent struct {
+	Amount          cadence.UFix64
+	InclusionEffort cadence.UFix64
+	ExecutionEffort cadence.UFix64
+}
+
+func (e *FeesDeductedEvent) String() string {
+	return fmt.Sprintf(
+		"%s FLOW fees deducted (inclusion effort %s, execution effort %s)",
+		e.Amount, e.InclusionEffort, e.ExecutionEffort,
+	)
+}
+
+// IsCoreEvent returns true if the event type can be decoded with DecodeCoreEvent.
+func IsCoreEvent(eventType string) bool {
+	switch eventType {
+	case flow.EventAccountCreated,
+		flow.EventAccountKeyAdded,
+		flow.EventAccountKeyRemoved,
+		flow.EventAccountContractAdded,
+		flow.EventAccountContractUpdated,
+		flow.EventAccountContractRemoved:
+		return true
+	}
+
+	return strings.HasSuffix(eventType, tokensDepositedEventSuffix) ||
+		strings.HasSuffix(eventType, tokensWithdrawnEventSuffix) ||
+		strings.HasSuffix(eventType, feesDeductedEventSuffix)
+}
+
+// DecodeCoreEvent decodes a core protocol event, nil is returned for events which are not core events.
+func DecodeCoreEvent(event Event) (CoreEvent, error) {
+	var decoded CoreEvent
+	var err error
+
+	switch {
+	case event.Type == flow.EventAccountCreated:
+		decoded, err = decodeAccountCreated(event)
+	case event.Type == flow.EventAccountKeyAdded:
+		decoded, err = decodeAccountKey(event, false)
+	case event.Type == flow.EventAccountKeyRemoved:
+		decoded, err = decodeAccountKey(event, true)
+	case event.Type == flow.EventAccountContractAdded:
+		decoded, err = decodeAccountContract(event, "added")
+	case event.Type == flow.EventAccountContractUpdated:
+		decoded, err = decodeAccountContract(event, "updated")
+	case event.Type == flow.EventAccountContractRemoved:
+		decoded, err = decodeAccountContract(event, "removed")
+	case strings.HasSuffix(event.Type, tokensDepositedEventSuffix):
+		decoded, err = decodeTokensDeposited(event)
+	case strings.HasSuffix(event.Type, tokensWithdrawnEventSuffix):
+		decoded, err = decodeTokensWithdrawn(event)
+	case strings.HasSuffix(event.Type, feesDeductedEventSuffix):
+		decoded, err = decodeFeesDeducted(event)
+	default:
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", event.Type, err)
+	}
+
+	return decoded, nil
+}
+
+// CoreEvents returns the decoded core events, events which are not core events are skipped.
+func (e *Events) CoreEvents() ([]CoreEvent, error) {
+	coreEvents := make([]CoreEvent, 0)
+	for _, event := range *e {
+		decoded, err := DecodeCoreEvent(event)
+		if err != nil {
+			return nil, err
+		}
+		if decoded != nil {
+			coreEvents = append(coreEvents, decoded)
+		}
+	}
+
+	return coreEvents, nil
+}
+
+func decodeAccountCreated(event Event) (*AccountCreatedEvent, error) {
+	address, err := addressField(event, "address")
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountCreatedEvent{Address: address}, nil
+}
+
+func decodeAccountKey(event Event, removed bool) (*AccountKeyEvent, error) {
+	address, err := addressField(event, "address")
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := publicKeyField(event, "publicKey")
+	if err != nil {
+		return nil, err
+	}
+
+	keyIndex, err := optionalIntField(event, "keyIndex")
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountKeyEvent{
+		Address:   address,
+		PublicKey: publicKey,
+		KeyIndex:  keyIndex,
+		Removed:   removed,
+	}, nil
+}
+
+func decodeAccountContract(event Event, action string) (*AccountContractEvent, error) {
+	address, err := addressField(event, "address")
+	if err != nil {
+		return nil, err
+	}
+
+	contract, ok := event.Values["contract"].(cadence.String)
+	if !ok {
+		return nil, fmt.Errorf("contract field must be a string")
+	}
+
+	codeHash, ok := event.Values["codeHash"].(cadence.Array)
+	if !ok {
+		return nil, fmt.Errorf("codeHash field must be a byte array")
+	}
+
+	hash, err := arrayBytes(codeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountContractEvent{
+		Address:  address,
+		Contract: string(contract),
+		CodeHash: hash,
+		Action:   action,
+	}, nil
+}
+
+func decodeTokensDeposited(event Event) (*TokensDepositedEvent, error) {
+	amount, err := ufix64Field(event, "amount")
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := optionalAddressField(event, "to")
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokensDepositedEvent{Amount: amount, To: to}, nil
+}
+
+func decodeTokensWithdrawn(event Event) (*TokensWithdrawnEvent, error) {
+	amount, err := ufix64Field(event, "amount")
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := optionalAddressField(event, "from")
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokensWithdrawnEvent{Amount: amount, From: from}, nil
+}
+
+func decodeFeesDeducted(event Event) (*FeesDeductedEvent, error) {
+	amount, err := ufix64Field(event, "amount")
+	if err != nil {
+		return nil, err
+	}
+
+	// the effort fields were added in a later version of the fees contract
+	fees := &FeesDeductedEvent{Amount: amount}
+	if _, ok := event.Values["inclusionEffort"]; ok {
+		fees.InclusionEffort, err = ufix64Field(event, "inclusionEffort")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := event.Values["executionEffort"]; ok {
+		fees.ExecutionEffort, err = ufix64Field(event, "executionEffort")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fees, nil
+}
+
+func addressField(event Event, name string) (flow.Address, error) {
+	address, ok := event.Values[name].(cadence.Address)
+	if !ok {
+		return flow.EmptyAddress, fmt.Errorf("%s field must be an address", name)
+	}
+
+	return flow.Address(address), nil
+}
+
+func optionalAddressField(event Event, name string) (*flow.Address, error) {
+	value := event.Values[name]
+	if optional, ok := value.(cadence.Optional); ok {
+		value = optional.Value
+	}
+
+	if value == nil {
+		return nil, nil
+	}
+
+	address, ok := value.(cadence.Address)
+	if !ok {
+		return nil, fmt.Errorf("%s field must be an optional address", name)
+	}
+
+	flowAddress := flow.Address(address)
+	return &flowAddress, nil
+}
+
+func ufix64Field(event Event, name string) (cadence.UFix64, error) {
+	amount, ok := event.Values[name].(cadence.UFix64)
+	if !ok {
+		return 0, fmt.Errorf("%s field must be a UFix64", name)
+	}
+
+	return amount, nil
+}
+
+// publicKeyField decodes the public key which is a PublicKey struct in recent versions of Cadence, in previous
+// versions it was a byte array of the encoded account key containing other data besides the key.
+// optionalIntField returns the integer field, or nil if the event doesn't have it.
+func optionalIntField(event Event, name string) (*int, error) {
+	value, ok := event.Values[name]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	number, ok := value.(cadence.Int)
+	if !ok {
+		return nil, fmt.Errorf("%s field must be an integer", name)
+	}
+
+	integer := number.Int()
+	return &integer, nil
+}
+
+func publicKeyField(event Event, name string) ([]byte, error) {
+	switch value := event.Values[name].(type) {
+	case cadence.Struct:
+		if len(value.Fields) == 0 {
+			return nil, fmt.Errorf("%s field is missing the public key", name)
+		}
+		keyArray, ok := value.Fields[0].(cadence.Array)
+		if !ok {
+			return nil, fmt.Errorf("%s field must contain a byte array", name)
+		}
+		return arrayBytes(keyArray)
+
+	case cadence.Array:
+		encoded, err := arrayBytes(value)
+		if err != nil {
+			return nil, err
+		}
+
+		accountKey, err := flow.DecodeAccountKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode account key: %w", err)
+		}
+		return accountKey.PublicKey.Encode(), nil
+
+	default:
+		return nil, fmt.Errorf("%s field must be a public key", name)
+	}
+}
+
+func arrayBytes(array cadence.Array) ([]byte, error) {
+	b := make([]byte, len(array.Values))
+	for i, value := range array.Values {
+		v, ok := value.(cadence.UInt8)
+		if !ok {
+			return nil, fmt.Errorf("array must only contain bytes")
+		}
+		b[i] = byte(v)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,206 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit_test
+
+import (
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+)
+
+func bytesArray(b []byte) cadence.Array {
+	values := make([]cadence.Value, len(b))
+	for i, v := range b {
+		values[i] = cadence.NewUInt8(v)
+	}
+	return cadence.NewArray(values)
+}
+
+func TestDecodeCoreEvent(t *testing.T) {
+	address := flow.HexToAddress("01cf0e2f2f715450")
+	amount, _ := cadence.NewUFix64("10.5")
+
+	privateKey, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, []byte("seedseedseedseedseedseedseedseed"))
+	require.NoError(t, err)
+	publicKey := privateKey.PublicKey()
+
+	t.Run("Account Created", func(t *testing.T) {
+		decoded, err := flowkit.DecodeCoreEvent(flowkit.Event{
+			Type:   flow.EventAccountCreated,
+			Values: map[string]cadence.Value{"address": cadence.NewAddress(address)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &flowkit.AccountCreatedEvent{Address: address}, decoded)
+		assert.Equal(t, "Account 0x01cf0e2f2f715450 created", decoded.String())
+	})
+
+	t.Run("Account Key Added", func(t *testing.T) {
+		keyStruct := cadence.NewStruct([]cadence.Value{bytesArray(publicKey.Encode())})
+
+		decoded, err := flowkit.DecodeCoreEvent(flowkit.Event{
+			Type: flow.EventAccountKeyAdded,
+			Values: map[string]cadence.Value{
+				"address":   cadence.NewAddress(address),
+				"publicKey": keyStruct,
+			},
+		})
+		require.NoError(t, err)
+
+		keyAdded, ok := decoded.(*flowkit.AccountKeyEvent)
+		require.True(t, ok)
+		assert.Equal(t, address, keyAdded.Address)
+		assert.False(t, keyAdded.Removed)
+		assert.True(t, keyAdded.HasPublicKey(publicKey))
+		assert.Nil(t, keyAdded.KeyIndex)
+	})
+
+	t.Run("Account Key Added With Key Index", func(t *testing.T) {
+		decoded, err := flowkit.DecodeCoreEvent(flowkit.Event{
+			Type: flow.EventAccountKeyAdded,
+			Values: map[string]cadence.Value{
+				"address":   cadence.NewAddress(address),
+				"publicKey": cadence.NewStruct([]cadence.Value{bytesArray(publicKey.Encode())}),
+				"keyIndex":  cadence.NewInt(2),
+			},
+		})
+		require.NoError(t, err)
+
+		keyAdded, ok := decoded.(*flowkit.AccountKeyEvent)
+		require.True(t, ok)
+		require.NotNil(t, keyAdded.KeyIndex)
+		assert.Equal(t, 2, *keyAdded.KeyIndex)
+	})
+
+	t.Run("Account Key Removed Encoded Account Key", func(t *testing.T) {
+		accountKey := flow.NewAccountKey().
+			SetPublicKey(publicKey).
+			SetHashAlgo(crypto.SHA3_256).
+			SetWeight(flow.AccountKeyWeightThreshold)
+
+		decoded, err := flowkit.DecodeCoreEvent(flowkit.Event{
+			Type: flow.EventAccountKeyRemoved,
+			Values: map[string]cadence.Value{
+				"address":   cadence.NewAddress(address),
+				"publicKey": bytesArray(accountKey.Encode()),
+			},
+		})
+		require.NoError(t, err)
+
+		keyRemoved, ok := decoded.(*flowkit.AccountKeyEvent)
+		require.True(t, ok)
+		assert.True(t, keyRemoved.Removed)
+		assert.True(t, keyRemoved.HasPublicKey(publicKey))
+	})
+
+	t.Run("Account Contract Updated", func(t *testing.T) {
+		decoded, err := flowkit.DecodeCoreEvent(flowkit.Event{
+			Type: flow.EventAccountContractUpdated,
+			Values: map[string]cadence.Value{
+				"address":  cadence.NewAddress(address),
+				"codeHash": bytesArray([]byte{1, 2, 3}),
+				"contract": cadence.String("NonFungibleToken"),
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &flowkit.AccountContractEvent{
+			Address:  address,
+			Contract: "NonFungibleToken",
+			CodeHash: []byte{1, 2, 3},
+			Action:   "updated",
+		}, decoded)
+		assert.Equal(t, "Contract NonFungibleToken updated on account 0x01cf0e2f2f715450", decoded.String())
+	})
+
+	t.Run("Tokens Deposited", func(t *testing.T) {
+		decoded, err := flowkit.DecodeCoreEvent(flowkit.Event{
+			Type: "A.1654653399040a61.FlowToken.TokensDeposited",
+			Values: map[string]cadence.Value{
+				"amount": amount,
+				"to":     cadence.NewOptional(cadence.NewAddress(address)),
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &flowkit.TokensDepositedEvent{Amount: amount, To: &address}, decoded)
+		assert.Equal(t, "10.50000000 FLOW deposited to 0x01cf0e2f2f715450", decoded.String())
+	})
+
+	t.Run("Tokens Withdrawn Without Account", func(t *testing.T) {
+		decoded, err := flowkit.DecodeCoreEvent(flowkit.Event{
+			Type: "A.1654653399040a61.FlowToken.TokensWithdrawn",
+			Values: map[string]cadence.Value{
+				"amount": amount,
+				"from":   cadence.NewOptional(nil),
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &flowkit.TokensWithdrawnEvent{Amount: amount}, decoded)
+	})
+
+	t.Run("Fees Deducted", func(t *testing.T) {
+		effort, _ := cadence.NewUFix64("0.5")
+
+		decoded, err := flowkit.DecodeCoreEvent(flowkit.Event{
+			Type: "A.f919ee77447b7497.FlowFees.FeesDeducted",
+			Values: map[string]cadence.Value{
+				"amount":          amount,
+				"inclusionEffort": effort,
+				"executionEffort": effort,
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &flowkit.FeesDeductedEvent{
+			Amount:          amount,
+			InclusionEffort: effort,
+			ExecutionEffort: effort,
+		}, decoded)
+	})
+
+	t.Run("Not Core Event", func(t *testing.T) {
+		decoded, err := flowkit.DecodeCoreEvent(flowkit.Event{Type: "A.01.Token.Deposited"})
+		require.NoError(t, err)
+		assert.Nil(t, decoded)
+		assert.False(t, flowkit.IsCoreEvent("A.01.Token.Deposited"))
+	})
+
+	t.Run("Fail Invalid Fields", func(t *testing.T) {
+		_, err := flowkit.DecodeCoreEvent(flowkit.Event{
+			Type:   flow.EventAccountCreated,
+			Values: map[string]cadence.Value{"address": cadence.String("01")},
+		})
+		assert.EqualError(t, err, "failed to decode flow.AccountCreated event: address field must be an address")
+	})
+
+	t.Run("Address For Key Added", func(t *testing.T) {
+		events := flowkit.Events{{
+			Type: flow.EventAccountKeyAdded,
+			Values: map[string]cadence.Value{
+				"address":   cadence.NewAddress(address),
+				"publicKey": cadence.NewStruct([]cadence.Value{bytesArray(publicKey.Encode())}),
+			},
+		}}
+
+		assert.Equal(t, &address, events.GetAddressForKeyAdded(publicKey))
+	})
+}
@@ -19,10 +19,6 @@
 package flowkit
 
 import (
-	"bytes"
-	"fmt"
-	"strings"
-
 	"github.com/onflow/cadence"
 
 	"github.com/onflow/flow-go-sdk/crypto"
@@ -46,6 +42,50 @@ func (e *Event) GetAddress() *flow.Address {
 	return nil
 }
 
+// FlatValues returns the event values formatted as strings, composite values are flattened
+// into their fields with the keys joined by a dot, e.g. "vault.balance".
+func (e *Event) FlatValues() map[string]string {
+	flat := make(map[string]string)
+	for name, value := range e.Values {
+		flattenValue(name, value, flat)
+	}
+
+	return flat
+}
+
+func flattenValue(key string, value cadence.Value, flat map[string]string) {
+	flattenFields := func(fields []cadence.Field, values []cadence.Value) {
+		for i, field := range fields {
+			if i < len(values) {
+				flattenValue(key+"."+field.Identifier, values[i], flat)
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		flat[key] = ""
+	case cadence.Optional:
+		flattenValue(key, v.Value, flat)
+	case cadence.String:
+		flat[key] = string(v)
+	case cadence.Struct:
+		if v.StructType == nil {
+			flat[key] = v.String()
+			return
+		}
+		flattenFields(v.StructType.Fields, v.Fields)
+	case cadence.Resource:
+		if v.ResourceType == nil {
+			flat[key] = v.String()
+			return
+		}
+		flattenFields(v.ResourceType.Fields, v.Fields)
+	default:
+		flat[key] = v.String()
+	}
+}
+
 type Events []Event
 
 func EventsFromTransaction(tx *flow.TransactionResult) Events {
@@ -82,10 +122,6 @@ func NewEvent(event flow.Event) Event {
 	}
 }
 
-// TODO(sideninja):
-// - Refactor this to flow.Address and err as return value instead of returning nil.
-// - This section should be improved to support all the core events parsing to better Go struct representation and should be extracted to Go SDK
-
 func (e *Events) GetAddress() *flow.Address {
 	for _, event := range *e {
 		if a, ok := event.Values["address"].(cadence.Address); ok {
@@ -108,37 +144,19 @@ func (e *Events) GetCreatedAddresses() []*flow.Address {
 	return addresses
 }
 
-func handleCadenceArrayValues(keyArray cadence.Array) []byte {
-	parsedKey := make([]byte, len(keyArray.Values))
-	for i, val := range keyArray.Values {
-		parsedKey[i] = val.ToGoValue().(byte)
-	}
-	return parsedKey
-}
-
 func (e *Events) GetAddressForKeyAdded(publicKey crypto.PublicKey) *flow.Address {
 	for _, event := range *e {
-		if event.Type == flow.EventAccountKeyAdded {
-			// new format
-			if keyStruct, ok := event.Values["publicKey"].(cadence.Struct); ok {
-				if keyArray, ok := keyStruct.Fields[0].(cadence.Array); ok {
-					parsedKey := handleCadenceArrayValues(keyArray)
-					if bytes.Equal(parsedKey, publicKey.Encode()) {
-						return event.GetAddress()
-					}
-				}
-			}
+		if event.Type != flow.EventAccountKeyAdded {
+			continue
+		}
 
-			// older format support, in previous versions of cadence the public key was encoded into an array containing other data beside the key
-			if p, ok := event.Values["publicKey"].(cadence.Array); ok {
-				parsedKey := handleCadenceArrayValues(p)
-				parsedKeyhex := fmt.Sprintf("%x", parsedKey)
-				publicKeyHex := publicKey.String()
-				//we have to remove 0x from beginning of publicKeyHex
-				if strings.Contains(parsedKeyhex, publicKeyHex[2:]) {
-					return event.GetAddress()
-				}
-			}
+		keyAdded, err := decodeAccountKey(event, false)
+		if err != nil {
+			continue
+		}
+
+		if keyAdded.HasPublicKey(publicKey) {
+			return &keyAdded.Address
 		}
 	}
 
@@ -0,0 +1,544 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+)
+
+// EventFilter is a filter expression evaluated against the values of an event.
+//
+// An expression compares event fields to literals, e.g. `to == 0x01cf0e2f2f715450 && amount > 10.0`.
+// Comparisons can be combined with `&&`, `||`, `!` and parentheses, and nested fields are
+// accessed with a dot, e.g. `vault.balance >= 1.5`.
+//
+// Literals are numbers, addresses prefixed with 0x, quoted strings, true, false and nil. Numbers
+// are compared exactly, so fixed-point values like UFix64 compare correctly with decimal literals.
+// A comparison on a field the event doesn't have is false.
+type EventFilter struct {
+	expression string
+	root       filterNode
+}
+
+// ParseEventFilter parses the filter expression.
+func ParseEventFilter(expression string) (*EventFilter, error) {
+	tokens, err := tokenizeFilter(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression: %w", err)
+	}
+
+	parser := &filterParser{tokens: tokens}
+	root, err := parser.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression: %w", err)
+	}
+
+	if token := parser.peek(); token.kind != tokenEOF {
+		return nil, fmt.Errorf("invalid filter expression: unexpected %q at position %d", token.text, token.pos)
+	}
+
+	return &EventFilter{
+		expression: expression,
+		root:       root,
+	}, nil
+}
+
+func (f *EventFilter) String() string {
+	return f.expression
+}
+
+// Match returns true if the event values match the filter.
+func (f *EventFilter) Match(event Event) (bool, error) {
+	return f.root.eval(event.Values)
+}
+
+// FilterBlockEvents returns the block events with only the events matching the filter.
+func (f *EventFilter) FilterBlockEvents(blockEvents []flow.BlockEvents) ([]flow.BlockEvents, error) {
+	filtered := make([]flow.BlockEvents, 0, len(blockEvents))
+	for _, blockEvent := range blockEvents {
+		events := make([]flow.Event, 0)
+		for _, event := range blockEvent.Events {
+			match, err := f.Match(NewEvent(event))
+			if err != nil {
+				return nil, fmt.Errorf("failed to filter event %s: %w", event.Type, err)
+			}
+			if match {
+				events = append(events, event)
+			}
+		}
+
+		blockEvent.Events = events
+		filtered = append(filtered, blockEvent)
+	}
+
+	return filtered, nil
+}
+
+type filterTokenKind int
+
+const (
+	tokenEOF filterTokenKind = iota
+	tokenIdentifier
+	tokenNumber
+	tokenAddress
+	tokenString
+	tokenOperator
+	tokenOpenParen
+	tokenCloseParen
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+var filterOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"}
+
+func tokenizeFilter(expression string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(expression)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+
+		case r == '(':
+			tokens = append(tokens, filterToken{tokenOpenParen, "(", start})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, filterToken{tokenCloseParen, ")", start})
+			i++
+
+		case r == '"' || r == '\'':
+			i++
+			for i < len(runes) && runes[i] != r {
+				i++
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			tokens = append(tokens, filterToken{tokenString, string(runes[start+1 : i]), start})
+			i++
+
+		case r == '0' && i+1 < len(runes) && runes[i+1] == 'x':
+			i += 2
+			for i < len(runes) && isHexRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, filterToken{tokenAddress, string(runes[start:i]), start})
+
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, filterToken{tokenNumber, string(runes[start:i]), start})
+
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, filterToken{tokenIdentifier, string(runes[start:i]), start})
+
+		default:
+			operator := ""
+			for _, op := range filterOperators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					operator = op
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected %q at position %d", r, start)
+			}
+			tokens = append(tokens, filterToken{tokenOperator, operator, start})
+			i += len(operator)
+		}
+	}
+
+	return append(tokens, filterToken{tokenEOF, "end of expression", len(runes)}), nil
+}
+
+func isHexRune(r rune) bool {
+	return unicode.IsDigit(r) || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+// filterParser is a recursive descent parser of filter expressions, where && binds tighter than ||.
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	token := p.tokens[p.pos]
+	if token.kind != tokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+func (p *filterParser) isOperator(operator string) bool {
+	token := p.peek()
+	return token.kind == tokenOperator && token.text == operator
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator("&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.isOperator("!") {
+		p.next()
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node}, nil
+	}
+
+	if p.peek().kind == tokenOpenParen {
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if token := p.next(); token.kind != tokenCloseParen {
+			return nil, fmt.Errorf("expected ) at position %d", token.pos)
+		}
+		return node, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	field := p.next()
+	if field.kind != tokenIdentifier {
+		return nil, fmt.Errorf("expected field name at position %d, got %q", field.pos, field.text)
+	}
+
+	operator := p.next()
+	switch operator.text {
+	case "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return nil, fmt.Errorf("expected comparison operator at position %d, got %q", operator.pos, operator.text)
+	}
+
+	literal, err := parseFilterLiteral(p.next())
+	if err != nil {
+		return nil, err
+	}
+
+	return comparisonNode{
+		field:    strings.Split(field.text, "."),
+		operator: operator.text,
+		literal:  literal,
+	}, nil
+}
+
+type literalKind int
+
+const (
+	literalNil literalKind = iota
+	literalBool
+	literalNumber
+	literalAddress
+	literalString
+)
+
+type filterLiteral struct {
+	kind    literalKind
+	text    string
+	boolean bool
+	number  *big.Rat
+	address flow.Address
+}
+
+func parseFilterLiteral(token filterToken) (filterLiteral, error) {
+	literal := filterLiteral{text: token.text}
+
+	switch token.kind {
+	case tokenNumber:
+		number, ok := new(big.Rat).SetString(token.text)
+		if !ok {
+			return literal, fmt.Errorf("invalid number %q at position %d", token.text, token.pos)
+		}
+		literal.kind = literalNumber
+		literal.number = number
+	case tokenAddress:
+		literal.kind = literalAddress
+		literal.address = flow.HexToAddress(token.text)
+	case tokenString:
+		literal.kind = literalString
+	case tokenIdentifier:
+		switch token.text {
+		case "true", "false":
+			literal.kind = literalBool
+			literal.boolean = token.text == "true"
+		case "nil":
+			literal.kind = literalNil
+		default:
+			return literal, fmt.Errorf("expected value at position %d, got %q, quote strings", token.pos, token.text)
+		}
+	default:
+		return literal, fmt.Errorf("expected value at position %d, got %q", token.pos, token.text)
+	}
+
+	return literal, nil
+}
+
+type filterNode interface {
+	eval(values map[string]cadence.Value) (bool, error)
+}
+
+type orNode struct {
+	left, right filterNode
+}
+
+func (n orNode) eval(values map[string]cadence.Value) (bool, error) {
+	left, err := n.left.eval(values)
+	if err != nil || left {
+		return left, err
+	}
+	return n.right.eval(values)
+}
+
+type andNode struct {
+	left, right filterNode
+}
+
+func (n andNode) eval(values map[string]cadence.Value) (bool, error) {
+	left, err := n.left.eval(values)
+	if err != nil || !left {
+		return false, err
+	}
+	return n.right.eval(values)
+}
+
+type notNode struct {
+	node filterNode
+}
+
+func (n notNode) eval(values map[string]cadence.Value) (bool, error) {
+	result, err := n.node.eval(values)
+	return !result, err
+}
+
+type comparisonNode struct {
+	field    []string
+	operator string
+	literal  filterLiteral
+}
+
+func (n comparisonNode) eval(values map[string]cadence.Value) (bool, error) {
+	value, ok := lookupField(values, n.field)
+	if !ok {
+		return false, nil
+	}
+
+	name := strings.Join(n.field, ".")
+
+	if value == nil || n.literal.kind == literalNil {
+		isNil := value == nil && n.literal.kind == literalNil
+		switch n.operator {
+		case "==":
+			return isNil, nil
+		case "!=":
+			return !isNil, nil
+		default:
+			return false, nil
+		}
+	}
+
+	switch v := value.(type) {
+	case cadence.Address:
+		address := n.literal.address
+		switch n.literal.kind {
+		case literalAddress:
+		case literalString:
+			address = flow.HexToAddress(n.literal.text)
+		default:
+			return false, n.mismatch(name, "an address")
+		}
+		return n.compareEquality(name, flow.Address(v) == address)
+
+	case cadence.String:
+		if n.literal.kind != literalString {
+			return false, n.mismatch(name, "a string")
+		}
+		return n.compareOrder(strings.Compare(string(v), n.literal.text)), nil
+
+	case cadence.Bool:
+		if n.literal.kind != literalBool {
+			return false, n.mismatch(name, "true or false")
+		}
+		return n.compareEquality(name, bool(v) == n.literal.boolean)
+
+	case cadence.Int, cadence.Int8, cadence.Int16, cadence.Int32, cadence.Int64, cadence.Int128, cadence.Int256,
+		cadence.UInt, cadence.UInt8, cadence.UInt16, cadence.UInt32, cadence.UInt64, cadence.UInt128, cadence.UInt256,
+		cadence.Word8, cadence.Word16, cadence.Word32, cadence.Word64,
+		cadence.Fix64, cadence.UFix64:
+		if n.literal.kind != literalNumber {
+			return false, n.mismatch(name, "a number")
+		}
+		// integers and fixed-point values are formatted in decimal notation, which is parsed exactly
+		number, ok := new(big.Rat).SetString(v.String())
+		if !ok {
+			return false, fmt.Errorf("field %s has an invalid number %s", name, v.String())
+		}
+		return n.compareOrder(number.Cmp(n.literal.number)), nil
+
+	default:
+		return false, fmt.Errorf("field %s of type %s can't be compared", name, value.Type().ID())
+	}
+}
+
+func (n comparisonNode) compareEquality(name string, equal bool) (bool, error) {
+	switch n.operator {
+	case "==":
+		return equal, nil
+	case "!=":
+		return !equal, nil
+	default:
+		return false, fmt.Errorf("field %s can only be compared with == or !=", name)
+	}
+}
+
+func (n comparisonNode) compareOrder(cmp int) bool {
+	switch n.operator {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func (n comparisonNode) mismatch(name string, expected string) error {
+	return fmt.Errorf("field %s must be compared with %s, got %s", name, expected, n.literal.text)
+}
+
+// lookupField returns the value of the field path, optionals are unwrapped and a nil optional is returned as nil.
+func lookupField(values map[string]cadence.Value, path []string) (cadence.Value, bool) {
+	value, ok := values[path[0]]
+	if !ok {
+		return nil, false
+	}
+
+	for _, name := range path[1:] {
+		value = unwrapOptional(value)
+
+		var fields []cadence.Field
+		var fieldValues []cadence.Value
+		switch v := value.(type) {
+		case cadence.Struct:
+			if v.StructType != nil {
+				fields, fieldValues = v.StructType.Fields, v.Fields
+			}
+		case cadence.Resource:
+			if v.ResourceType != nil {
+				fields, fieldValues = v.ResourceType.Fields, v.Fields
+			}
+		case cadence.Event:
+			if v.EventType != nil {
+				fields, fieldValues = v.EventType.Fields, v.Fields
+			}
+		}
+
+		found := false
+		for i, field := range fields {
+			if field.Identifier == name && i < len(fieldValues) {
+				value = fieldValues[i]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	return unwrapOptional(value), true
+}
+
+func unwrapOptional(value cadence.Value) cadence.Value {
+	for {
+		optional, ok := value.(cadence.Optional)
+		if !ok {
+			return value
+		}
+		value = optional.Value
+	}
+}
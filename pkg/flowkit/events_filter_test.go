@@ -0,0 +1,158 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit_test
+
+import (
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/tests"
+)
+
+func TestEventFilter(t *testing.T) {
+	vaultType := &cadence.StructType{
+		QualifiedIdentifier: "Vault",
+		Fields: []cadence.Field{{
+			Identifier: "balance",
+			Type:       cadence.UFix64Type{},
+		}},
+	}
+	amount, _ := cadence.NewUFix64("10.5")
+	balance, _ := cadence.NewUFix64("100.0")
+
+	event := flowkit.Event{
+		Type: "A.01.Token.Deposited",
+		Values: map[string]cadence.Value{
+			"to":     cadence.NewOptional(cadence.NewAddress(flow.HexToAddress("01cf0e2f2f715450"))),
+			"from":   cadence.NewOptional(nil),
+			"amount": amount,
+			"id":     cadence.NewUInt64(42),
+			"name":   cadence.String("alice"),
+			"locked": cadence.NewBool(false),
+			"vault":  cadence.NewStruct([]cadence.Value{balance}).WithType(vaultType),
+		},
+	}
+
+	t.Run("Match", func(t *testing.T) {
+		matching := []string{
+			"to == 0x01cf0e2f2f715450 && amount > 10.0",
+			"to == '01cf0e2f2f715450'",
+			"amount == 10.5",
+			"amount >= 10.50000000",
+			"id == 42 && id < 100",
+			`name == "alice"`,
+			"locked == false",
+			"from == nil",
+			"to != nil",
+			"vault.balance >= 100",
+			"amount > 100 || id == 42",
+			"!(amount > 100)",
+			"(amount < 1 || id == 42) && name != 'bob'",
+		}
+
+		for _, expression := range matching {
+			filter, err := flowkit.ParseEventFilter(expression)
+			require.NoError(t, err, expression)
+
+			match, err := filter.Match(event)
+			require.NoError(t, err, expression)
+			assert.True(t, match, expression)
+		}
+	})
+
+	t.Run("No Match", func(t *testing.T) {
+		notMatching := []string{
+			"to == 0x01",
+			"amount > 10.5",
+			"amount < 10.0 && id == 42",
+			"from != nil",
+			"missing == 1",
+			"vault.missing == 1",
+			"!(id == 42)",
+		}
+
+		for _, expression := range notMatching {
+			filter, err := flowkit.ParseEventFilter(expression)
+			require.NoError(t, err, expression)
+
+			match, err := filter.Match(event)
+			require.NoError(t, err, expression)
+			assert.False(t, match, expression)
+		}
+	})
+
+	t.Run("Fail Parsing", func(t *testing.T) {
+		invalid := []string{
+			"",
+			"amount >",
+			"amount = 10",
+			"10 == amount",
+			"name == alice",
+			"(amount > 10",
+			"amount > 10 amount",
+			"name == 'alice",
+		}
+
+		for _, expression := range invalid {
+			_, err := flowkit.ParseEventFilter(expression)
+			assert.Error(t, err, expression)
+		}
+	})
+
+	t.Run("Fail Type Mismatch", func(t *testing.T) {
+		mismatched := []string{
+			"amount == 'ten'",
+			"name > 10",
+			"to > 0x01",
+			"locked == 1",
+		}
+
+		for _, expression := range mismatched {
+			filter, err := flowkit.ParseEventFilter(expression)
+			require.NoError(t, err, expression)
+
+			_, err = filter.Match(event)
+			assert.Error(t, err, expression)
+		}
+	})
+
+	t.Run("Filter Block Events", func(t *testing.T) {
+		fields := []cadence.Field{{Identifier: "amount", Type: cadence.IntType{}}}
+		small := tests.NewEvent(0, "Deposit", fields, []cadence.Value{cadence.NewInt(1)})
+		large := tests.NewEvent(1, "Deposit", fields, []cadence.Value{cadence.NewInt(100)})
+
+		filter, err := flowkit.ParseEventFilter("amount > 10")
+		require.NoError(t, err)
+
+		filtered, err := filter.FilterBlockEvents([]flow.BlockEvents{
+			{Height: 1, Events: []flow.Event{*small, *large}},
+			{Height: 2, Events: []flow.Event{*small}},
+		})
+		require.NoError(t, err)
+		require.Len(t, filtered, 2)
+		require.Len(t, filtered[0].Events, 1)
+		assert.Equal(t, 1, filtered[0].Events[0].EventIndex)
+		assert.Len(t, filtered[1].Events, 0)
+	})
+}
@@ -50,3 +50,31 @@ func TestAddress(t *testing.T) {
 	address := flow.HexToAddress("cdfef0f4f0786e9")
 	assert.Equal(t, "0cdfef0f4f0786e9", address.String())
 }
+
+func TestEventFlatValues(t *testing.T) {
+	vaultType := &cadence.StructType{
+		QualifiedIdentifier: "Vault",
+		Fields: []cadence.Field{{
+			Identifier: "balance",
+			Type:       cadence.UFix64Type{},
+		}},
+	}
+	balance, _ := cadence.NewUFix64("10.5")
+
+	event := flowkit.Event{
+		Type: "A.01.Token.Deposited",
+		Values: map[string]cadence.Value{
+			"to":    cadence.NewOptional(cadence.NewAddress(flow.HexToAddress("01"))),
+			"from":  cadence.NewOptional(nil),
+			"name":  cadence.String("alice"),
+			"vault": cadence.NewStruct([]cadence.Value{balance}).WithType(vaultType),
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"to":            "0x0000000000000001",
+		"from":          "",
+		"name":          "alice",
+		"vault.balance": "10.50000000",
+	}, event.FlatValues())
+}
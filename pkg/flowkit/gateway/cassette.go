@@ -0,0 +1,379 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// A cassette is a newline delimited JSON file, each line holds one recorded gateway interaction.
+//
+// Flow types are converted to the cassette types below so the file stays readable and
+// decodes back to the same values.
+
+type cassetteInteraction struct {
+	Method   string          `json:"method"`
+	Request  string          `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+func scriptRequest(script []byte, arguments []cadence.Value) (string, error) {
+	args, err := cadenceValuesToMessages(arguments)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(script)
+	for _, arg := range args {
+		hash.Write(arg)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+type cassetteAccountKey struct {
+	Index          int    `json:"index"`
+	PublicKey      string `json:"publicKey"`
+	SigAlgo        string `json:"sigAlgo"`
+	HashAlgo       string `json:"hashAlgo"`
+	Weight         int    `json:"weight"`
+	SequenceNumber uint64 `json:"sequenceNumber"`
+	Revoked        bool   `json:"revoked"`
+}
+
+type cassetteAccount struct {
+	Address   string               `json:"address"`
+	Balance   uint64               `json:"balance"`
+	Code      []byte               `json:"code,omitempty"`
+	Keys      []cassetteAccountKey `json:"keys"`
+	Contracts map[string]string    `json:"contracts"`
+}
+
+func accountToCassette(account *flow.Account) cassetteAccount {
+	keys := make([]cassetteAccountKey, 0, len(account.Keys))
+	for _, key := range account.Keys {
+		keys = append(keys, cassetteAccountKey{
+			Index:          key.Index,
+			PublicKey:      hex.EncodeToString(key.PublicKey.Encode()),
+			SigAlgo:        key.SigAlgo.String(),
+			HashAlgo:       key.HashAlgo.String(),
+			Weight:         key.Weight,
+			SequenceNumber: key.SequenceNumber,
+			Revoked:        key.Revoked,
+		})
+	}
+
+	contracts := make(map[string]string, len(account.Contracts))
+	for name, code := range account.Contracts {
+		contracts[name] = string(code)
+	}
+
+	return cassetteAccount{
+		Address:   account.Address.Hex(),
+		Balance:   account.Balance,
+		Code:      account.Code,
+		Keys:      keys,
+		Contracts: contracts,
+	}
+}
+
+func (c cassetteAccount) toFlow() (*flow.Account, error) {
+	keys := make([]*flow.AccountKey, 0, len(c.Keys))
+	for _, key := range c.Keys {
+		sigAlgo := crypto.StringToSignatureAlgorithm(key.SigAlgo)
+		publicKey, err := crypto.DecodePublicKeyHex(sigAlgo, key.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode account key: %w", err)
+		}
+
+		keys = append(keys, &flow.AccountKey{
+			Index:          key.Index,
+			PublicKey:      publicKey,
+			SigAlgo:        sigAlgo,
+			HashAlgo:       crypto.StringToHashAlgorithm(key.HashAlgo),
+			Weight:         key.Weight,
+			SequenceNumber: key.SequenceNumber,
+			Revoked:        key.Revoked,
+		})
+	}
+
+	contracts := make(map[string][]byte, len(c.Contracts))
+	for name, code := range c.Contracts {
+		contracts[name] = []byte(code)
+	}
+
+	return &flow.Account{
+		Address:   flow.HexToAddress(c.Address),
+		Balance:   c.Balance,
+		Code:      c.Code,
+		Keys:      keys,
+		Contracts: contracts,
+	}, nil
+}
+
+type cassetteEvent struct {
+	Type             string          `json:"type"`
+	TransactionID    string          `json:"transactionId"`
+	TransactionIndex int             `json:"transactionIndex"`
+	EventIndex       int             `json:"eventIndex"`
+	Payload          json.RawMessage `json:"payload"`
+}
+
+func eventsToCassette(events []flow.Event) ([]cassetteEvent, error) {
+	result := make([]cassetteEvent, 0, len(events))
+	for _, event := range events {
+		payload := event.Payload
+		if len(payload) == 0 {
+			var err error
+			payload, err = jsoncdc.Encode(event.Value)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result = append(result, cassetteEvent{
+			Type:             event.Type,
+			TransactionID:    event.TransactionID.Hex(),
+			TransactionIndex: event.TransactionIndex,
+			EventIndex:       event.EventIndex,
+			Payload:          payload,
+		})
+	}
+
+	return result, nil
+}
+
+func eventsFromCassette(events []cassetteEvent) ([]flow.Event, error) {
+	result := make([]flow.Event, 0, len(events))
+	for _, event := range events {
+		value, err := messageToCadenceValue(event.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		cadenceEvent, ok := value.(cadence.Event)
+		if !ok {
+			return nil, fmt.Errorf("recorded payload of event %s is not an event", event.Type)
+		}
+
+		result = append(result, flow.Event{
+			Type:             event.Type,
+			TransactionID:    flow.HexToID(event.TransactionID),
+			TransactionIndex: event.TransactionIndex,
+			EventIndex:       event.EventIndex,
+			Value:            cadenceEvent,
+			Payload:          event.Payload,
+		})
+	}
+
+	return result, nil
+}
+
+type cassetteTransactionResult struct {
+	Status      int             `json:"status"`
+	Error       string          `json:"error,omitempty"`
+	Events      []cassetteEvent `json:"events"`
+	BlockID     string          `json:"blockId"`
+	BlockHeight uint64          `json:"blockHeight"`
+}
+
+func transactionResultToCassette(result *flow.TransactionResult) (cassetteTransactionResult, error) {
+	events, err := eventsToCassette(result.Events)
+	if err != nil {
+		return cassetteTransactionResult{}, err
+	}
+
+	errMessage := ""
+	if result.Error != nil {
+		errMessage = result.Error.Error()
+	}
+
+	return cassetteTransactionResult{
+		Status:      int(result.Status),
+		Error:       errMessage,
+		Events:      events,
+		BlockID:     result.BlockID.Hex(),
+		BlockHeight: result.BlockHeight,
+	}, nil
+}
+
+func (c cassetteTransactionResult) toFlow() (*flow.TransactionResult, error) {
+	events, err := eventsFromCassette(c.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	var resultErr error
+	if c.Error != "" {
+		resultErr = errors.New(c.Error)
+	}
+
+	return &flow.TransactionResult{
+		Status:      flow.TransactionStatus(c.Status),
+		Error:       resultErr,
+		Events:      events,
+		BlockID:     flow.HexToID(c.BlockID),
+		BlockHeight: c.BlockHeight,
+	}, nil
+}
+
+type cassetteSeal struct {
+	BlockID            string `json:"blockId"`
+	ExecutionReceiptID string `json:"executionReceiptId"`
+}
+
+type cassetteBlock struct {
+	ID          string         `json:"id"`
+	ParentID    string         `json:"parentId"`
+	Height      uint64         `json:"height"`
+	Timestamp   time.Time      `json:"timestamp"`
+	Collections []string       `json:"collections"`
+	Seals       []cassetteSeal `json:"seals"`
+}
+
+func blockToCassette(block *flow.Block) cassetteBlock {
+	collections := make([]string, 0, len(block.CollectionGuarantees))
+	for _, guarantee := range block.CollectionGuarantees {
+		collections = append(collections, guarantee.CollectionID.Hex())
+	}
+
+	seals := make([]cassetteSeal, 0, len(block.Seals))
+	for _, seal := range block.Seals {
+		seals = append(seals, cassetteSeal{
+			BlockID:            seal.BlockID.Hex(),
+			ExecutionReceiptID: seal.ExecutionReceiptID.Hex(),
+		})
+	}
+
+	return cassetteBlock{
+		ID:          block.ID.Hex(),
+		ParentID:    block.ParentID.Hex(),
+		Height:      block.Height,
+		Timestamp:   block.Timestamp,
+		Collections: collections,
+		Seals:       seals,
+	}
+}
+
+func (c cassetteBlock) toFlow() *flow.Block {
+	guarantees := make([]*flow.CollectionGuarantee, 0, len(c.Collections))
+	for _, id := range c.Collections {
+		guarantees = append(guarantees, &flow.CollectionGuarantee{CollectionID: flow.HexToID(id)})
+	}
+
+	seals := make([]*flow.BlockSeal, 0, len(c.Seals))
+	for _, seal := range c.Seals {
+		seals = append(seals, &flow.BlockSeal{
+			BlockID:            flow.HexToID(seal.BlockID),
+			ExecutionReceiptID: flow.HexToID(seal.ExecutionReceiptID),
+		})
+	}
+
+	return &flow.Block{
+		BlockHeader: flow.BlockHeader{
+			ID:        flow.HexToID(c.ID),
+			ParentID:  flow.HexToID(c.ParentID),
+			Height:    c.Height,
+			Timestamp: c.Timestamp,
+		},
+		BlockPayload: flow.BlockPayload{
+			CollectionGuarantees: guarantees,
+			Seals:                seals,
+		},
+	}
+}
+
+type cassetteBlockEvents struct {
+	BlockID        string          `json:"blockId"`
+	Height         uint64          `json:"height"`
+	BlockTimestamp time.Time       `json:"blockTimestamp"`
+	Events         []cassetteEvent `json:"events"`
+}
+
+func blockEventsToCassette(blockEvents []flow.BlockEvents) ([]cassetteBlockEvents, error) {
+	result := make([]cassetteBlockEvents, 0, len(blockEvents))
+	for _, be := range blockEvents {
+		events, err := eventsToCassette(be.Events)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, cassetteBlockEvents{
+			BlockID:        be.BlockID.Hex(),
+			Height:         be.Height,
+			BlockTimestamp: be.BlockTimestamp,
+			Events:         events,
+		})
+	}
+
+	return result, nil
+}
+
+func blockEventsFromCassette(blockEvents []cassetteBlockEvents) ([]flow.BlockEvents, error) {
+	result := make([]flow.BlockEvents, 0, len(blockEvents))
+	for _, be := range blockEvents {
+		events, err := eventsFromCassette(be.Events)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, flow.BlockEvents{
+			BlockID:        flow.HexToID(be.BlockID),
+			Height:         be.Height,
+			BlockTimestamp: be.BlockTimestamp,
+			Events:         events,
+		})
+	}
+
+	return result, nil
+}
+
+type cassetteCollection struct {
+	TransactionIDs []string `json:"transactionIds"`
+}
+
+func collectionToCassette(collection *flow.Collection) cassetteCollection {
+	ids := make([]string, 0, len(collection.TransactionIDs))
+	for _, id := range collection.TransactionIDs {
+		ids = append(ids, id.Hex())
+	}
+
+	return cassetteCollection{TransactionIDs: ids}
+}
+
+func (c cassetteCollection) toFlow() *flow.Collection {
+	ids := make([]flow.Identifier, 0, len(c.TransactionIDs))
+	for _, id := range c.TransactionIDs {
+		ids = append(ids, flow.HexToID(id))
+	}
+
+	return &flow.Collection{TransactionIDs: ids}
+}
@@ -41,7 +41,6 @@ import (
 type EmulatorGateway struct {
 	emulator        *emulator.Blockchain
 	backend         *backend.Backend
-	ctx             context.Context
 	logger          *logrus.Logger
 	emulatorOptions []emulator.Option
 }
@@ -57,7 +56,6 @@ func NewEmulatorGateway(serviceAccount *flowkit.Account) *EmulatorGateway {
 func NewEmulatorGatewayWithOpts(serviceAccount *flowkit.Account, opts ...func(*EmulatorGateway)) *EmulatorGateway {
 
 	gateway := &EmulatorGateway{
-		ctx:             context.Background(),
 		logger:          logrus.New(),
 		emulatorOptions: []emulator.Option{},
 	}
@@ -90,15 +88,17 @@ func WithEmulatorLogger(logger *zerolog.Logger) func(g *EmulatorGateway) {
 	}
 }
 
-func WithContext(ctx context.Context) func(g *EmulatorGateway) {
-	return func(g *EmulatorGateway) {
-		g.ctx = ctx
-	}
+// WithContext sets the context of the gateway calls.
+//
+// Deprecated: each gateway call takes its own context, the context set with WithContext is ignored.
+func WithContext(_ context.Context) func(g *EmulatorGateway) {
+	return func(g *EmulatorGateway) {}
 }
 
-func (g *EmulatorGateway) SetContext(ctx context.Context) {
-	g.ctx = ctx
-}
+// SetContext sets the context of the gateway calls.
+//
+// Deprecated: each gateway call takes its own context, the context set with SetContext is ignored.
+func (g *EmulatorGateway) SetContext(_ context.Context) {}
 
 func newEmulator(serviceAccount *flowkit.Account, emulatorOptions ...emulator.Option) *emulator.Blockchain {
 	var opts []emulator.Option
@@ -110,9 +110,8 @@ func newEmulator(serviceAccount *flowkit.Account, emulatorOptions ...emulator.Op
 			serviceAccount.Key().SigAlgo(),
 			serviceAccount.Key().HashAlgo(),
 		))
-		opts = append(opts, emulatorOptions...)
-
 	}
+	opts = append(opts, emulatorOptions...)
 
 	b, err := emulator.NewBlockchain(opts...)
 	if err != nil {
@@ -122,54 +121,70 @@ func newEmulator(serviceAccount *flowkit.Account, emulatorOptions ...emulator.Op
 	return b
 }
 
-func (g *EmulatorGateway) GetAccount(address flow.Address) (*flow.Account, error) {
-	account, err := g.backend.GetAccount(g.ctx, address)
+func (g *EmulatorGateway) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
+	account, err := g.backend.GetAccount(ctx, address)
+	if err != nil {
+		return nil, UnwrapStatusError(err)
+	}
+	return account, nil
+}
+
+func (g *EmulatorGateway) GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	account, err := g.backend.GetAccountAtBlockHeight(ctx, address, height)
 	if err != nil {
 		return nil, UnwrapStatusError(err)
 	}
 	return account, nil
 }
 
-func (g *EmulatorGateway) SendSignedTransaction(tx *flowkit.Transaction) (*flow.Transaction, error) {
-	err := g.backend.SendTransaction(context.Background(), *tx.FlowTransaction())
+func (g *EmulatorGateway) GetAccountAtBlockID(ctx context.Context, address flow.Address, id flow.Identifier) (*flow.Account, error) {
+	block, err := g.backend.GetBlockByID(ctx, id)
+	if err != nil {
+		return nil, UnwrapStatusError(err)
+	}
+	return g.GetAccountAtBlockHeight(ctx, address, block.Header.Height)
+}
+
+func (g *EmulatorGateway) SendSignedTransaction(ctx context.Context, tx *flowkit.Transaction) (*flow.Transaction, error) {
+	err := g.backend.SendTransaction(ctx, *tx.FlowTransaction())
 	if err != nil {
 		return nil, UnwrapStatusError(err)
 	}
 	return tx.FlowTransaction(), nil
 }
 
-func (g *EmulatorGateway) GetTransactionResult(ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
-	result, err := g.backend.GetTransactionResult(g.ctx, ID)
+func (g *EmulatorGateway) GetTransactionResult(ctx context.Context, ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
+	result, err := g.backend.GetTransactionResult(ctx, ID)
 	if err != nil {
 		return nil, UnwrapStatusError(err)
 	}
 	return result, nil
 }
 
-func (g *EmulatorGateway) GetTransaction(id flow.Identifier) (*flow.Transaction, error) {
-	transaction, err := g.backend.GetTransaction(g.ctx, id)
+func (g *EmulatorGateway) GetTransaction(ctx context.Context, id flow.Identifier) (*flow.Transaction, error) {
+	transaction, err := g.backend.GetTransaction(ctx, id)
 	if err != nil {
 		return nil, UnwrapStatusError(err)
 	}
 	return transaction, nil
 }
 
-func (g *EmulatorGateway) Ping() error {
-	err := g.backend.Ping(g.ctx)
+func (g *EmulatorGateway) Ping(ctx context.Context) error {
+	err := g.backend.Ping(ctx)
 	if err != nil {
 		return UnwrapStatusError(err)
 	}
 	return nil
 }
 
-func (g *EmulatorGateway) ExecuteScript(script []byte, arguments []cadence.Value) (cadence.Value, error) {
+func (g *EmulatorGateway) ExecuteScript(ctx context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
 
 	args, err := cadenceValuesToMessages(arguments)
 	if err != nil {
 		return nil, UnwrapStatusError(err)
 	}
 
-	result, err := g.backend.ExecuteScriptAtLatestBlock(g.ctx, script, args)
+	result, err := g.backend.ExecuteScriptAtLatestBlock(ctx, script, args)
 	if err != nil {
 		return nil, UnwrapStatusError(err)
 	}
@@ -182,8 +197,36 @@ func (g *EmulatorGateway) ExecuteScript(script []byte, arguments []cadence.Value
 	return value, nil
 }
 
-func (g *EmulatorGateway) GetLatestBlock() (*flow.Block, error) {
-	block, err := g.backend.GetLatestBlock(g.ctx, true)
+func (g *EmulatorGateway) ExecuteScriptAtBlockHeight(ctx context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	args, err := cadenceValuesToMessages(arguments)
+	if err != nil {
+		return nil, UnwrapStatusError(err)
+	}
+
+	result, err := g.backend.ExecuteScriptAtBlockHeight(ctx, height, script, args)
+	if err != nil {
+		return nil, UnwrapStatusError(err)
+	}
+
+	return messageToCadenceValue(result)
+}
+
+func (g *EmulatorGateway) ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments []cadence.Value, id flow.Identifier) (cadence.Value, error) {
+	args, err := cadenceValuesToMessages(arguments)
+	if err != nil {
+		return nil, UnwrapStatusError(err)
+	}
+
+	result, err := g.backend.ExecuteScriptAtBlockID(ctx, id, script, args)
+	if err != nil {
+		return nil, UnwrapStatusError(err)
+	}
+
+	return messageToCadenceValue(result)
+}
+
+func (g *EmulatorGateway) GetLatestBlock(ctx context.Context) (*flow.Block, error) {
+	block, err := g.backend.GetLatestBlock(ctx, true)
 	if err != nil {
 		return nil, UnwrapStatusError(err)
 	}
@@ -228,6 +271,7 @@ func convertBlock(block *flowGo.Block) *flow.Block {
 }
 
 func (g *EmulatorGateway) GetEvents(
+	ctx context.Context,
 	eventType string,
 	startHeight uint64,
 	endHeight uint64,
@@ -235,15 +279,18 @@ func (g *EmulatorGateway) GetEvents(
 	events := make([]flow.BlockEvents, 0)
 
 	for height := startHeight; height <= endHeight; height++ {
-		events = append(events, g.getBlockEvent(height, eventType))
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		events = append(events, g.getBlockEvent(ctx, height, eventType))
 	}
 
 	return events, nil
 }
 
-func (g *EmulatorGateway) getBlockEvent(height uint64, eventType string) flow.BlockEvents {
-	block, _ := g.backend.GetBlockByHeight(g.ctx, height)
-	events, _ := g.backend.GetEventsForBlockIDs(g.ctx, eventType, []flow.Identifier{flow.Identifier(block.ID())})
+func (g *EmulatorGateway) getBlockEvent(ctx context.Context, height uint64, eventType string) flow.BlockEvents {
+	block, _ := g.backend.GetBlockByHeight(ctx, height)
+	events, _ := g.backend.GetEventsForBlockIDs(ctx, eventType, []flow.Identifier{flow.Identifier(block.ID())})
 
 	result := flow.BlockEvents{
 		BlockID:        flow.Identifier(block.ID()),
@@ -262,32 +309,32 @@ func (g *EmulatorGateway) getBlockEvent(height uint64, eventType string) flow.Bl
 	return result
 }
 
-func (g *EmulatorGateway) GetCollection(id flow.Identifier) (*flow.Collection, error) {
-	collection, err := g.backend.GetCollectionByID(g.ctx, id)
+func (g *EmulatorGateway) GetCollection(ctx context.Context, id flow.Identifier) (*flow.Collection, error) {
+	collection, err := g.backend.GetCollectionByID(ctx, id)
 	if err != nil {
 		return nil, UnwrapStatusError(err)
 	}
 	return collection, nil
 }
 
-func (g *EmulatorGateway) GetBlockByID(id flow.Identifier) (*flow.Block, error) {
-	block, err := g.backend.GetBlockByID(g.ctx, id)
+func (g *EmulatorGateway) GetBlockByID(ctx context.Context, id flow.Identifier) (*flow.Block, error) {
+	block, err := g.backend.GetBlockByID(ctx, id)
 	if err != nil {
 		return nil, UnwrapStatusError(err)
 	}
 	return convertBlock(block), nil
 }
 
-func (g *EmulatorGateway) GetBlockByHeight(height uint64) (*flow.Block, error) {
-	block, err := g.backend.GetBlockByHeight(g.ctx, height)
+func (g *EmulatorGateway) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, error) {
+	block, err := g.backend.GetBlockByHeight(ctx, height)
 	if err != nil {
 		return nil, UnwrapStatusError(err)
 	}
 	return convertBlock(block), nil
 }
 
-func (g *EmulatorGateway) GetLatestProtocolStateSnapshot() ([]byte, error) {
-	snapshot, err := g.backend.GetLatestProtocolStateSnapshot(g.ctx)
+func (g *EmulatorGateway) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
+	snapshot, err := g.backend.GetLatestProtocolStateSnapshot(ctx)
 	if err != nil {
 		return nil, UnwrapStatusError(err)
 	}
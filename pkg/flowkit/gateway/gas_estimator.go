@@ -0,0 +1,73 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+
+	emulator "github.com/onflow/flow-emulator"
+	"github.com/onflow/flow-go-sdk"
+	flowGo "github.com/onflow/flow-go/model/flow"
+)
+
+// GasEstimator estimates the computation used by transactions by executing them on an in-process emulator.
+//
+// The emulator is seeded with the contracts the transactions depend on, fetched from the network
+// gateway, the rest of the account storage isn't copied. Transactions are executed without verifying signatures, sequence numbers and storage limits,
+// and the results are not committed, so the emulator state only holds the seeded accounts.
+type GasEstimator struct {
+	fork *networkFork
+}
+
+// NewGasEstimator creates a new gas estimator seeded with the contracts from the network gateway.
+//
+// The computation is metered with the execution effort weights used by the networks with transaction
+// fees if executionEffortWeights is set, otherwise with the default weights.
+func NewGasEstimator(network Gateway, executionEffortWeights bool) (*GasEstimator, error) {
+	fork, err := newNetworkFork(
+		network,
+		0,
+		emulator.WithTransactionValidationEnabled(false),
+		emulator.WithStorageLimitEnabled(false),
+		emulator.WithTransactionFeesEnabled(executionEffortWeights),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GasEstimator{fork: fork}, nil
+}
+
+// ComputationUsed executes the transaction on the emulator and returns the computation it used.
+func (e *GasEstimator) ComputationUsed(ctx context.Context, tx *flow.Transaction) (uint64, error) {
+	dryRun, err := e.fork.transaction(ctx, tx, flowGo.DefaultMaxTransactionGasLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := e.fork.execute(dryRun, false)
+	if err != nil {
+		return 0, err
+	}
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.ComputationUsed, nil
+}
@@ -19,6 +19,8 @@
 package gateway
 
 import (
+	"context"
+
 	"github.com/onflow/flow-cli/pkg/flowkit"
 
 	"github.com/onflow/cadence"
@@ -27,17 +29,21 @@ import (
 
 // Gateway describes blockchain access interface
 type Gateway interface {
-	GetAccount(flow.Address) (*flow.Account, error)
-	SendSignedTransaction(*flowkit.Transaction) (*flow.Transaction, error)
-	GetTransaction(flow.Identifier) (*flow.Transaction, error)
-	GetTransactionResult(flow.Identifier, bool) (*flow.TransactionResult, error)
-	ExecuteScript([]byte, []cadence.Value) (cadence.Value, error)
-	GetLatestBlock() (*flow.Block, error)
-	GetBlockByHeight(uint64) (*flow.Block, error)
-	GetBlockByID(flow.Identifier) (*flow.Block, error)
-	GetEvents(string, uint64, uint64) ([]flow.BlockEvents, error)
-	GetCollection(flow.Identifier) (*flow.Collection, error)
-	GetLatestProtocolStateSnapshot() ([]byte, error)
-	Ping() error
+	GetAccount(context.Context, flow.Address) (*flow.Account, error)
+	GetAccountAtBlockHeight(context.Context, flow.Address, uint64) (*flow.Account, error)
+	GetAccountAtBlockID(context.Context, flow.Address, flow.Identifier) (*flow.Account, error)
+	SendSignedTransaction(context.Context, *flowkit.Transaction) (*flow.Transaction, error)
+	GetTransaction(context.Context, flow.Identifier) (*flow.Transaction, error)
+	GetTransactionResult(context.Context, flow.Identifier, bool) (*flow.TransactionResult, error)
+	ExecuteScript(context.Context, []byte, []cadence.Value) (cadence.Value, error)
+	ExecuteScriptAtBlockHeight(context.Context, []byte, []cadence.Value, uint64) (cadence.Value, error)
+	ExecuteScriptAtBlockID(context.Context, []byte, []cadence.Value, flow.Identifier) (cadence.Value, error)
+	GetLatestBlock(context.Context) (*flow.Block, error)
+	GetBlockByHeight(context.Context, uint64) (*flow.Block, error)
+	GetBlockByID(context.Context, flow.Identifier) (*flow.Block, error)
+	GetEvents(context.Context, string, uint64, uint64) ([]flow.BlockEvents, error)
+	GetCollection(context.Context, flow.Identifier) (*flow.Collection, error)
+	GetLatestProtocolStateSnapshot(context.Context) ([]byte, error)
+	Ping(context.Context) error
 	SecureConnection() bool
 }
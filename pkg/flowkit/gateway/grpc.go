@@ -42,7 +42,6 @@ const maxGRPCMessageSize = 1024 * 1024 * 16
 // GrpcGateway is a gateway implementation that uses the Flow Access gRPC API.
 type GrpcGateway struct {
 	client       *grpcAccess.Client
-	ctx          context.Context
 	secureClient bool
 }
 
@@ -54,7 +53,6 @@ func NewGrpcGateway(host string) (*GrpcGateway, error) {
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxGRPCMessageSize)),
 	)
-	ctx := context.Background()
 
 	if err != nil || gClient == nil {
 		return nil, fmt.Errorf("failed to connect to host %s", host)
@@ -62,7 +60,6 @@ func NewGrpcGateway(host string) (*GrpcGateway, error) {
 
 	return &GrpcGateway{
 		client:       gClient,
-		ctx:          ctx,
 		secureClient: false,
 	}, nil
 }
@@ -79,7 +76,6 @@ func NewSecureGrpcGateway(host, hostNetworkKey string) (*GrpcGateway, error) {
 		secureDialOpts,
 		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxGRPCMessageSize)),
 	)
-	ctx := context.Background()
 
 	if err != nil || gClient == nil {
 		return nil, fmt.Errorf("failed to connect to host %s", host)
@@ -87,14 +83,13 @@ func NewSecureGrpcGateway(host, hostNetworkKey string) (*GrpcGateway, error) {
 
 	return &GrpcGateway{
 		client:       gClient,
-		ctx:          ctx,
 		secureClient: true,
 	}, nil
 }
 
 // GetAccount gets an account by address from the Flow Access API.
-func (g *GrpcGateway) GetAccount(address flow.Address) (*flow.Account, error) {
-	account, err := g.client.GetAccountAtLatestBlock(g.ctx, address)
+func (g *GrpcGateway) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
+	account, err := g.client.GetAccountAtLatestBlock(ctx, address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account with address %s: %w", address, err)
 	}
@@ -102,11 +97,33 @@ func (g *GrpcGateway) GetAccount(address flow.Address) (*flow.Account, error) {
 	return account, nil
 }
 
+// GetAccountAtBlockHeight gets an account by address at the block height from the Flow Access API.
+func (g *GrpcGateway) GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	account, err := g.client.GetAccountAtBlockHeight(ctx, address, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account with address %s at height %d: %w", address, height, err)
+	}
+
+	return account, nil
+}
+
+// GetAccountAtBlockID gets an account by address at the block ID from the Flow Access API.
+//
+// The Access API only supports account queries by height so the block is resolved to its height first.
+func (g *GrpcGateway) GetAccountAtBlockID(ctx context.Context, address flow.Address, id flow.Identifier) (*flow.Account, error) {
+	header, err := g.client.GetBlockHeaderByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block with ID %s: %w", id, err)
+	}
+
+	return g.GetAccountAtBlockHeight(ctx, address, header.Height)
+}
+
 // SendSignedTransaction sends a transaction to flow that is already prepared and signed.
-func (g *GrpcGateway) SendSignedTransaction(transaction *flowkit.Transaction) (*flow.Transaction, error) {
+func (g *GrpcGateway) SendSignedTransaction(ctx context.Context, transaction *flowkit.Transaction) (*flow.Transaction, error) {
 	tx := transaction.FlowTransaction()
 
-	err := g.client.SendTransaction(g.ctx, *tx)
+	err := g.client.SendTransaction(ctx, *tx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to submit transaction: %w", err)
 	}
@@ -115,60 +132,88 @@ func (g *GrpcGateway) SendSignedTransaction(transaction *flowkit.Transaction) (*
 }
 
 // GetTransaction gets a transaction by ID from the Flow Access API.
-func (g *GrpcGateway) GetTransaction(ID flow.Identifier) (*flow.Transaction, error) {
-	return g.client.GetTransaction(g.ctx, ID)
+func (g *GrpcGateway) GetTransaction(ctx context.Context, ID flow.Identifier) (*flow.Transaction, error) {
+	return g.client.GetTransaction(ctx, ID)
 }
 
 // GetTransactionResult gets a transaction result by ID from the Flow Access API.
-func (g *GrpcGateway) GetTransactionResult(ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
-	result, err := g.client.GetTransactionResult(g.ctx, ID)
-	if err != nil {
-		return nil, err
+//
+// If waitSeal is set the result is polled until the transaction is sealed or the context is done.
+func (g *GrpcGateway) GetTransactionResult(ctx context.Context, ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
+	for {
+		result, err := g.client.GetTransactionResult(ctx, ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.Status == flow.TransactionStatusSealed || !waitSeal {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
 	}
+}
 
-	if result.Status != flow.TransactionStatusSealed && waitSeal {
-		time.Sleep(time.Second)
-		return g.GetTransactionResult(ID, waitSeal)
+// ExecuteScript execute a scripts on Flow through the Access API.
+func (g *GrpcGateway) ExecuteScript(ctx context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+
+	value, err := g.client.ExecuteScriptAtLatestBlock(ctx, script, arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit executable script: %w", err)
 	}
 
-	return result, nil
+	return value, nil
 }
 
-// ExecuteScript execute a scripts on Flow through the Access API.
-func (g *GrpcGateway) ExecuteScript(script []byte, arguments []cadence.Value) (cadence.Value, error) {
+// ExecuteScriptAtBlockHeight execute a script on Flow at the block height through the Access API.
+func (g *GrpcGateway) ExecuteScriptAtBlockHeight(ctx context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	value, err := g.client.ExecuteScriptAtBlockHeight(ctx, height, script, arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit executable script at height %d: %w", height, err)
+	}
+
+	return value, nil
+}
 
-	value, err := g.client.ExecuteScriptAtLatestBlock(g.ctx, script, arguments)
+// ExecuteScriptAtBlockID execute a script on Flow at the block ID through the Access API.
+func (g *GrpcGateway) ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments []cadence.Value, id flow.Identifier) (cadence.Value, error) {
+	value, err := g.client.ExecuteScriptAtBlockID(ctx, id, script, arguments)
 	if err != nil {
-		return nil, fmt.Errorf("failed to submit executable script: %w", err)
+		return nil, fmt.Errorf("failed to submit executable script at block %s: %w", id, err)
 	}
 
 	return value, nil
 }
 
 // GetLatestBlock gets the latest block on Flow through the Access API.
-func (g *GrpcGateway) GetLatestBlock() (*flow.Block, error) {
-	return g.client.GetLatestBlock(g.ctx, true)
+func (g *GrpcGateway) GetLatestBlock(ctx context.Context) (*flow.Block, error) {
+	return g.client.GetLatestBlock(ctx, true)
 }
 
 // GetBlockByID get block by ID from the Flow Access API.
-func (g *GrpcGateway) GetBlockByID(id flow.Identifier) (*flow.Block, error) {
-	return g.client.GetBlockByID(g.ctx, id)
+func (g *GrpcGateway) GetBlockByID(ctx context.Context, id flow.Identifier) (*flow.Block, error) {
+	return g.client.GetBlockByID(ctx, id)
 }
 
 // GetBlockByHeight get block by height from the Flow Access API.
-func (g *GrpcGateway) GetBlockByHeight(height uint64) (*flow.Block, error) {
-	return g.client.GetBlockByHeight(g.ctx, height)
+func (g *GrpcGateway) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, error) {
+	return g.client.GetBlockByHeight(ctx, height)
 }
 
 // GetEvents gets events by name and block range from the Flow Access API.
 func (g *GrpcGateway) GetEvents(
+	ctx context.Context,
 	eventType string,
 	startHeight uint64,
 	endHeight uint64,
 ) ([]flow.BlockEvents, error) {
 
 	events, err := g.client.GetEventsForHeightRange(
-		g.ctx,
+		ctx,
 		eventType,
 		startHeight,
 		endHeight,
@@ -178,18 +223,18 @@ func (g *GrpcGateway) GetEvents(
 }
 
 // GetCollection gets a collection by ID from the Flow Access API.
-func (g *GrpcGateway) GetCollection(id flow.Identifier) (*flow.Collection, error) {
-	return g.client.GetCollection(g.ctx, id)
+func (g *GrpcGateway) GetCollection(ctx context.Context, id flow.Identifier) (*flow.Collection, error) {
+	return g.client.GetCollection(ctx, id)
 }
 
 // GetLatestProtocolStateSnapshot gets the latest finalized protocol state snapshot
-func (g *GrpcGateway) GetLatestProtocolStateSnapshot() ([]byte, error) {
-	return g.client.GetLatestProtocolStateSnapshot(g.ctx)
+func (g *GrpcGateway) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
+	return g.client.GetLatestProtocolStateSnapshot(ctx)
 }
 
 // Ping is used to check if the access node is alive and healthy.
-func (g *GrpcGateway) Ping() error {
-	return g.client.Ping(g.ctx)
+func (g *GrpcGateway) Ping(ctx context.Context) error {
+	return g.client.Ping(ctx)
 }
 
 // SecureConnection is used to log warning if a service should be using a secure client but is not
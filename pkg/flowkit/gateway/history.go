@@ -0,0 +1,78 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/output"
+)
+
+// HistoryGateway is a gateway decorator that records the sent transactions and their latest status to the transaction history.
+//
+// Failing to write the history is only logged, so it never fails the call to the network.
+type HistoryGateway struct {
+	Gateway
+	history *flowkit.TransactionHistory
+	network string
+	logger  output.Logger
+}
+
+// NewHistoryGateway returns a new gateway recording the transactions sent on the network with the provided gateway.
+func NewHistoryGateway(gateway Gateway, history *flowkit.TransactionHistory, network string, logger output.Logger) *HistoryGateway {
+	return &HistoryGateway{
+		Gateway: gateway,
+		history: history,
+		network: network,
+		logger:  logger,
+	}
+}
+
+func (h *HistoryGateway) SendSignedTransaction(ctx context.Context, tx *flowkit.Transaction) (*flow.Transaction, error) {
+	sent, err := h.Gateway.SendSignedTransaction(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	historyErr := h.history.Add(flowkit.NewTransactionHistoryEntry(h.network, sent, time.Now().UTC()))
+	if historyErr != nil {
+		h.logger.Error(fmt.Sprintf("Failed to record transaction %s to the history: %s", sent.ID(), historyErr))
+	}
+
+	return sent, nil
+}
+
+func (h *HistoryGateway) GetTransactionResult(ctx context.Context, ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
+	result, err := h.Gateway.GetTransactionResult(ctx, ID, waitSeal)
+	if err != nil {
+		return nil, err
+	}
+
+	historyErr := h.history.Update(ID, result, time.Now().UTC())
+	if historyErr != nil {
+		h.logger.Error(fmt.Sprintf("Failed to update transaction %s in the history: %s", ID, historyErr))
+	}
+
+	return result, nil
+}
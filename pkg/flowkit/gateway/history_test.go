@@ -0,0 +1,81 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/output"
+	"github.com/onflow/flow-cli/pkg/flowkit/tests"
+	"github.com/onflow/flow-cli/pkg/flowkit/tests/mocks"
+)
+
+func TestHistoryGateway(t *testing.T) {
+	ctx := context.Background()
+	logger := output.NewStdoutLogger(output.NoneLog)
+
+	t.Run("Record sent transaction", func(t *testing.T) {
+		history := flowkit.NewTransactionHistory(afero.NewMemMapFs(), flowkit.DefaultTransactionHistoryPath)
+		tx := tests.NewTransaction()
+
+		m := &mocks.Gateway{}
+		m.On(tests.SendSignedTransactionFunc, mock.Anything, mock.Anything).Return(tx, nil)
+		m.On(tests.GetTransactionResultFunc, mock.Anything, mock.Anything, mock.Anything).
+			Return(&flow.TransactionResult{Status: flow.TransactionStatusSealed}, nil)
+		gw := NewHistoryGateway(m, history, "testnet", logger)
+
+		_, err := gw.SendSignedTransaction(ctx, flowkit.NewTransaction())
+		require.NoError(t, err)
+
+		entries, err := history.Search(flowkit.TransactionHistoryFilter{Network: "testnet", Status: "pending"})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, tx.ID().Hex(), entries[0].ID)
+
+		_, err = gw.GetTransactionResult(ctx, tx.ID(), true)
+		require.NoError(t, err)
+
+		entries, err = history.Search(flowkit.TransactionHistoryFilter{Status: "sealed"})
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("Don't record failed send", func(t *testing.T) {
+		history := flowkit.NewTransactionHistory(afero.NewMemMapFs(), flowkit.DefaultTransactionHistoryPath)
+
+		m := &mocks.Gateway{}
+		m.On(tests.SendSignedTransactionFunc, mock.Anything, mock.Anything).Return(nil, errors.New("invalid signature"))
+
+		_, err := NewHistoryGateway(m, history, "testnet", logger).SendSignedTransaction(ctx, flowkit.NewTransaction())
+		assert.EqualError(t, err, "invalid signature")
+
+		entries, err := history.Search(flowkit.TransactionHistoryFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
@@ -0,0 +1,456 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser"
+	emulator "github.com/onflow/flow-emulator"
+	"github.com/onflow/flow-emulator/types"
+	"github.com/onflow/flow-go-sdk"
+	flowGo "github.com/onflow/flow-go/model/flow"
+)
+
+// coreAccountsCount is the number of accounts at the start of the chain the core contracts are deployed to.
+const coreAccountsCount = 4
+
+const deployContractTemplate = `
+transaction(name: String, code: String) {
+	prepare(signer: AuthAccount) {
+		signer.contracts.add(name: name, code: code.decodeHex())
+	}
+}`
+
+// networkFork is an in-process emulator seeded with the contracts network transactions depend on,
+// fetched from the network gateway.
+//
+// Each account a transaction refers to is replaced with an emulator account, and the contracts
+// imported by the transaction are deployed to the replacement accounts with their own imports replaced
+// the same way. Imports of core contracts, from the core accounts of a network, are resolved to the core
+// contracts of the emulator. Only the contracts are copied, the rest of the account storage is not,
+// so transactions using the storage of their authorizers fail on the fork.
+type networkFork struct {
+	network  Gateway
+	emulator *EmulatorGateway
+	// height is the block height the network accounts are fetched at, the latest accounts are fetched if it's zero.
+	height uint64
+	// accounts maps the network addresses to the emulator addresses replacing them.
+	accounts map[flow.Address]flow.Address
+	// deployed contains the names of the contracts deployed to the replacing accounts.
+	deployed map[flow.Address]map[string]bool
+	fetched  map[flow.Address]*flow.Account
+	// core maps the core account addresses of the networks to the emulator core accounts.
+	core map[flow.Address]*flow.Account
+}
+
+func newNetworkFork(network Gateway, height uint64, options ...emulator.Option) (*networkFork, error) {
+	emulatorGateway := NewEmulatorGatewayWithOpts(nil, WithEmulatorOptions(options...))
+
+	f := &networkFork{
+		network:  network,
+		emulator: emulatorGateway,
+		height:   height,
+		accounts: make(map[flow.Address]flow.Address),
+		deployed: make(map[flow.Address]map[string]bool),
+		fetched:  make(map[flow.Address]*flow.Account),
+		core:     make(map[flow.Address]*flow.Account),
+	}
+
+	// the core accounts are at the same indexes on every chain
+	chains := []flowGo.Chain{emulatorGateway.emulator.GetChain(), flowGo.Mainnet.Chain(), flowGo.Testnet.Chain()}
+	for i := uint64(1); i <= coreAccountsCount; i++ {
+		address, err := chains[0].AddressAtIndex(i)
+		if err != nil {
+			return nil, err
+		}
+
+		account, err := emulatorGateway.emulator.GetAccount(flow.BytesToAddress(address.Bytes()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, chain := range chains {
+			networkAddress, err := chain.AddressAtIndex(i)
+			if err != nil {
+				return nil, err
+			}
+			f.core[flow.BytesToAddress(networkAddress.Bytes())] = account
+		}
+	}
+
+	return f, nil
+}
+
+// transaction returns the transaction with its imports, authorizers and arguments replaced for the emulator.
+//
+// The emulator service account proposes the transaction and pays its fees, so only the authorizers are
+// replaced with emulator accounts.
+func (f *networkFork) transaction(ctx context.Context, tx *flow.Transaction, gasLimit uint64) (*flow.Transaction, error) {
+	code, err := f.resolveImports(ctx, tx.Script)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceAddress := f.emulator.emulator.ServiceKey().Address
+	forked := flow.NewTransaction().
+		SetScript(code).
+		SetGasLimit(gasLimit).
+		SetProposalKey(serviceAddress, 0, 0).
+		SetPayer(serviceAddress)
+
+	for _, authorizer := range tx.Authorizers {
+		address, err := f.account(authorizer)
+		if err != nil {
+			return nil, err
+		}
+		forked.AddAuthorizer(address)
+	}
+
+	for i, arg := range tx.Arguments {
+		value, err := jsoncdc.Decode(nil, arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode argument %d: %w", i, err)
+		}
+
+		value, err = f.resolveValue(ctx, value)
+		if err != nil {
+			return nil, err
+		}
+
+		err = forked.AddArgument(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return forked, nil
+}
+
+// execute executes the transaction on the emulator and commits the result if requested.
+//
+// Only failures to execute the transaction are returned as errors, the transaction error is part of the result.
+func (f *networkFork) execute(tx *flow.Transaction, commit bool) (*types.TransactionResult, error) {
+	blockchain := f.emulator.emulator
+
+	block, err := blockchain.GetLatestBlock()
+	if err != nil {
+		return nil, err
+	}
+	tx.SetReferenceBlockID(flow.Identifier(block.ID()))
+
+	err = blockchain.AddTransaction(*tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !commit {
+		defer func() { _ = blockchain.ResetPendingBlock() }()
+		return blockchain.ExecuteNextTransaction()
+	}
+
+	_, results, err := blockchain.ExecuteAndCommitBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return results[len(results)-1], nil
+}
+
+// account returns the emulator account replacing the network account, the account is created if it doesn't exist yet.
+func (f *networkFork) account(address flow.Address) (flow.Address, error) {
+	if replacement, ok := f.accounts[address]; ok {
+		return replacement, nil
+	}
+
+	replacement, err := f.emulator.emulator.CreateAccount(nil, nil)
+	if err != nil {
+		return flow.EmptyAddress, fmt.Errorf("failed to create emulator account for %s: %w", address, err)
+	}
+
+	f.accounts[address] = replacement
+	f.deployed[address] = make(map[string]bool)
+	return replacement, nil
+}
+
+// contract deploys the network contract to the emulator account replacing the network account and returns its address.
+func (f *networkFork) contract(ctx context.Context, address flow.Address, name string) (flow.Address, error) {
+	if core, ok := f.core[address]; ok {
+		if _, ok := core.Contracts[name]; ok {
+			return core.Address, nil
+		}
+	}
+
+	replacement, err := f.account(address)
+	if err != nil {
+		return flow.EmptyAddress, err
+	}
+
+	if f.deployed[address][name] {
+		return replacement, nil
+	}
+	// marked before the imports are resolved, so cyclic imports don't deploy the contract twice
+	f.deployed[address][name] = true
+
+	networkAccount, err := f.networkAccount(ctx, address)
+	if err != nil {
+		return flow.EmptyAddress, err
+	}
+
+	code, ok := networkAccount.Contracts[name]
+	if !ok {
+		return flow.EmptyAddress, fmt.Errorf("contract %s not found on account %s", name, address)
+	}
+
+	code, err = f.resolveImports(ctx, code)
+	if err != nil {
+		return flow.EmptyAddress, err
+	}
+
+	tx := flow.NewTransaction().
+		SetScript([]byte(deployContractTemplate)).
+		SetGasLimit(flowGo.DefaultMaxTransactionGasLimit).
+		SetProposalKey(replacement, 0, 0).
+		SetPayer(f.emulator.emulator.ServiceKey().Address).
+		AddAuthorizer(replacement)
+
+	err = tx.AddArgument(cadence.String(name))
+	if err != nil {
+		return flow.EmptyAddress, err
+	}
+	err = tx.AddArgument(cadence.String(hex.EncodeToString(code)))
+	if err != nil {
+		return flow.EmptyAddress, err
+	}
+
+	result, err := f.execute(tx, true)
+	if err == nil {
+		err = result.Error
+	}
+	if err != nil {
+		return flow.EmptyAddress, fmt.Errorf("failed to deploy contract %s of account %s to the emulator: %w", name, address, err)
+	}
+
+	return replacement, nil
+}
+
+// networkAddresses returns the network addresses keyed by the emulator addresses replacing them.
+//
+// The emulator core accounts are mapped to the core accounts of the chain the network address belongs to.
+func (f *networkFork) networkAddresses(network flow.Address) (map[flow.Address]flow.Address, error) {
+	addresses := make(map[flow.Address]flow.Address, len(f.accounts))
+	for networkAddress, replacement := range f.accounts {
+		addresses[replacement] = networkAddress
+	}
+
+	for _, chain := range []flowGo.Chain{flowGo.Mainnet.Chain(), flowGo.Testnet.Chain()} {
+		if !chain.IsValid(flowGo.BytesToAddress(network.Bytes())) {
+			continue
+		}
+
+		for i := uint64(1); i <= coreAccountsCount; i++ {
+			emulatorAddress, err := f.emulator.emulator.GetChain().AddressAtIndex(i)
+			if err != nil {
+				return nil, err
+			}
+			networkAddress, err := chain.AddressAtIndex(i)
+			if err != nil {
+				return nil, err
+			}
+			addresses[flow.BytesToAddress(emulatorAddress.Bytes())] = flow.BytesToAddress(networkAddress.Bytes())
+		}
+		break
+	}
+
+	return addresses, nil
+}
+
+// networkAccount fetches the network account at the fork height, or the latest account if the height isn't set.
+func (f *networkFork) networkAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
+	if account, ok := f.fetched[address]; ok {
+		return account, nil
+	}
+
+	var account *flow.Account
+	var err error
+	if f.height > 0 {
+		account, err = f.network.GetAccountAtBlockHeight(ctx, address, f.height)
+	} else {
+		account, err = f.network.GetAccount(ctx, address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account %s: %w", address, err)
+	}
+
+	f.fetched[address] = account
+	return account, nil
+}
+
+// resolveImports replaces the addresses of the imports in the code with the emulator accounts the contracts are deployed to.
+func (f *networkFork) resolveImports(ctx context.Context, code []byte) ([]byte, error) {
+	program, err := parser.ParseProgram(code, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	type replacement struct {
+		start, end int
+		address    flow.Address
+	}
+
+	var replacements []replacement
+	for _, imp := range program.ImportDeclarations() {
+		location, ok := imp.Location.(common.AddressLocation)
+		if !ok {
+			continue
+		}
+		address := flow.BytesToAddress(location.Address.Bytes())
+
+		var names []string
+		for _, identifier := range imp.Identifiers {
+			names = append(names, identifier.Identifier)
+		}
+
+		// importing an address without identifiers imports all of its contracts
+		if len(names) == 0 {
+			account, err := f.networkAccount(ctx, address)
+			if err != nil {
+				return nil, err
+			}
+			for name := range account.Contracts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		}
+
+		var target flow.Address
+		for _, name := range names {
+			contractAddress, err := f.contract(ctx, address, name)
+			if err != nil {
+				return nil, err
+			}
+			if target != flow.EmptyAddress && target != contractAddress {
+				return nil, fmt.Errorf("contracts imported from %s are deployed to different emulator accounts", address)
+			}
+			target = contractAddress
+		}
+
+		start := imp.LocationPos.Offset
+		end := start + len("0x")
+		for end < len(code) && isHexLiteralChar(code[end]) {
+			end++
+		}
+
+		replacements = append(replacements, replacement{start: start, end: end, address: target})
+	}
+
+	// replace from the end so the offsets of the other imports stay valid
+	resolved := append([]byte{}, code...)
+	for i := len(replacements) - 1; i >= 0; i-- {
+		r := replacements[i]
+		resolved = append(
+			resolved[:r.start],
+			append([]byte("0x"+r.address.Hex()), resolved[r.end:]...)...,
+		)
+	}
+
+	return resolved, nil
+}
+
+// resolveValue replaces addresses in the argument value with the emulator accounts replacing them,
+// and resolves the imports of contract code passed as hex encoded strings, as the deployment templates do.
+func (f *networkFork) resolveValue(ctx context.Context, value cadence.Value) (cadence.Value, error) {
+	switch v := value.(type) {
+	case cadence.Address:
+		address, err := f.account(flow.Address(v))
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewAddress(address), nil
+
+	case cadence.String:
+		code, err := hex.DecodeString(string(v))
+		if err != nil || !utf8.Valid(code) || !strings.Contains(string(code), "import") {
+			return v, nil
+		}
+
+		// strings which aren't Cadence code are left as they are
+		if _, err := parser.ParseProgram(code, nil); err != nil {
+			return v, nil
+		}
+
+		resolved, err := f.resolveImports(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.String(hex.EncodeToString(resolved)), nil
+
+	case cadence.Optional:
+		if v.Value == nil {
+			return v, nil
+		}
+		inner, err := f.resolveValue(ctx, v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewOptional(inner), nil
+
+	case cadence.Array:
+		values := make([]cadence.Value, len(v.Values))
+		for i, element := range v.Values {
+			resolved, err := f.resolveValue(ctx, element)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = resolved
+		}
+		v.Values = values
+		return v, nil
+
+	case cadence.Dictionary:
+		pairs := make([]cadence.KeyValuePair, len(v.Pairs))
+		for i, pair := range v.Pairs {
+			key, err := f.resolveValue(ctx, pair.Key)
+			if err != nil {
+				return nil, err
+			}
+			val, err := f.resolveValue(ctx, pair.Value)
+			if err != nil {
+				return nil, err
+			}
+			pairs[i] = cadence.KeyValuePair{Key: key, Value: val}
+		}
+		v.Pairs = pairs
+		return v, nil
+	}
+
+	return value, nil
+}
+
+func isHexLiteralChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F' || c == '_'
+}
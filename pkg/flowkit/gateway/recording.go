@@ -0,0 +1,275 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go-sdk"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+)
+
+// RecordingGateway is a gateway decorator that records every call and its result to a cassette.
+//
+// The cassette can be served with the ReplayGateway to repeat the session offline.
+type RecordingGateway struct {
+	gateway Gateway
+	encoder *json.Encoder
+	mu      sync.Mutex
+}
+
+// NewRecordingGateway returns a new gateway recording calls to the provided gateway into the writer.
+func NewRecordingGateway(gateway Gateway, writer io.Writer) *RecordingGateway {
+	return &RecordingGateway{
+		gateway: gateway,
+		encoder: json.NewEncoder(writer),
+	}
+}
+
+// record writes the interaction to the cassette, the response is only written if the call didn't fail.
+func (r *RecordingGateway) record(method string, request string, response interface{}, callErr error) error {
+	interaction := cassetteInteraction{
+		Method:  method,
+		Request: request,
+	}
+
+	if callErr != nil {
+		interaction.Error = callErr.Error()
+	} else {
+		b, err := json.Marshal(response)
+		if err != nil {
+			return fmt.Errorf("failed to record %s: %w", method, err)
+		}
+		interaction.Response = b
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.encoder.Encode(interaction)
+	if err != nil {
+		return fmt.Errorf("failed to record %s: %w", method, err)
+	}
+
+	return callErr
+}
+
+func (r *RecordingGateway) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
+	account, err := r.gateway.GetAccount(ctx, address)
+
+	var response cassetteAccount
+	if err == nil {
+		response = accountToCassette(account)
+	}
+
+	return account, r.record("GetAccount", address.Hex(), response, err)
+}
+
+func (r *RecordingGateway) GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	account, err := r.gateway.GetAccountAtBlockHeight(ctx, address, height)
+
+	var response cassetteAccount
+	if err == nil {
+		response = accountToCassette(account)
+	}
+
+	return account, r.record("GetAccountAtBlockHeight", fmt.Sprintf("%s %d", address.Hex(), height), response, err)
+}
+
+func (r *RecordingGateway) GetAccountAtBlockID(ctx context.Context, address flow.Address, id flow.Identifier) (*flow.Account, error) {
+	account, err := r.gateway.GetAccountAtBlockID(ctx, address, id)
+
+	var response cassetteAccount
+	if err == nil {
+		response = accountToCassette(account)
+	}
+
+	return account, r.record("GetAccountAtBlockID", fmt.Sprintf("%s %s", address.Hex(), id.Hex()), response, err)
+}
+
+func (r *RecordingGateway) SendSignedTransaction(ctx context.Context, tx *flowkit.Transaction) (*flow.Transaction, error) {
+	sent, err := r.gateway.SendSignedTransaction(ctx, tx)
+
+	var response string
+	if err == nil {
+		response = sent.ID().Hex()
+	}
+
+	// transactions are replayed in the order they were sent since the ID depends on the signatures
+	return sent, r.record("SendSignedTransaction", "", response, err)
+}
+
+func (r *RecordingGateway) GetTransaction(ctx context.Context, ID flow.Identifier) (*flow.Transaction, error) {
+	tx, err := r.gateway.GetTransaction(ctx, ID)
+
+	var response string
+	if err == nil {
+		response = hex.EncodeToString(tx.Encode())
+	}
+
+	return tx, r.record("GetTransaction", ID.Hex(), response, err)
+}
+
+func (r *RecordingGateway) GetTransactionResult(ctx context.Context, ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
+	result, err := r.gateway.GetTransactionResult(ctx, ID, waitSeal)
+
+	var response cassetteTransactionResult
+	if err == nil {
+		var convErr error
+		response, convErr = transactionResultToCassette(result)
+		if convErr != nil {
+			return nil, convErr
+		}
+	}
+
+	return result, r.record("GetTransactionResult", ID.Hex(), response, err)
+}
+
+func (r *RecordingGateway) ExecuteScript(ctx context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+	request, err := scriptRequest(script, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := r.gateway.ExecuteScript(ctx, script, arguments)
+	return r.recordScript("ExecuteScript", request, value, err)
+}
+
+func (r *RecordingGateway) ExecuteScriptAtBlockHeight(ctx context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	request, err := scriptRequest(script, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := r.gateway.ExecuteScriptAtBlockHeight(ctx, script, arguments, height)
+	return r.recordScript("ExecuteScriptAtBlockHeight", fmt.Sprintf("%s %d", request, height), value, err)
+}
+
+func (r *RecordingGateway) ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments []cadence.Value, id flow.Identifier) (cadence.Value, error) {
+	request, err := scriptRequest(script, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := r.gateway.ExecuteScriptAtBlockID(ctx, script, arguments, id)
+	return r.recordScript("ExecuteScriptAtBlockID", fmt.Sprintf("%s %s", request, id.Hex()), value, err)
+}
+
+func (r *RecordingGateway) recordScript(method string, request string, value cadence.Value, err error) (cadence.Value, error) {
+	var response json.RawMessage
+	if err == nil {
+		var convErr error
+		response, convErr = jsoncdc.Encode(value)
+		if convErr != nil {
+			return nil, convErr
+		}
+	}
+
+	return value, r.record(method, request, response, err)
+}
+
+func (r *RecordingGateway) GetLatestBlock(ctx context.Context) (*flow.Block, error) {
+	block, err := r.gateway.GetLatestBlock(ctx)
+
+	var response cassetteBlock
+	if err == nil {
+		response = blockToCassette(block)
+	}
+
+	return block, r.record("GetLatestBlock", "", response, err)
+}
+
+func (r *RecordingGateway) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, error) {
+	block, err := r.gateway.GetBlockByHeight(ctx, height)
+
+	var response cassetteBlock
+	if err == nil {
+		response = blockToCassette(block)
+	}
+
+	return block, r.record("GetBlockByHeight", fmt.Sprintf("%d", height), response, err)
+}
+
+func (r *RecordingGateway) GetBlockByID(ctx context.Context, id flow.Identifier) (*flow.Block, error) {
+	block, err := r.gateway.GetBlockByID(ctx, id)
+
+	var response cassetteBlock
+	if err == nil {
+		response = blockToCassette(block)
+	}
+
+	return block, r.record("GetBlockByID", id.Hex(), response, err)
+}
+
+func (r *RecordingGateway) GetEvents(
+	ctx context.Context,
+	eventType string,
+	startHeight uint64,
+	endHeight uint64,
+) ([]flow.BlockEvents, error) {
+	events, err := r.gateway.GetEvents(ctx, eventType, startHeight, endHeight)
+
+	var response []cassetteBlockEvents
+	if err == nil {
+		var convErr error
+		response, convErr = blockEventsToCassette(events)
+		if convErr != nil {
+			return nil, convErr
+		}
+	}
+
+	return events, r.record("GetEvents", eventsRequest(eventType, startHeight, endHeight), response, err)
+}
+
+func (r *RecordingGateway) GetCollection(ctx context.Context, id flow.Identifier) (*flow.Collection, error) {
+	collection, err := r.gateway.GetCollection(ctx, id)
+
+	var response cassetteCollection
+	if err == nil {
+		response = collectionToCassette(collection)
+	}
+
+	return collection, r.record("GetCollection", id.Hex(), response, err)
+}
+
+func (r *RecordingGateway) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
+	snapshot, err := r.gateway.GetLatestProtocolStateSnapshot(ctx)
+	return snapshot, r.record("GetLatestProtocolStateSnapshot", "", snapshot, err)
+}
+
+func (r *RecordingGateway) Ping(ctx context.Context) error {
+	err := r.gateway.Ping(ctx)
+	return r.record("Ping", "", nil, err)
+}
+
+func (r *RecordingGateway) SecureConnection() bool {
+	return r.gateway.SecureConnection()
+}
+
+func eventsRequest(eventType string, startHeight uint64, endHeight uint64) string {
+	return fmt.Sprintf("%s %d %d", eventType, startHeight, endHeight)
+}
@@ -0,0 +1,114 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-cli/pkg/flowkit/tests"
+	"github.com/onflow/flow-cli/pkg/flowkit/tests/mocks"
+)
+
+func TestRecordReplayGateway(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Replay recorded calls", func(t *testing.T) {
+		account := tests.NewAccountWithAddress("01")
+		event := tests.NewEvent(
+			0,
+			"Deposit",
+			[]cadence.Field{{Identifier: "amount", Type: cadence.IntType{}}},
+			[]cadence.Value{cadence.NewInt(10)},
+		)
+		blockEvents := []flow.BlockEvents{{
+			BlockID: flow.HexToID("02"),
+			Height:  2,
+			Events:  []flow.Event{*event},
+		}}
+		script := []byte("pub fun main(a: Int): Int { return a }")
+		args := []cadence.Value{cadence.NewInt(1)}
+
+		m := &mocks.Gateway{}
+		m.On(tests.GetAccountFunc, mock.Anything, mock.Anything).Return(account, nil)
+		m.On(tests.ExecuteScriptFunc, mock.Anything, mock.Anything, mock.Anything).Return(cadence.NewInt(1), nil)
+		m.On(tests.GetEventsFunc, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(blockEvents, nil)
+
+		var cassette bytes.Buffer
+		recorder := NewRecordingGateway(m, &cassette)
+
+		_, err := recorder.GetAccount(ctx, account.Address)
+		require.NoError(t, err)
+		_, err = recorder.ExecuteScript(ctx, script, args)
+		require.NoError(t, err)
+		_, err = recorder.GetEvents(ctx, event.Type, 0, 2)
+		require.NoError(t, err)
+
+		replay, err := NewReplayGateway(&cassette)
+		require.NoError(t, err)
+
+		replayedAccount, err := replay.GetAccount(ctx, account.Address)
+		require.NoError(t, err)
+		assert.Equal(t, account.Address, replayedAccount.Address)
+		assert.Equal(t, account.Balance, replayedAccount.Balance)
+		assert.Equal(t, account.Keys[0].PublicKey.String(), replayedAccount.Keys[0].PublicKey.String())
+
+		value, err := replay.ExecuteScript(ctx, script, args)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(1), value)
+
+		events, err := replay.GetEvents(ctx, event.Type, 0, 2)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, uint64(2), events[0].Height)
+		assert.Equal(t, event.Type, events[0].Events[0].Type)
+		assert.Equal(t, event.Value.Fields, events[0].Events[0].Value.Fields)
+	})
+
+	t.Run("Replay recorded errors", func(t *testing.T) {
+		m := &mocks.Gateway{}
+		m.On(tests.GetLatestBlockFunc, mock.Anything).Return(nil, fmt.Errorf("block not found"))
+
+		var cassette bytes.Buffer
+		_, err := NewRecordingGateway(m, &cassette).GetLatestBlock(ctx)
+		assert.EqualError(t, err, "block not found")
+
+		replay, err := NewReplayGateway(&cassette)
+		require.NoError(t, err)
+
+		_, err = replay.GetLatestBlock(ctx)
+		assert.EqualError(t, err, "block not found")
+	})
+
+	t.Run("Fail on unrecorded call", func(t *testing.T) {
+		replay, err := NewReplayGateway(&bytes.Buffer{})
+		require.NoError(t, err)
+
+		_, err = replay.GetAccount(ctx, flow.HexToAddress("01"))
+		assert.EqualError(t, err, "no recorded response for GetAccount(0000000000000001)")
+	})
+}
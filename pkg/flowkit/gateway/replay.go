@@ -0,0 +1,305 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+)
+
+// ReplayGateway is a gateway that serves responses from a cassette recorded with the RecordingGateway.
+//
+// Calls with the same request are served in the recorded order, once only the last
+// response is left it is served for any further calls. Sent transactions are matched
+// in the order they were recorded, since their IDs depend on the signatures.
+type ReplayGateway struct {
+	interactions   map[string][]cassetteInteraction
+	transactionIDs map[flow.Identifier]flow.Identifier
+	mu             sync.Mutex
+}
+
+// NewReplayGateway returns a new gateway serving the cassette read from the reader.
+func NewReplayGateway(reader io.Reader) (*ReplayGateway, error) {
+	interactions := make(map[string][]cassetteInteraction)
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxGRPCMessageSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var interaction cassetteInteraction
+		err := json.Unmarshal(scanner.Bytes(), &interaction)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cassette: %w", err)
+		}
+
+		key := interactionKey(interaction.Method, interaction.Request)
+		interactions[key] = append(interactions[key], interaction)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	return &ReplayGateway{
+		interactions:   interactions,
+		transactionIDs: make(map[flow.Identifier]flow.Identifier),
+	}, nil
+}
+
+func interactionKey(method string, request string) string {
+	return method + "/" + request
+}
+
+// next returns the next recorded response for the request and decodes it into the response value.
+func (r *ReplayGateway) next(method string, request string, response interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := interactionKey(method, request)
+	recorded := r.interactions[key]
+	if len(recorded) == 0 {
+		return fmt.Errorf("no recorded response for %s(%s)", method, request)
+	}
+
+	interaction := recorded[0]
+	if len(recorded) > 1 {
+		r.interactions[key] = recorded[1:]
+	}
+
+	if interaction.Error != "" {
+		return errors.New(interaction.Error)
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	err := json.Unmarshal(interaction.Response, response)
+	if err != nil {
+		return fmt.Errorf("failed to decode recorded response for %s(%s): %w", method, request, err)
+	}
+
+	return nil
+}
+
+// recordedTransactionID returns the ID the transaction had in the recording.
+func (r *ReplayGateway) recordedTransactionID(ID flow.Identifier) flow.Identifier {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if recorded, ok := r.transactionIDs[ID]; ok {
+		return recorded
+	}
+	return ID
+}
+
+func (r *ReplayGateway) GetAccount(_ context.Context, address flow.Address) (*flow.Account, error) {
+	var account cassetteAccount
+	err := r.next("GetAccount", address.Hex(), &account)
+	if err != nil {
+		return nil, err
+	}
+
+	return account.toFlow()
+}
+
+func (r *ReplayGateway) GetAccountAtBlockHeight(_ context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	var account cassetteAccount
+	err := r.next("GetAccountAtBlockHeight", fmt.Sprintf("%s %d", address.Hex(), height), &account)
+	if err != nil {
+		return nil, err
+	}
+
+	return account.toFlow()
+}
+
+func (r *ReplayGateway) GetAccountAtBlockID(_ context.Context, address flow.Address, id flow.Identifier) (*flow.Account, error) {
+	var account cassetteAccount
+	err := r.next("GetAccountAtBlockID", fmt.Sprintf("%s %s", address.Hex(), id.Hex()), &account)
+	if err != nil {
+		return nil, err
+	}
+
+	return account.toFlow()
+}
+
+func (r *ReplayGateway) SendSignedTransaction(_ context.Context, tx *flowkit.Transaction) (*flow.Transaction, error) {
+	var recordedID string
+	err := r.next("SendSignedTransaction", "", &recordedID)
+	if err != nil {
+		return nil, err
+	}
+
+	sent := tx.FlowTransaction()
+
+	r.mu.Lock()
+	r.transactionIDs[sent.ID()] = flow.HexToID(recordedID)
+	r.mu.Unlock()
+
+	return sent, nil
+}
+
+func (r *ReplayGateway) GetTransaction(_ context.Context, ID flow.Identifier) (*flow.Transaction, error) {
+	var encoded string
+	err := r.next("GetTransaction", r.recordedTransactionID(ID).Hex(), &encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode recorded transaction: %w", err)
+	}
+
+	return flow.DecodeTransaction(b)
+}
+
+func (r *ReplayGateway) GetTransactionResult(_ context.Context, ID flow.Identifier, _ bool) (*flow.TransactionResult, error) {
+	var result cassetteTransactionResult
+	err := r.next("GetTransactionResult", r.recordedTransactionID(ID).Hex(), &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.toFlow()
+}
+
+func (r *ReplayGateway) ExecuteScript(_ context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+	request, err := scriptRequest(script, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.nextScript("ExecuteScript", request)
+}
+
+func (r *ReplayGateway) ExecuteScriptAtBlockHeight(_ context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	request, err := scriptRequest(script, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.nextScript("ExecuteScriptAtBlockHeight", fmt.Sprintf("%s %d", request, height))
+}
+
+func (r *ReplayGateway) ExecuteScriptAtBlockID(_ context.Context, script []byte, arguments []cadence.Value, id flow.Identifier) (cadence.Value, error) {
+	request, err := scriptRequest(script, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.nextScript("ExecuteScriptAtBlockID", fmt.Sprintf("%s %s", request, id.Hex()))
+}
+
+func (r *ReplayGateway) nextScript(method string, request string) (cadence.Value, error) {
+	var value json.RawMessage
+	err := r.next(method, request, &value)
+	if err != nil {
+		return nil, err
+	}
+
+	return messageToCadenceValue(value)
+}
+
+func (r *ReplayGateway) GetLatestBlock(_ context.Context) (*flow.Block, error) {
+	var block cassetteBlock
+	err := r.next("GetLatestBlock", "", &block)
+	if err != nil {
+		return nil, err
+	}
+
+	return block.toFlow(), nil
+}
+
+func (r *ReplayGateway) GetBlockByHeight(_ context.Context, height uint64) (*flow.Block, error) {
+	var block cassetteBlock
+	err := r.next("GetBlockByHeight", fmt.Sprintf("%d", height), &block)
+	if err != nil {
+		return nil, err
+	}
+
+	return block.toFlow(), nil
+}
+
+func (r *ReplayGateway) GetBlockByID(_ context.Context, id flow.Identifier) (*flow.Block, error) {
+	var block cassetteBlock
+	err := r.next("GetBlockByID", id.Hex(), &block)
+	if err != nil {
+		return nil, err
+	}
+
+	return block.toFlow(), nil
+}
+
+func (r *ReplayGateway) GetEvents(
+	_ context.Context,
+	eventType string,
+	startHeight uint64,
+	endHeight uint64,
+) ([]flow.BlockEvents, error) {
+	var events []cassetteBlockEvents
+	err := r.next("GetEvents", eventsRequest(eventType, startHeight, endHeight), &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockEventsFromCassette(events)
+}
+
+func (r *ReplayGateway) GetCollection(_ context.Context, id flow.Identifier) (*flow.Collection, error) {
+	var collection cassetteCollection
+	err := r.next("GetCollection", id.Hex(), &collection)
+	if err != nil {
+		return nil, err
+	}
+
+	return collection.toFlow(), nil
+}
+
+func (r *ReplayGateway) GetLatestProtocolStateSnapshot(_ context.Context) ([]byte, error) {
+	var snapshot []byte
+	err := r.next("GetLatestProtocolStateSnapshot", "", &snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func (r *ReplayGateway) Ping(_ context.Context) error {
+	return r.next("Ping", "", nil)
+}
+
+// SecureConnection placeholder func to complete gateway interface implementation
+func (r *ReplayGateway) SecureConnection() bool {
+	return false
+}
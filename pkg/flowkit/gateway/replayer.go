@@ -0,0 +1,96 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"sort"
+
+	emulator "github.com/onflow/flow-emulator"
+	"github.com/onflow/flow-emulator/types"
+	"github.com/onflow/flow-go-sdk"
+)
+
+// TransactionReplayer re-executes network transactions on an in-process emulator, so they can be debugged locally.
+//
+// The emulator is seeded with the contracts of the transaction authorizers and the contracts imported by the
+// transaction, fetched from the network at the block height the replayer is created with. Only the contracts
+// are copied, so transactions depending on other values stored in the accounts may have different results.
+type TransactionReplayer struct {
+	fork *networkFork
+}
+
+// NewTransactionReplayer creates a new replayer seeded with the account state from the network gateway at the height.
+//
+// The latest account state is used if the height is zero. Transaction fees should be enabled if the
+// network charges them, so the fee events and the computation metering match the network.
+func NewTransactionReplayer(network Gateway, height uint64, transactionFees bool) (*TransactionReplayer, error) {
+	fork, err := newNetworkFork(
+		network,
+		height,
+		emulator.WithTransactionValidationEnabled(false),
+		emulator.WithStorageLimitEnabled(false),
+		emulator.WithTransactionFeesEnabled(transactionFees),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransactionReplayer{fork: fork}, nil
+}
+
+// Replay executes the transaction on the emulator with its gas limit and returns the result with the Cadence logs.
+//
+// The contracts deployed to the emulator have different addresses, so the event types and the
+// values referring to the network accounts differ from the network result.
+func (r *TransactionReplayer) Replay(ctx context.Context, tx *flow.Transaction) (*types.TransactionResult, error) {
+	for _, authorizer := range tx.Authorizers {
+		account, err := r.fork.networkAccount(ctx, authorizer)
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(account.Contracts))
+		for name := range account.Contracts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			_, err = r.fork.contract(ctx, authorizer, name)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	replay, err := r.fork.transaction(ctx, tx, tx.GasLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.fork.execute(replay, false)
+}
+
+// NetworkAddresses returns the network addresses keyed by the emulator addresses replacing them in the replayed results.
+//
+// The emulator core accounts are mapped to the core accounts of the chain of the transaction payer.
+func (r *TransactionReplayer) NetworkAddresses(payer flow.Address) (map[flow.Address]flow.Address, error) {
+	return r.fork.networkAddresses(payer)
+}
@@ -0,0 +1,289 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/config"
+)
+
+// RetryGateway is a gateway decorator that retries calls failing with transient access node errors.
+//
+// Read calls are retried with jittered exponential backoff. Sending a transaction is only
+// retried after the access node reports it doesn't know the transaction, so an
+// accepted transaction is never submitted twice.
+type RetryGateway struct {
+	gateway Gateway
+	policy  config.Retry
+	randMu  sync.Mutex
+	rand    *rand.Rand
+}
+
+// NewRetryGateway returns a new gateway wrapping the provided gateway with the retry policy.
+func NewRetryGateway(gateway Gateway, policy config.Retry) *RetryGateway {
+	return &RetryGateway{
+		gateway: gateway,
+		policy:  policy,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// IsTransientError returns true if the error is a gRPC error which is expected to resolve by retrying.
+func IsTransientError(err error) bool {
+	switch grpcCode(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// grpcCode returns the code of the gRPC error wrapped in the error, or codes.Unknown if there's none.
+func grpcCode(err error) codes.Code {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return codes.Unknown
+	}
+
+	return grpcErr.GRPCStatus().Code()
+}
+
+// retry calls the function until it succeeds, fails with a non-transient error,
+// the attempts are used up or the context is done.
+func (r *RetryGateway) retry(ctx context.Context, call func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = call()
+		if err == nil || !IsTransientError(err) || attempt+1 >= r.policy.Attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.backoff(attempt)):
+		}
+	}
+}
+
+// backoff returns a random duration up to the exponential backoff for the attempt (full jitter).
+func (r *RetryGateway) backoff(attempt int) time.Duration {
+	backoff := r.policy.MaxBackoff
+	if attempt < 32 { // avoid overflowing the shift
+		if exp := r.policy.InitialBackoff << attempt; exp > 0 && exp < backoff {
+			backoff = exp
+		}
+	}
+
+	if backoff <= 0 {
+		return 0
+	}
+
+	r.randMu.Lock()
+	defer r.randMu.Unlock()
+	return time.Duration(r.rand.Int63n(int64(backoff)))
+}
+
+func (r *RetryGateway) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
+	var account *flow.Account
+	err := r.retry(ctx, func() (err error) {
+		account, err = r.gateway.GetAccount(ctx, address)
+		return err
+	})
+	return account, err
+}
+
+func (r *RetryGateway) GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	var account *flow.Account
+	err := r.retry(ctx, func() (err error) {
+		account, err = r.gateway.GetAccountAtBlockHeight(ctx, address, height)
+		return err
+	})
+	return account, err
+}
+
+func (r *RetryGateway) GetAccountAtBlockID(ctx context.Context, address flow.Address, id flow.Identifier) (*flow.Account, error) {
+	var account *flow.Account
+	err := r.retry(ctx, func() (err error) {
+		account, err = r.gateway.GetAccountAtBlockID(ctx, address, id)
+		return err
+	})
+	return account, err
+}
+
+// SendSignedTransaction sends the transaction and on transient errors resubmits it only if
+// the access node reports it doesn't know the transaction.
+//
+// If the access node can't be asked about the transaction, an error is returned without resubmitting it,
+// since the transaction may have been sent.
+func (r *RetryGateway) SendSignedTransaction(ctx context.Context, tx *flowkit.Transaction) (*flow.Transaction, error) {
+	var sent *flow.Transaction
+	err := r.retry(ctx, func() (err error) {
+		sent, err = r.gateway.SendSignedTransaction(ctx, tx)
+		if err == nil || !IsTransientError(err) {
+			return err
+		}
+
+		// the request might have reached the access node even if we got an error back
+		id := tx.FlowTransaction().ID()
+		_, getErr := r.GetTransaction(ctx, id)
+		if getErr == nil {
+			sent = tx.FlowTransaction()
+			return nil
+		}
+		if grpcCode(getErr) == codes.NotFound {
+			return err
+		}
+
+		// not wrapped, so the transaction isn't resubmitted on a transient lookup error
+		return fmt.Errorf(
+			"transaction %s may have been sent, check its status before sending it again: %s (failed to get the transaction: %s)",
+			id,
+			err,
+			getErr,
+		)
+	})
+	return sent, err
+}
+
+func (r *RetryGateway) GetTransaction(ctx context.Context, ID flow.Identifier) (*flow.Transaction, error) {
+	var tx *flow.Transaction
+	err := r.retry(ctx, func() (err error) {
+		tx, err = r.gateway.GetTransaction(ctx, ID)
+		return err
+	})
+	return tx, err
+}
+
+func (r *RetryGateway) GetTransactionResult(ctx context.Context, ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
+	var result *flow.TransactionResult
+	err := r.retry(ctx, func() (err error) {
+		result, err = r.gateway.GetTransactionResult(ctx, ID, waitSeal)
+		return err
+	})
+	return result, err
+}
+
+func (r *RetryGateway) ExecuteScript(ctx context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+	var value cadence.Value
+	err := r.retry(ctx, func() (err error) {
+		value, err = r.gateway.ExecuteScript(ctx, script, arguments)
+		return err
+	})
+	return value, err
+}
+
+func (r *RetryGateway) ExecuteScriptAtBlockHeight(ctx context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	var value cadence.Value
+	err := r.retry(ctx, func() (err error) {
+		value, err = r.gateway.ExecuteScriptAtBlockHeight(ctx, script, arguments, height)
+		return err
+	})
+	return value, err
+}
+
+func (r *RetryGateway) ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments []cadence.Value, id flow.Identifier) (cadence.Value, error) {
+	var value cadence.Value
+	err := r.retry(ctx, func() (err error) {
+		value, err = r.gateway.ExecuteScriptAtBlockID(ctx, script, arguments, id)
+		return err
+	})
+	return value, err
+}
+
+func (r *RetryGateway) GetLatestBlock(ctx context.Context) (*flow.Block, error) {
+	var block *flow.Block
+	err := r.retry(ctx, func() (err error) {
+		block, err = r.gateway.GetLatestBlock(ctx)
+		return err
+	})
+	return block, err
+}
+
+func (r *RetryGateway) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, error) {
+	var block *flow.Block
+	err := r.retry(ctx, func() (err error) {
+		block, err = r.gateway.GetBlockByHeight(ctx, height)
+		return err
+	})
+	return block, err
+}
+
+func (r *RetryGateway) GetBlockByID(ctx context.Context, id flow.Identifier) (*flow.Block, error) {
+	var block *flow.Block
+	err := r.retry(ctx, func() (err error) {
+		block, err = r.gateway.GetBlockByID(ctx, id)
+		return err
+	})
+	return block, err
+}
+
+func (r *RetryGateway) GetEvents(
+	ctx context.Context,
+	eventType string,
+	startHeight uint64,
+	endHeight uint64,
+) ([]flow.BlockEvents, error) {
+	var events []flow.BlockEvents
+	err := r.retry(ctx, func() (err error) {
+		events, err = r.gateway.GetEvents(ctx, eventType, startHeight, endHeight)
+		return err
+	})
+	return events, err
+}
+
+func (r *RetryGateway) GetCollection(ctx context.Context, id flow.Identifier) (*flow.Collection, error) {
+	var collection *flow.Collection
+	err := r.retry(ctx, func() (err error) {
+		collection, err = r.gateway.GetCollection(ctx, id)
+		return err
+	})
+	return collection, err
+}
+
+func (r *RetryGateway) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
+	var snapshot []byte
+	err := r.retry(ctx, func() (err error) {
+		snapshot, err = r.gateway.GetLatestProtocolStateSnapshot(ctx)
+		return err
+	})
+	return snapshot, err
+}
+
+func (r *RetryGateway) Ping(ctx context.Context) error {
+	return r.retry(ctx, func() error {
+		return r.gateway.Ping(ctx)
+	})
+}
+
+func (r *RetryGateway) SecureConnection() bool {
+	return r.gateway.SecureConnection()
+}
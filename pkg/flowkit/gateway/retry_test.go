@@ -0,0 +1,130 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/config"
+	"github.com/onflow/flow-cli/pkg/flowkit/tests"
+	"github.com/onflow/flow-cli/pkg/flowkit/tests/mocks"
+)
+
+func TestRetryGateway(t *testing.T) {
+	ctx := context.Background()
+	policy := config.Retry{Attempts: 3}
+	unavailable := fmt.Errorf("failed: %w", status.Error(codes.Unavailable, "unavailable"))
+
+	t.Run("Retry transient error", func(t *testing.T) {
+		m := &mocks.Gateway{}
+		m.On(tests.GetAccountFunc, mock.Anything, mock.Anything).Return(nil, unavailable).Once()
+		m.On(tests.GetAccountFunc, mock.Anything, mock.Anything).Return(tests.NewAccountWithAddress("01"), nil).Once()
+
+		account, err := NewRetryGateway(m, policy).GetAccount(ctx, flow.HexToAddress("01"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, flow.HexToAddress("01"), account.Address)
+		m.AssertNumberOfCalls(t, tests.GetAccountFunc, 2)
+	})
+
+	t.Run("Stop after attempts", func(t *testing.T) {
+		m := &mocks.Gateway{}
+		m.On(tests.GetLatestBlockFunc, mock.Anything).Return(nil, unavailable)
+
+		_, err := NewRetryGateway(m, policy).GetLatestBlock(ctx)
+
+		assert.ErrorIs(t, err, unavailable)
+		m.AssertNumberOfCalls(t, tests.GetLatestBlockFunc, 3)
+	})
+
+	t.Run("Don't retry other errors", func(t *testing.T) {
+		m := &mocks.Gateway{}
+		m.On(tests.GetLatestBlockFunc, mock.Anything).Return(nil, status.Error(codes.NotFound, "not found"))
+
+		_, err := NewRetryGateway(m, policy).GetLatestBlock(ctx)
+
+		assert.Error(t, err)
+		m.AssertNumberOfCalls(t, tests.GetLatestBlockFunc, 1)
+	})
+
+	t.Run("Don't resend known transaction", func(t *testing.T) {
+		tx := flowkit.NewTransaction()
+		m := &mocks.Gateway{}
+		m.On(tests.SendSignedTransactionFunc, mock.Anything, mock.Anything).Return(nil, unavailable)
+		m.On(tests.GetTransactionFunc, mock.Anything, mock.Anything).Return(tx.FlowTransaction(), nil)
+
+		sent, err := NewRetryGateway(m, policy).SendSignedTransaction(ctx, tx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tx.FlowTransaction().ID(), sent.ID())
+		m.AssertNumberOfCalls(t, tests.SendSignedTransactionFunc, 1)
+		m.AssertCalled(t, tests.GetTransactionFunc, mock.Anything, tx.FlowTransaction().ID())
+	})
+
+	t.Run("Retry transaction lookup", func(t *testing.T) {
+		tx := flowkit.NewTransaction()
+		m := &mocks.Gateway{}
+		m.On(tests.SendSignedTransactionFunc, mock.Anything, mock.Anything).Return(nil, unavailable)
+		m.On(tests.GetTransactionFunc, mock.Anything, mock.Anything).Return(nil, unavailable).Once()
+		m.On(tests.GetTransactionFunc, mock.Anything, mock.Anything).Return(tx.FlowTransaction(), nil).Once()
+
+		sent, err := NewRetryGateway(m, policy).SendSignedTransaction(ctx, tx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tx.FlowTransaction().ID(), sent.ID())
+		m.AssertNumberOfCalls(t, tests.SendSignedTransactionFunc, 1)
+		m.AssertNumberOfCalls(t, tests.GetTransactionFunc, 2)
+	})
+
+	t.Run("Don't resend transaction with unknown state", func(t *testing.T) {
+		tx := flowkit.NewTransaction()
+		m := &mocks.Gateway{}
+		m.On(tests.SendSignedTransactionFunc, mock.Anything, mock.Anything).Return(nil, unavailable)
+		m.On(tests.GetTransactionFunc, mock.Anything, mock.Anything).Return(nil, unavailable)
+
+		_, err := NewRetryGateway(m, policy).SendSignedTransaction(ctx, tx)
+
+		assert.ErrorContains(t, err, "may have been sent")
+		assert.False(t, IsTransientError(err))
+		m.AssertNumberOfCalls(t, tests.SendSignedTransactionFunc, 1)
+		m.AssertNumberOfCalls(t, tests.GetTransactionFunc, 3)
+	})
+
+	t.Run("Resend unknown transaction", func(t *testing.T) {
+		tx := flowkit.NewTransaction()
+		m := &mocks.Gateway{}
+		m.On(tests.SendSignedTransactionFunc, mock.Anything, mock.Anything).Return(nil, unavailable).Once()
+		m.On(tests.SendSignedTransactionFunc, mock.Anything, mock.Anything).Return(tx.FlowTransaction(), nil).Once()
+		m.On(tests.GetTransactionFunc, mock.Anything, mock.Anything).Return(nil, status.Error(codes.NotFound, "not found"))
+
+		_, err := NewRetryGateway(m, policy).SendSignedTransaction(ctx, tx)
+
+		assert.NoError(t, err)
+		m.AssertNumberOfCalls(t, tests.SendSignedTransactionFunc, 2)
+	})
+}
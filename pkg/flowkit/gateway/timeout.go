@@ -0,0 +1,170 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+)
+
+// TimeoutGateway is a gateway decorator that bounds each call with a timeout.
+//
+// Waiting for a transaction to be sealed is done by polling the transaction result,
+// so each poll is bounded by the timeout instead of the whole wait.
+type TimeoutGateway struct {
+	gateway Gateway
+	timeout time.Duration
+	// pollInterval is the interval at which the transaction result is polled while waiting for the seal.
+	pollInterval time.Duration
+}
+
+// NewTimeoutGateway returns a new gateway wrapping the provided gateway with the per-call timeout.
+func NewTimeoutGateway(gateway Gateway, timeout time.Duration) *TimeoutGateway {
+	return &TimeoutGateway{
+		gateway:      gateway,
+		timeout:      timeout,
+		pollInterval: time.Second,
+	}
+}
+
+func (t *TimeoutGateway) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.GetAccount(ctx, address)
+}
+
+func (t *TimeoutGateway) GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.GetAccountAtBlockHeight(ctx, address, height)
+}
+
+func (t *TimeoutGateway) GetAccountAtBlockID(ctx context.Context, address flow.Address, id flow.Identifier) (*flow.Account, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.GetAccountAtBlockID(ctx, address, id)
+}
+
+func (t *TimeoutGateway) SendSignedTransaction(ctx context.Context, tx *flowkit.Transaction) (*flow.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.SendSignedTransaction(ctx, tx)
+}
+
+func (t *TimeoutGateway) GetTransaction(ctx context.Context, id flow.Identifier) (*flow.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.GetTransaction(ctx, id)
+}
+
+// GetTransactionResult returns the transaction result, if waitSeal is set the result is polled
+// with a timeout for each poll until the transaction is sealed or the context is done.
+func (t *TimeoutGateway) GetTransactionResult(ctx context.Context, id flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
+	for {
+		result, err := t.getTransactionResult(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.Status == flow.TransactionStatusSealed || !waitSeal {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(t.pollInterval):
+		}
+	}
+}
+
+func (t *TimeoutGateway) getTransactionResult(ctx context.Context, id flow.Identifier) (*flow.TransactionResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.GetTransactionResult(ctx, id, false)
+}
+
+func (t *TimeoutGateway) ExecuteScript(ctx context.Context, script []byte, args []cadence.Value) (cadence.Value, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.ExecuteScript(ctx, script, args)
+}
+
+func (t *TimeoutGateway) ExecuteScriptAtBlockHeight(ctx context.Context, script []byte, args []cadence.Value, height uint64) (cadence.Value, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.ExecuteScriptAtBlockHeight(ctx, script, args, height)
+}
+
+func (t *TimeoutGateway) ExecuteScriptAtBlockID(ctx context.Context, script []byte, args []cadence.Value, id flow.Identifier) (cadence.Value, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.ExecuteScriptAtBlockID(ctx, script, args, id)
+}
+
+func (t *TimeoutGateway) GetLatestBlock(ctx context.Context) (*flow.Block, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.GetLatestBlock(ctx)
+}
+
+func (t *TimeoutGateway) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.GetBlockByHeight(ctx, height)
+}
+
+func (t *TimeoutGateway) GetBlockByID(ctx context.Context, id flow.Identifier) (*flow.Block, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.GetBlockByID(ctx, id)
+}
+
+func (t *TimeoutGateway) GetEvents(ctx context.Context, eventType string, startHeight uint64, endHeight uint64) ([]flow.BlockEvents, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.GetEvents(ctx, eventType, startHeight, endHeight)
+}
+
+func (t *TimeoutGateway) GetCollection(ctx context.Context, id flow.Identifier) (*flow.Collection, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.GetCollection(ctx, id)
+}
+
+func (t *TimeoutGateway) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.GetLatestProtocolStateSnapshot(ctx)
+}
+
+func (t *TimeoutGateway) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.gateway.Ping(ctx)
+}
+
+func (t *TimeoutGateway) SecureConnection() bool {
+	return t.gateway.SecureConnection()
+}
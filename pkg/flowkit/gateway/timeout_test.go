@@ -0,0 +1,80 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/onflow/flow-cli/pkg/flowkit/tests"
+	"github.com/onflow/flow-cli/pkg/flowkit/tests/mocks"
+)
+
+func TestTimeoutGateway(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Timeout call", func(t *testing.T) {
+		m := &mocks.Gateway{}
+		m.On(tests.GetAccountFunc, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				<-args.Get(0).(context.Context).Done()
+			}).
+			Return(nil, context.DeadlineExceeded)
+
+		_, err := NewTimeoutGateway(m, 10*time.Millisecond).GetAccount(ctx, flow.HexToAddress("01"))
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Call within timeout", func(t *testing.T) {
+		m := &mocks.Gateway{}
+		m.On(tests.GetAccountFunc, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				_, ok := args.Get(0).(context.Context).Deadline()
+				assert.True(t, ok)
+			}).
+			Return(tests.NewAccountWithAddress("01"), nil)
+
+		account, err := NewTimeoutGateway(m, time.Minute).GetAccount(ctx, flow.HexToAddress("01"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, flow.HexToAddress("01"), account.Address)
+	})
+
+	t.Run("Poll until sealed", func(t *testing.T) {
+		m := &mocks.Gateway{}
+		m.On(tests.GetTransactionResultFunc, mock.Anything, mock.Anything, false).
+			Return(&flow.TransactionResult{Status: flow.TransactionStatusPending}, nil).Once()
+		m.On(tests.GetTransactionResultFunc, mock.Anything, mock.Anything, false).
+			Return(&flow.TransactionResult{Status: flow.TransactionStatusSealed}, nil).Once()
+
+		gw := NewTimeoutGateway(m, time.Minute)
+		gw.pollInterval = time.Millisecond
+		result, err := gw.GetTransactionResult(ctx, flow.EmptyID, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusSealed, result.Status)
+		m.AssertNumberOfCalls(t, tests.GetTransactionResultFunc, 2)
+	})
+}
@@ -0,0 +1,122 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// DefaultKeyRotationPath is the path of the pending key rotation, relative to the project configuration.
+const DefaultKeyRotationPath = ".flow-key-rotation.json"
+
+// KeyRotation is a pending rotation of an account key, saved before the rotation transaction is sent,
+// so an interrupted rotation can be resumed without losing the new private key.
+type KeyRotation struct {
+	Account       string `json:"account"`
+	Address       string `json:"address"`
+	RevokeIndex   int    `json:"revokeIndex"`
+	SigAlgo       string `json:"sigAlgo"`
+	HashAlgo      string `json:"hashAlgo"`
+	PrivateKey    string `json:"privateKey"`
+	TransactionID string `json:"transactionId,omitempty"`
+}
+
+// NewKeyRotation creates a rotation of the account key to the private key.
+func NewKeyRotation(account *Account, privateKey crypto.PrivateKey, hashAlgo crypto.HashAlgorithm) *KeyRotation {
+	return &KeyRotation{
+		Account:     account.Name(),
+		Address:     account.Address().Hex(),
+		RevokeIndex: account.Key().Index(),
+		SigAlgo:     privateKey.Algorithm().String(),
+		HashAlgo:    hashAlgo.String(),
+		PrivateKey:  hex.EncodeToString(privateKey.Encode()),
+	}
+}
+
+// ParseKeyRotation parses the JSON encoded rotation.
+func ParseKeyRotation(data []byte) (*KeyRotation, error) {
+	var rotation KeyRotation
+	err := json.Unmarshal(data, &rotation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key rotation: %w", err)
+	}
+
+	if _, err := rotation.AccountAddress(); err != nil {
+		return nil, err
+	}
+
+	if _, err := rotation.NewPrivateKey(); err != nil {
+		return nil, err
+	}
+
+	if rotation.NewHashAlgo() == crypto.UnknownHashAlgorithm {
+		return nil, fmt.Errorf("invalid hash algorithm %s in the key rotation", rotation.HashAlgo)
+	}
+
+	return &rotation, nil
+}
+
+// AccountAddress returns the address of the rotated account.
+func (r *KeyRotation) AccountAddress() (flow.Address, error) {
+	address, err := hexToAddress(r.Address)
+	if err != nil {
+		return flow.EmptyAddress, fmt.Errorf("invalid account in the key rotation: %w", err)
+	}
+
+	return address, nil
+}
+
+// NewPrivateKey returns the private key the account key is rotated to.
+func (r *KeyRotation) NewPrivateKey() (crypto.PrivateKey, error) {
+	privateKey, err := crypto.DecodePrivateKeyHex(
+		crypto.StringToSignatureAlgorithm(r.SigAlgo),
+		strings.TrimPrefix(r.PrivateKey, "0x"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key in the key rotation: %w", err)
+	}
+
+	return privateKey, nil
+}
+
+// NewHashAlgo returns the hash algorithm of the new account key.
+func (r *KeyRotation) NewHashAlgo() crypto.HashAlgorithm {
+	return crypto.StringToHashAlgorithm(r.HashAlgo)
+}
+
+// MarshalIndent returns the rotation as indented JSON.
+func (r *KeyRotation) MarshalIndent() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,62 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit_test
+
+import (
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/tests"
+)
+
+func TestKeyRotation(t *testing.T) {
+	alice := tests.Alice()
+	privateKey := tests.PrivKeys()[0]
+
+	t.Run("Round Trip", func(t *testing.T) {
+		rotation := flowkit.NewKeyRotation(alice, privateKey, crypto.SHA2_256)
+		rotation.TransactionID = "7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7"
+
+		data, err := rotation.MarshalIndent()
+		require.NoError(t, err)
+
+		parsed, err := flowkit.ParseKeyRotation(data)
+		require.NoError(t, err)
+		assert.Equal(t, rotation, parsed)
+
+		address, err := parsed.AccountAddress()
+		require.NoError(t, err)
+		assert.Equal(t, alice.Address(), address)
+
+		parsedKey, err := parsed.NewPrivateKey()
+		require.NoError(t, err)
+		assert.True(t, privateKey.Equals(parsedKey))
+		assert.Equal(t, crypto.SHA2_256, parsed.NewHashAlgo())
+		assert.Equal(t, 0, parsed.RevokeIndex)
+	})
+
+	t.Run("Invalid Rotation", func(t *testing.T) {
+		_, err := flowkit.ParseKeyRotation([]byte(`{"account": "Alice", "address": "01cf0e2f2f715450", "sigAlgo": "ECDSA_P256", "privateKey": "foo"}`))
+		assert.ErrorContains(t, err, "invalid private key in the key rotation")
+	})
+}
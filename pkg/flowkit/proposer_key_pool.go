@@ -0,0 +1,132 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// ProposerKey is a proposal key leased from a proposer key pool.
+type ProposerKey struct {
+	Index          int
+	SequenceNumber uint64
+}
+
+// ProposerKeyPool hands out the proposal keys of one account to concurrent transactions.
+//
+// The keys are leased round-robin and the sequence numbers are tracked locally, so transactions
+// sent in parallel by the same account don't collide on the sequence number. The pool keys must
+// have the public key of the account default key and should only be used through the pool.
+type ProposerKeyPool struct {
+	account *Account
+	keys    chan *ProposerKey
+}
+
+// NewProposerKeyPool creates a pool of the account keys at the key indexes, starting from the on-chain sequence numbers.
+func NewProposerKeyPool(account *Account, onChain *flow.Account, keyIndexes []int) (*ProposerKeyPool, error) {
+	if account.Key() == nil {
+		return nil, fmt.Errorf("account %s has no key", account.Name())
+	}
+
+	if len(keyIndexes) == 0 {
+		return nil, fmt.Errorf("proposer key pool requires at least one key")
+	}
+
+	pool := &ProposerKeyPool{
+		account: account,
+		keys:    make(chan *ProposerKey, len(keyIndexes)),
+	}
+
+	for _, index := range keyIndexes {
+		if index < 0 || index >= len(onChain.Keys) {
+			return nil, fmt.Errorf("account %s has no key with index %d", onChain.Address, index)
+		}
+
+		key := onChain.Keys[index]
+		if key.Revoked {
+			return nil, fmt.Errorf("account key with index %d is revoked", index)
+		}
+
+		pool.keys <- &ProposerKey{
+			Index:          key.Index,
+			SequenceNumber: key.SequenceNumber,
+		}
+	}
+
+	return pool, nil
+}
+
+// Address of the account proposing the transactions.
+func (p *ProposerKeyPool) Address() flow.Address {
+	return p.account.Address()
+}
+
+// Size is the number of keys in the pool.
+func (p *ProposerKeyPool) Size() int {
+	return cap(p.keys)
+}
+
+// Acquire leases the next free proposal key, waiting for a key to be released if all the keys are in use.
+func (p *ProposerKeyPool) Acquire(ctx context.Context) (*ProposerKey, error) {
+	select {
+	case key := <-p.keys:
+		return key, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Release returns the leased key to the pool, the sequence number is incremented if the transaction was sent.
+func (p *ProposerKeyPool) Release(key *ProposerKey, sent bool) {
+	if sent {
+		key.SequenceNumber++
+	}
+
+	p.keys <- key
+}
+
+// ReleaseWithSequenceNumber returns the leased key to the pool with the sequence number, used when the
+// tracked sequence number can't be trusted because it's unknown whether the transaction was received.
+func (p *ProposerKeyPool) ReleaseWithSequenceNumber(key *ProposerKey, sequenceNumber uint64) {
+	key.SequenceNumber = sequenceNumber
+	p.keys <- key
+}
+
+// Signer returns the account signing with the leased proposal key.
+func (p *ProposerKeyPool) Signer(key *ProposerKey) *Account {
+	return NewAccount(p.account.Name()).
+		SetAddress(p.account.Address()).
+		SetKey(&proposerAccountKey{
+			AccountKey: p.account.Key(),
+			index:      key.Index,
+		})
+}
+
+// proposerAccountKey signs with the account key under the key index of a pool key.
+type proposerAccountKey struct {
+	AccountKey
+	index int
+}
+
+func (k *proposerAccountKey) Index() int {
+	return k.index
+}
@@ -0,0 +1,130 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/tests"
+)
+
+func TestProposerKeyPool(t *testing.T) {
+	account := tests.Alice()
+	onChain := &flow.Account{
+		Address: account.Address(),
+		Keys: []*flow.AccountKey{
+			{Index: 0, SequenceNumber: 7},
+			{Index: 1, SequenceNumber: 3},
+			{Index: 2, SequenceNumber: 5},
+			{Index: 3, Revoked: true},
+		},
+	}
+
+	t.Run("Round Robin", func(t *testing.T) {
+		pool, err := flowkit.NewProposerKeyPool(account, onChain, []int{1, 2})
+		require.NoError(t, err)
+		assert.Equal(t, 2, pool.Size())
+		assert.Equal(t, account.Address(), pool.Address())
+
+		var used []flowkit.ProposerKey
+		for i := 0; i < 4; i++ {
+			key, err := pool.Acquire(context.Background())
+			require.NoError(t, err)
+			used = append(used, *key)
+			pool.Release(key, true)
+		}
+
+		assert.Equal(t, []flowkit.ProposerKey{
+			{Index: 1, SequenceNumber: 3},
+			{Index: 2, SequenceNumber: 5},
+			{Index: 1, SequenceNumber: 4},
+			{Index: 2, SequenceNumber: 6},
+		}, used)
+	})
+
+	t.Run("Release With On-Chain Sequence Number", func(t *testing.T) {
+		pool, err := flowkit.NewProposerKeyPool(account, onChain, []int{1})
+		require.NoError(t, err)
+
+		key, err := pool.Acquire(context.Background())
+		require.NoError(t, err)
+		pool.ReleaseWithSequenceNumber(key, 9)
+
+		key, err = pool.Acquire(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(9), key.SequenceNumber)
+	})
+
+	t.Run("Unsent Transaction Keeps Sequence Number", func(t *testing.T) {
+		pool, err := flowkit.NewProposerKeyPool(account, onChain, []int{1})
+		require.NoError(t, err)
+
+		key, err := pool.Acquire(context.Background())
+		require.NoError(t, err)
+		pool.Release(key, false)
+
+		key, err = pool.Acquire(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), key.SequenceNumber)
+	})
+
+	t.Run("Acquire Waits For Release", func(t *testing.T) {
+		pool, err := flowkit.NewProposerKeyPool(account, onChain, []int{1})
+		require.NoError(t, err)
+
+		_, err = pool.Acquire(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = pool.Acquire(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Signer Uses Pool Key Index", func(t *testing.T) {
+		pool, err := flowkit.NewProposerKeyPool(account, onChain, []int{2})
+		require.NoError(t, err)
+
+		key, err := pool.Acquire(context.Background())
+		require.NoError(t, err)
+
+		signer := pool.Signer(key)
+		assert.Equal(t, account.Address(), signer.Address())
+		assert.Equal(t, 2, signer.Key().Index())
+		assert.Equal(t, account.Key().SigAlgo(), signer.Key().SigAlgo())
+	})
+
+	t.Run("Invalid Keys", func(t *testing.T) {
+		_, err := flowkit.NewProposerKeyPool(account, onChain, nil)
+		assert.EqualError(t, err, "proposer key pool requires at least one key")
+
+		_, err = flowkit.NewProposerKeyPool(account, onChain, []int{4})
+		assert.EqualError(t, err, "account 0000000000000001 has no key with index 4")
+
+		_, err = flowkit.NewProposerKeyPool(account, onChain, []int{3})
+		assert.EqualError(t, err, "account key with index 3 is revoked")
+	})
+}
@@ -19,7 +19,11 @@
 package services
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/onflow/flow-cli/pkg/flowkit/contracts"
@@ -44,6 +48,7 @@ type Accounts struct {
 	gateway gateway.Gateway
 	state   *flowkit.State
 	logger  output.Logger
+	gas     *gasEstimation
 }
 
 // NewAccounts returns a new accounts service.
@@ -56,21 +61,49 @@ func NewAccounts(
 		gateway: gateway,
 		state:   state,
 		logger:  logger,
+		gas:     newGasEstimation(gateway, logger),
 	}
 }
 
 // Get returns an account by on address.
+//
+// Deprecated: use GetWithContext, Get is kept for callers without a context and can't be cancelled.
 func (a *Accounts) Get(address flow.Address) (*flow.Account, error) {
+	return a.GetWithContext(context.Background(), address)
+}
+
+// GetWithContext returns an account by on address.
+func (a *Accounts) GetWithContext(ctx context.Context, address flow.Address) (*flow.Account, error) {
 	a.logger.StartProgress(fmt.Sprintf("Loading %s...", address))
 
-	account, err := a.gateway.GetAccount(address)
+	account, err := a.gateway.GetAccount(ctx, address)
+	a.logger.StopProgress()
+
+	return account, err
+}
+
+// GetAtBlockHeight returns an account by address as it was at the block height.
+func (a *Accounts) GetAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	a.logger.StartProgress(fmt.Sprintf("Loading %s at height %d...", address, height))
+
+	account, err := a.gateway.GetAccountAtBlockHeight(ctx, address, height)
+	a.logger.StopProgress()
+
+	return account, err
+}
+
+// GetAtBlockID returns an account by address as it was at the block ID.
+func (a *Accounts) GetAtBlockID(ctx context.Context, address flow.Address, id flow.Identifier) (*flow.Account, error) {
+	a.logger.StartProgress(fmt.Sprintf("Loading %s at block %s...", address, id))
+
+	account, err := a.gateway.GetAccountAtBlockID(ctx, address, id)
 	a.logger.StopProgress()
 
 	return account, err
 }
 
 // StakingInfo returns the staking and delegation information for an account.
-func (a *Accounts) StakingInfo(address flow.Address) ([]map[string]interface{}, []map[string]interface{}, error) {
+func (a *Accounts) StakingInfo(ctx context.Context, address flow.Address) ([]map[string]interface{}, []map[string]interface{}, error) {
 	a.logger.StartProgress(fmt.Sprintf("Fetching info for %s...", address.String()))
 	defer a.logger.StopProgress()
 
@@ -92,12 +125,12 @@ func (a *Accounts) StakingInfo(address flow.Address) ([]map[string]interface{},
 	stakingInfoScript := tmpl.GenerateCollectionGetAllNodeInfoScript(env)
 	delegationInfoScript := tmpl.GenerateCollectionGetAllDelegatorInfoScript(env)
 
-	stakingValue, err := a.gateway.ExecuteScript(stakingInfoScript, cadenceAddress)
+	stakingValue, err := a.gateway.ExecuteScript(ctx, stakingInfoScript, cadenceAddress)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error getting staking info: %s", err.Error())
 	}
 
-	delegationValue, err := a.gateway.ExecuteScript(delegationInfoScript, cadenceAddress)
+	delegationValue, err := a.gateway.ExecuteScript(ctx, delegationInfoScript, cadenceAddress)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error getting delegation info: %s", err.Error())
 	}
@@ -124,7 +157,7 @@ func (a *Accounts) StakingInfo(address flow.Address) ([]map[string]interface{},
 
 	// foreach node id, get the node total stake
 	for nodeID := range nodeStakes {
-		stake, err := a.gateway.ExecuteScript(totalCommitmentScript, []cadence.Value{cadence.String(nodeID)})
+		stake, err := a.gateway.ExecuteScript(ctx, totalCommitmentScript, []cadence.Value{cadence.String(nodeID)})
 		if err != nil {
 			return nil, nil, fmt.Errorf("error getting total stake for node: %s", err.Error())
 		}
@@ -150,7 +183,7 @@ func nodeIDToString(value interface{}) string {
 }
 
 // NodeTotalStake returns the total stake including delegations of a node.
-func (a *Accounts) NodeTotalStake(nodeId string, chain flow.ChainID) (*cadence.Value, error) {
+func (a *Accounts) NodeTotalStake(ctx context.Context, nodeId string, chain flow.ChainID) (*cadence.Value, error) {
 	a.logger.StartProgress(fmt.Sprintf("Fetching total stake for node id %s...", nodeId))
 	defer a.logger.StopProgress()
 
@@ -161,7 +194,7 @@ func (a *Accounts) NodeTotalStake(nodeId string, chain flow.ChainID) (*cadence.V
 	env := util.EnvFromNetwork(chain)
 
 	stakingInfoScript := tmpl.GenerateGetTotalCommitmentBalanceScript(env)
-	stakingValue, err := a.gateway.ExecuteScript(stakingInfoScript, []cadence.Value{cadence.String(nodeId)})
+	stakingValue, err := a.gateway.ExecuteScript(ctx, stakingInfoScript, []cadence.Value{cadence.String(nodeId)})
 	if err != nil {
 		return nil, fmt.Errorf("error getting total stake for node: %s", err.Error())
 	}
@@ -173,16 +206,44 @@ func (a *Accounts) NodeTotalStake(nodeId string, chain flow.ChainID) (*cadence.V
 
 // Create creates and returns a new account.
 //
+// Deprecated: use CreateWithContext, Create is kept for callers without a context and can't be cancelled.
+func (a *Accounts) Create(
+	signer *flowkit.Account,
+	pubKeys []crypto.PublicKey,
+	keyWeights []int,
+	sigAlgo []crypto.SignatureAlgorithm,
+	hashAlgo []crypto.HashAlgorithm,
+	contractArgs []string,
+) (*flow.Account, error) {
+	return a.CreateWithContext(
+		context.Background(),
+		signer,
+		pubKeys,
+		keyWeights,
+		sigAlgo,
+		hashAlgo,
+		contractArgs,
+		flowkit.MaxGasLimit,
+		DefaultGasMargin,
+	)
+}
+
+// CreateWithContext creates and returns a new account.
+//
 // The new account is created with the given public keys and contracts.
 //
-// The account creation transaction is signed by the specified signer.
-func (a *Accounts) Create(
+// The account creation transaction is signed by the specified signer. If the gas limit is zero it's
+// estimated by executing the transaction on an in-process emulator and adding the gas margin, see Transactions.Build.
+func (a *Accounts) CreateWithContext(
+	ctx context.Context,
 	signer *flowkit.Account,
 	pubKeys []crypto.PublicKey,
 	keyWeights []int,
 	sigAlgo []crypto.SignatureAlgorithm,
 	hashAlgo []crypto.HashAlgorithm,
 	contractArgs []string,
+	gasLimit uint64,
+	gasMargin uint64,
 ) (*flow.Account, error) {
 	if a.state == nil {
 		return nil, config.ErrDoesNotExist
@@ -242,7 +303,12 @@ func (a *Accounts) Create(
 		return nil, err
 	}
 
-	tx, err = a.prepareTransaction(tx, signer)
+	err = a.gas.setGasLimit(ctx, tx, gasLimit, gasMargin)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err = a.prepareTransaction(ctx, tx, signer)
 	if err != nil {
 		return nil, err
 	}
@@ -251,14 +317,14 @@ func (a *Accounts) Create(
 	a.logger.StartProgress("Creating account...")
 	defer a.logger.StopProgress()
 
-	sentTx, err := a.gateway.SendSignedTransaction(tx)
+	sentTx, err := a.gateway.SendSignedTransaction(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
 
 	a.logger.StartProgress("Waiting for transaction to be sealed...")
 
-	result, err := a.gateway.GetTransactionResult(sentTx.ID(), true)
+	result, err := a.gateway.GetTransactionResult(ctx, sentTx.ID(), true)
 	if err != nil {
 		return nil, err
 	}
@@ -275,7 +341,494 @@ func (a *Accounts) Create(
 
 	a.logger.StopProgress()
 
-	return a.gateway.GetAccount(*newAccountAddress[0]) // we know it's the only and first event
+	return a.gateway.GetAccount(ctx, *newAccountAddress[0]) // we know it's the only and first event
+}
+
+// AddProposerKeys adds count proposal keys to the account and returns a pool of the new keys.
+//
+// The proposal keys have the public key of the account default key and zero weight, so they can only
+// propose transactions, see Transactions.SetProposerKeyPool.
+func (a *Accounts) AddProposerKeys(
+	ctx context.Context,
+	account *flowkit.Account,
+	count int,
+) (*flowkit.ProposerKeyPool, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("number of proposer keys must be greater than zero")
+	}
+
+	key := account.Key()
+	privateKey, err := key.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey := (*privateKey).PublicKey()
+	tx, err := flowkit.NewAddProposerKeysTransaction(account, &flow.AccountKey{
+		PublicKey: publicKey,
+		SigAlgo:   key.SigAlgo(),
+		HashAlgo:  key.HashAlgo(),
+		Weight:    0,
+	}, count)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err = a.prepareTransaction(ctx, tx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Info(fmt.Sprintf("Transaction ID: %s", tx.FlowTransaction().ID()))
+	a.logger.StartProgress(fmt.Sprintf("Adding %d proposer keys to %s...", count, account.Address()))
+	defer a.logger.StopProgress()
+
+	sentTx, err := a.gateway.SendSignedTransaction(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.gateway.GetTransactionResult(ctx, sentTx.ID(), true)
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	keyIndexes, err := a.addedKeyIndexes(ctx, result, account.Address(), publicKey, count)
+	if err != nil {
+		return nil, err
+	}
+
+	onChain, err := a.gateway.GetAccount(ctx, account.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	return flowkit.NewProposerKeyPool(account, onChain, keyIndexes)
+}
+
+// addedKeyIndexes returns the indexes of the keys with the public key added to the account,
+// taken from the AccountKeyAdded events of the transaction result.
+//
+// Networks which don't emit the key index with the event append the keys to the account in the order of
+// the events, so the indexes are resolved from the account at the block of the transaction instead,
+// which excludes keys added in later blocks, and the keys at the indexes are checked to have the public key.
+func (a *Accounts) addedKeyIndexes(
+	ctx context.Context,
+	result *flow.TransactionResult,
+	address flow.Address,
+	publicKey crypto.PublicKey,
+	count int,
+) ([]int, error) {
+	events := flowkit.EventsFromTransaction(result)
+	coreEvents, err := events.CoreEvents()
+	if err != nil {
+		return nil, err
+	}
+
+	keyIndexes := make([]int, 0, count)
+	added := 0
+	for _, event := range coreEvents {
+		keyAdded, ok := event.(*flowkit.AccountKeyEvent)
+		if !ok || keyAdded.Removed || keyAdded.Address != address || !keyAdded.HasPublicKey(publicKey) {
+			continue
+		}
+		added++
+		if keyAdded.KeyIndex != nil {
+			keyIndexes = append(keyIndexes, *keyAdded.KeyIndex)
+		}
+	}
+
+	if added != count {
+		return nil, fmt.Errorf("expected %d keys added to account %s, the transaction added %d", count, address, added)
+	}
+	if len(keyIndexes) == count {
+		return keyIndexes, nil
+	}
+
+	// the emulator doesn't return the block height with the result, then the latest account is used
+	var atBlock *flow.Account
+	if result.BlockHeight > 0 {
+		atBlock, err = a.gateway.GetAccountAtBlockHeight(ctx, address, result.BlockHeight)
+	} else {
+		atBlock, err = a.gateway.GetAccount(ctx, address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(atBlock.Keys) < count {
+		return nil, fmt.Errorf("account %s has fewer keys than the %d keys added", address, count)
+	}
+
+	keyIndexes = keyIndexes[:0]
+	for _, key := range atBlock.Keys[len(atBlock.Keys)-count:] {
+		if !key.PublicKey.Equals(publicKey) || key.Weight != 0 || key.Revoked {
+			return nil, fmt.Errorf(
+				"failed to find the keys added to account %s, keys were added by another transaction in the same block",
+				address,
+			)
+		}
+		keyIndexes = append(keyIndexes, key.Index)
+	}
+
+	return keyIndexes, nil
+}
+
+// Fund mints the amount of FLOW tokens to the account with the emulator service account and returns the funded account.
+//
+// Funding is only supported on the emulator, where the service account is the FlowToken administrator.
+func (a *Accounts) Fund(
+	ctx context.Context,
+	address flow.Address,
+	amount cadence.UFix64,
+) (*flow.Account, error) {
+	if a.state == nil {
+		return nil, config.ErrDoesNotExist
+	}
+
+	if amount == 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+
+	signer, err := a.state.EmulatorServiceAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range []flow.Address{signer.Address(), address} {
+		chain, err := util.GetAddressNetwork(addr)
+		if err != nil {
+			return nil, err
+		}
+		if chain != flow.Emulator {
+			return nil, fmt.Errorf("funding accounts is only supported on the emulator, %s is a %s address", addr, chain)
+		}
+	}
+
+	tx, err := flowkit.NewMintFlowTokensTransaction(signer, util.EnvFromNetwork(flow.Emulator), address, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.sendAccountTransaction(ctx, tx, signer, fmt.Sprintf("Funding %s with %s FLOW...", address, amount))
+	if err != nil {
+		return nil, err
+	}
+
+	return a.gateway.GetAccount(ctx, address)
+}
+
+// AddKey adds the key to the account and returns the updated account.
+func (a *Accounts) AddKey(
+	ctx context.Context,
+	account *flowkit.Account,
+	key *flow.AccountKey,
+) (*flow.Account, error) {
+	err := key.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid account key: %w", err)
+	}
+
+	tx, err := flowkit.NewAddAccountKeyTransaction(account, key)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.sendAccountTransaction(ctx, tx, account, fmt.Sprintf("Adding key to %s...", account.Address()))
+	if err != nil {
+		return nil, err
+	}
+
+	return a.gateway.GetAccount(ctx, account.Address())
+}
+
+// RevokeKey revokes the key at the index from the account and returns the updated account.
+//
+// The key used by the account in the configuration can't be revoked, it must be rotated instead
+// so the account can still sign transactions.
+func (a *Accounts) RevokeKey(
+	ctx context.Context,
+	account *flowkit.Account,
+	index int,
+) (*flow.Account, error) {
+	if index == account.Key().Index() {
+		return nil, fmt.Errorf("key %d is the key of account %s in the configuration, rotate it instead", index, account.Name())
+	}
+
+	onChain, err := a.gateway.GetAccount(ctx, account.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 || index >= len(onChain.Keys) {
+		return nil, fmt.Errorf("account %s doesn't have a key at index %d", account.Address(), index)
+	}
+	if onChain.Keys[index].Revoked {
+		return nil, fmt.Errorf("key %d of account %s is already revoked", index, account.Address())
+	}
+
+	tx, err := flowkit.NewRevokeAccountKeyTransaction(account, index)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.sendAccountTransaction(ctx, tx, account, fmt.Sprintf("Revoking key %d from %s...", index, account.Address()))
+	if err != nil {
+		return nil, err
+	}
+
+	return a.gateway.GetAccount(ctx, account.Address())
+}
+
+// sendAccountTransaction sends the transaction signed by the account and waits for it to be sealed.
+func (a *Accounts) sendAccountTransaction(
+	ctx context.Context,
+	tx *flowkit.Transaction,
+	account *flowkit.Account,
+	progress string,
+) error {
+	tx, err := a.prepareTransaction(ctx, tx, account)
+	if err != nil {
+		return err
+	}
+
+	a.logger.Info(fmt.Sprintf("Transaction ID: %s", tx.FlowTransaction().ID()))
+	a.logger.StartProgress(progress)
+	defer a.logger.StopProgress()
+
+	sentTx, err := a.gateway.SendSignedTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	result, err := a.gateway.GetTransactionResult(ctx, sentTx.ID(), true)
+	if err != nil {
+		return err
+	}
+
+	return result.Error
+}
+
+// PendingKeyRotation returns the key rotation saved at the path, or nil if no rotation is pending.
+func (a *Accounts) PendingKeyRotation(path string) (*flowkit.KeyRotation, error) {
+	if a.state == nil {
+		return nil, config.ErrDoesNotExist
+	}
+
+	data, err := a.state.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// a cleared rotation completed
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	return flowkit.ParseKeyRotation(data)
+}
+
+// ClearKeyRotation clears the key rotation saved at the path, once the state holds the new key the rotation
+// doesn't need to be resumed and the new private key shouldn't stay in the rotation file.
+func (a *Accounts) ClearKeyRotation(path string) error {
+	if a.state == nil {
+		return config.ErrDoesNotExist
+	}
+
+	err := a.state.ReaderWriter().WriteFile(path, nil, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to clear key rotation: %w", err)
+	}
+
+	return nil
+}
+
+// RotateKey replaces the account key with the new key of the rotation and updates the account in the state.
+//
+// The rotation is saved to the path before the transaction is sent, so it can be resumed if it's interrupted.
+// The new key is added and the old key is revoked in the same transaction, so if the new key is on the account
+// the rotation completed and only the account in the state is updated.
+func (a *Accounts) RotateKey(
+	ctx context.Context,
+	account *flowkit.Account,
+	rotation *flowkit.KeyRotation,
+	path string,
+) (*flowkit.Account, error) {
+	if a.state == nil {
+		return nil, config.ErrDoesNotExist
+	}
+
+	if rotation.Account != account.Name() || rotation.Address != account.Address().Hex() {
+		return nil, fmt.Errorf("key rotation is for account %s (0x%s), not %s", rotation.Account, rotation.Address, account.Name())
+	}
+
+	privateKey, err := rotation.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	onChain, err := a.gateway.GetAccount(ctx, account.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	index := accountKeyIndex(onChain, privateKey.PublicKey())
+	if index < 0 && rotation.TransactionID != "" {
+		// the rotation was interrupted after the transaction was prepared, wait for it if the network knows it
+		id := flow.HexToID(rotation.TransactionID)
+		result, err := a.gateway.GetTransactionResult(ctx, id, false)
+		if err == nil && result.Error == nil &&
+			result.Status != flow.TransactionStatusUnknown && result.Status != flow.TransactionStatusExpired {
+			a.logger.StartProgress("Waiting for the previous rotation transaction to be sealed...")
+			result, err = a.gateway.GetTransactionResult(ctx, id, true)
+			a.logger.StopProgress()
+		}
+
+		if err == nil && result.Status == flow.TransactionStatusSealed && result.Error == nil {
+			onChain, err = a.gateway.GetAccount(ctx, account.Address())
+			if err != nil {
+				return nil, err
+			}
+			index = accountKeyIndex(onChain, privateKey.PublicKey())
+		}
+
+		if index < 0 {
+			a.logger.Info(fmt.Sprintf("⚠️  Previous rotation transaction %s didn't rotate the key, sending it again", rotation.TransactionID))
+		}
+	}
+
+	if index < 0 {
+		index, err = a.sendKeyRotation(ctx, account, onChain, rotation, privateKey, path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	account.SetKey(flowkit.NewHexAccountKeyFromPrivateKey(index, rotation.NewHashAlgo(), privateKey))
+	a.state.Accounts().AddOrUpdate(account)
+
+	return account, nil
+}
+
+// sendKeyRotation checks the rotated key is valid, saves the rotation and sends the rotation transaction.
+//
+// It returns the index of the new key on the account.
+func (a *Accounts) sendKeyRotation(
+	ctx context.Context,
+	account *flowkit.Account,
+	onChain *flow.Account,
+	rotation *flowkit.KeyRotation,
+	privateKey crypto.PrivateKey,
+	path string,
+) (int, error) {
+	if rotation.RevokeIndex < 0 || rotation.RevokeIndex >= len(onChain.Keys) {
+		return 0, fmt.Errorf("account %s doesn't have a key at index %d", account.Address(), rotation.RevokeIndex)
+	}
+
+	revoked := onChain.Keys[rotation.RevokeIndex]
+	if revoked.Revoked {
+		return 0, fmt.Errorf("key %d of account %s is already revoked", rotation.RevokeIndex, account.Address())
+	}
+
+	// the configured key signs the rotation, so make sure it's the key being revoked
+	if configured, err := account.Key().PrivateKey(); err == nil && !(*configured).PublicKey().Equals(revoked.PublicKey) {
+		return 0, fmt.Errorf("key %d of account %s doesn't match the key in the configuration", rotation.RevokeIndex, account.Address())
+	}
+
+	tx, err := flowkit.NewRotateAccountKeyTransaction(account, &flow.AccountKey{
+		PublicKey: privateKey.PublicKey(),
+		SigAlgo:   privateKey.Algorithm(),
+		HashAlgo:  rotation.NewHashAlgo(),
+		Weight:    revoked.Weight,
+	}, rotation.RevokeIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err = a.prepareTransaction(ctx, tx, account)
+	if err != nil {
+		return 0, err
+	}
+
+	// save the rotation with the transaction ID before sending it, so the new key is never lost
+	rotation.TransactionID = tx.FlowTransaction().ID().Hex()
+	err = a.saveKeyRotation(rotation, path)
+	if err != nil {
+		return 0, err
+	}
+
+	a.logger.Info(fmt.Sprintf("Transaction ID: %s", rotation.TransactionID))
+	a.logger.StartProgress(fmt.Sprintf("Rotating key %d of %s...", rotation.RevokeIndex, account.Address()))
+	defer a.logger.StopProgress()
+
+	sentTx, err := a.gateway.SendSignedTransaction(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := a.gateway.GetTransactionResult(ctx, sentTx.ID(), true)
+	if err != nil {
+		return 0, err
+	}
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	onChain, err = a.gateway.GetAccount(ctx, account.Address())
+	if err != nil {
+		return 0, err
+	}
+
+	index := accountKeyIndex(onChain, privateKey.PublicKey())
+	if index < 0 {
+		return 0, fmt.Errorf("new key of account %s couldn't be found after the rotation", account.Address())
+	}
+
+	return index, nil
+}
+
+// fileModeChanger is implemented by the reader writers which can change the permissions of a file, like afero.Afero.
+type fileModeChanger interface {
+	Chmod(name string, mode os.FileMode) error
+}
+
+func (a *Accounts) saveKeyRotation(rotation *flowkit.KeyRotation, path string) error {
+	data, err := rotation.MarshalIndent()
+	if err != nil {
+		return err
+	}
+
+	// the rotation file holds the new private key, so an existing file with wider permissions is restricted first
+	if fs, ok := a.state.ReaderWriter().(fileModeChanger); ok {
+		err = fs.Chmod(path, 0600)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to save key rotation: %w", err)
+		}
+	}
+
+	err = a.state.ReaderWriter().WriteFile(path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to save key rotation: %w", err)
+	}
+
+	return nil
+}
+
+// accountKeyIndex returns the index of the public key on the account if it's not revoked, otherwise -1.
+func accountKeyIndex(account *flow.Account, publicKey crypto.PublicKey) int {
+	for _, key := range account.Keys {
+		if !key.Revoked && key.PublicKey.Equals(publicKey) {
+			return key.Index
+		}
+	}
+
+	return -1
 }
 
 // Contract defines properties of a contract like name of the contract,
@@ -306,10 +859,22 @@ func (c *Contract) validate(hasImports bool) error {
 }
 
 // AddContract deploys a contract code to the account provided with possible update flag.
+//
+// Deprecated: use AddContractWithContext, AddContract is kept for callers without a context and can't be cancelled.
 func (a *Accounts) AddContract(
 	account *flowkit.Account,
 	contract *Contract,
 	updateExisting bool,
+) (*flow.Account, error) {
+	return a.AddContractWithContext(context.Background(), account, contract, updateExisting)
+}
+
+// AddContractWithContext deploys a contract code to the account provided with possible update flag.
+func (a *Accounts) AddContractWithContext(
+	ctx context.Context,
+	account *flowkit.Account,
+	contract *Contract,
+	updateExisting bool,
 ) (*flow.Account, error) {
 	resolver, err := contracts.NewResolver(contract.Source)
 	if err != nil {
@@ -360,7 +925,7 @@ func (a *Accounts) AddContract(
 		}
 	}
 
-	tx, err = a.prepareTransaction(tx, account)
+	tx, err = a.prepareTransaction(ctx, tx, account)
 	if err != nil {
 		return nil, err
 	}
@@ -382,13 +947,13 @@ func (a *Accounts) AddContract(
 	defer a.logger.StopProgress()
 
 	// send transaction with contract
-	sentTx, err := a.gateway.SendSignedTransaction(tx)
+	sentTx, err := a.gateway.SendSignedTransaction(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
 
 	// we wait for transaction to be sealed
-	trx, err := a.gateway.GetTransactionResult(sentTx.ID(), true)
+	trx, err := a.gateway.GetTransactionResult(ctx, sentTx.ID(), true)
 	if err != nil {
 		return nil, err
 	}
@@ -398,7 +963,7 @@ func (a *Accounts) AddContract(
 		return nil, trx.Error
 	}
 
-	update, err := a.gateway.GetAccount(account.Address())
+	update, err := a.gateway.GetAccount(ctx, account.Address())
 
 	a.logger.StopProgress()
 
@@ -421,6 +986,7 @@ func (a *Accounts) AddContract(
 
 // RemoveContract removes a contract from an account and returns the updated account.
 func (a *Accounts) RemoveContract(
+	ctx context.Context,
 	account *flowkit.Account,
 	contractName string,
 ) (*flow.Account, error) {
@@ -429,7 +995,7 @@ func (a *Accounts) RemoveContract(
 		return nil, err
 	}
 
-	tx, err = a.prepareTransaction(tx, account)
+	tx, err = a.prepareTransaction(ctx, tx, account)
 	if err != nil {
 		return nil, err
 	}
@@ -440,12 +1006,12 @@ func (a *Accounts) RemoveContract(
 	)
 	defer a.logger.StopProgress()
 
-	sentTx, err := a.gateway.SendSignedTransaction(tx)
+	sentTx, err := a.gateway.SendSignedTransaction(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
 
-	txr, err := a.gateway.GetTransactionResult(sentTx.ID(), true)
+	txr, err := a.gateway.GetTransactionResult(ctx, sentTx.ID(), true)
 	if err != nil {
 		return nil, err
 	}
@@ -461,21 +1027,22 @@ func (a *Accounts) RemoveContract(
 		account.Address(),
 	))
 
-	return a.gateway.GetAccount(account.Address())
+	return a.gateway.GetAccount(ctx, account.Address())
 }
 
 // prepareTransaction prepares transaction for sending with data from network
 func (a *Accounts) prepareTransaction(
+	ctx context.Context,
 	tx *flowkit.Transaction,
 	account *flowkit.Account,
 ) (*flowkit.Transaction, error) {
 
-	block, err := a.gateway.GetLatestBlock()
+	block, err := a.gateway.GetLatestBlock(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	proposer, err := a.gateway.GetAccount(account.Address())
+	proposer, err := a.gateway.GetAccount(ctx, account.Address())
 	if err != nil {
 		return nil, err
 	}
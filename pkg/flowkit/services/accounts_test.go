@@ -19,11 +19,14 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 
 	"github.com/onflow/cadence"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-cli/pkg/flowkit/gateway"
@@ -41,6 +44,8 @@ import (
 	"github.com/onflow/flow-cli/pkg/flowkit/tests"
 )
 
+var ctx = context.Background()
+
 func setup() (*flowkit.State, *Services, *tests.TestGateway) {
 	readerWriter := tests.ReaderWriter()
 	state, err := flowkit.Init(readerWriter, crypto.ECDSA_P256, crypto.SHA3_256)
@@ -70,11 +75,66 @@ func TestAccounts(t *testing.T) {
 	serviceAcc, _ := state.EmulatorServiceAccount()
 	serviceAddress := serviceAcc.Address()
 
+	t.Run("Add Proposer Keys", func(t *testing.T) {
+		_, s, gw := setup()
+
+		privateKey, err := serviceAcc.Key().PrivateKey()
+		require.NoError(t, err)
+		publicKey := (*privateKey).PublicKey()
+
+		// the key indexes are taken from the events, keys added by other transactions are ignored
+		gw.GetTransactionResult.Return(tests.NewTransactionResult([]flow.Event{
+			keyAddedEvent(serviceAddress, publicKey, 3),
+			keyAddedEvent(flow.HexToAddress("02"), publicKey, 4),
+			keyAddedEvent(serviceAddress, publicKey, 5),
+		}), nil)
+
+		onChain := tests.NewAccountWithAddress(serviceAddress.String())
+		onChain.Keys = nil
+		for i := 0; i <= 5; i++ {
+			onChain.Keys = append(onChain.Keys, &flow.AccountKey{Index: i, PublicKey: publicKey, SequenceNumber: uint64(i)})
+		}
+		gw.GetAccount.Run(func(args mock.Arguments) {
+			gw.GetAccount.Return(onChain, nil)
+		})
+
+		pool, err := s.Accounts.AddProposerKeys(ctx, serviceAcc, 2)
+		require.NoError(t, err)
+		require.Equal(t, 2, pool.Size())
+
+		for _, index := range []int{3, 5} {
+			key, err := pool.Acquire(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, index, key.Index)
+			assert.Equal(t, uint64(index), key.SequenceNumber)
+		}
+		gw.Mock.AssertNotCalled(t, tests.GetAccountAtBlockHeightFunc, mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("Get an Account", func(t *testing.T) {
 		_, s, gw := setup()
-		account, err := s.Accounts.Get(serviceAddress)
+		account, err := s.Accounts.GetWithContext(ctx, serviceAddress)
 
-		gw.Mock.AssertCalled(t, "GetAccount", serviceAddress)
+		gw.Mock.AssertCalled(t, "GetAccount", mock.Anything, serviceAddress)
+		assert.NoError(t, err)
+		assert.Equal(t, serviceAddress, account.Address)
+	})
+
+	t.Run("Get an Account at Block Height", func(t *testing.T) {
+		_, s, gw := setup()
+		account, err := s.Accounts.GetAtBlockHeight(ctx, serviceAddress, 10)
+
+		gw.Mock.AssertCalled(t, tests.GetAccountAtBlockHeightFunc, mock.Anything, serviceAddress, uint64(10))
+		assert.NoError(t, err)
+		assert.Equal(t, serviceAddress, account.Address)
+	})
+
+	t.Run("Get an Account at Block ID", func(t *testing.T) {
+		_, s, gw := setup()
+		blockID := flow.HexToID("a310685082f0b09f2a148b2e8905f08ea458ed873596b53b200699e8e1f6536f")
+		account, err := s.Accounts.GetAtBlockID(ctx, serviceAddress, blockID)
+
+		gw.Mock.AssertCalled(t, tests.GetAccountAtBlockIDFunc, mock.Anything, serviceAddress, blockID)
 		assert.NoError(t, err)
 		assert.Equal(t, serviceAddress, account.Address)
 	})
@@ -84,7 +144,7 @@ func TestAccounts(t *testing.T) {
 		newAddress := flow.HexToAddress("192440c99cb17282")
 
 		gw.SendSignedTransaction.Run(func(args mock.Arguments) {
-			tx := args.Get(0).(*flowkit.Transaction)
+			tx := args.Get(1).(*flowkit.Transaction)
 			assert.Equal(t, serviceAddress, tx.FlowTransaction().Authorizers[0])
 			assert.Equal(t, serviceAddress, tx.Signer().Address())
 
@@ -93,7 +153,7 @@ func TestAccounts(t *testing.T) {
 
 		compareAddress := serviceAddress
 		gw.GetAccount.Run(func(args mock.Arguments) {
-			address := args.Get(0).(flow.Address)
+			address := args.Get(1).(flow.Address)
 			assert.Equal(t, address, compareAddress)
 			compareAddress = newAddress
 			gw.GetAccount.Return(
@@ -105,17 +165,20 @@ func TestAccounts(t *testing.T) {
 			tests.NewAccountCreateResult(newAddress), nil,
 		)
 
-		account, err := s.Accounts.Create(
+		account, err := s.Accounts.CreateWithContext(
+			ctx,
 			serviceAcc,
 			[]crypto.PublicKey{pubKey},
 			[]int{1000},
 			[]crypto.SignatureAlgorithm{crypto.ECDSA_P256},
 			[]crypto.HashAlgorithm{crypto.SHA3_256},
 			nil,
+			flowkit.MaxGasLimit,
+			DefaultGasMargin,
 		)
 
-		gw.Mock.AssertCalled(t, tests.GetAccountFunc, serviceAddress)
-		gw.Mock.AssertCalled(t, tests.GetAccountFunc, newAddress)
+		gw.Mock.AssertCalled(t, tests.GetAccountFunc, mock.Anything, serviceAddress)
+		gw.Mock.AssertCalled(t, tests.GetAccountFunc, mock.Anything, newAddress)
 		gw.Mock.AssertNumberOfCalls(t, tests.GetAccountFunc, 2)
 		gw.Mock.AssertNumberOfCalls(t, tests.GetTransactionResultFunc, 1)
 		gw.Mock.AssertNumberOfCalls(t, tests.SendSignedTransactionFunc, 1)
@@ -129,7 +192,7 @@ func TestAccounts(t *testing.T) {
 		newAddress := flow.HexToAddress("192440c99cb17281")
 
 		gw.SendSignedTransaction.Run(func(args mock.Arguments) {
-			tx := args.Get(0).(*flowkit.Transaction)
+			tx := args.Get(1).(*flowkit.Transaction)
 			assert.Equal(t, tx.FlowTransaction().Authorizers[0], serviceAddress)
 			assert.Equal(t, tx.Signer().Address(), serviceAddress)
 			assert.True(t, strings.Contains(string(tx.FlowTransaction().Script), "account.contracts.add"))
@@ -140,17 +203,20 @@ func TestAccounts(t *testing.T) {
 			gw.GetTransactionResult.Return(tests.NewAccountCreateResult(newAddress), nil)
 		})
 
-		account, err := s.Accounts.Create(
+		account, err := s.Accounts.CreateWithContext(
+			ctx,
 			serviceAcc,
 			[]crypto.PublicKey{pubKey},
 			[]int{1000},
 			[]crypto.SignatureAlgorithm{crypto.ECDSA_P256},
 			[]crypto.HashAlgorithm{crypto.SHA3_256},
 			[]string{"Hello:contractHello.cdc"},
+			flowkit.MaxGasLimit,
+			DefaultGasMargin,
 		)
 
-		gw.Mock.AssertCalled(t, tests.GetAccountFunc, serviceAddress)
-		gw.Mock.AssertCalled(t, tests.GetAccountFunc, newAddress)
+		gw.Mock.AssertCalled(t, tests.GetAccountFunc, mock.Anything, serviceAddress)
+		gw.Mock.AssertCalled(t, tests.GetAccountFunc, mock.Anything, newAddress)
 		gw.Mock.AssertNumberOfCalls(t, tests.GetAccountFunc, 2)
 		gw.Mock.AssertNumberOfCalls(t, tests.GetTransactionResultFunc, 1)
 		gw.Mock.AssertNumberOfCalls(t, tests.SendSignedTransactionFunc, 1)
@@ -162,20 +228,21 @@ func TestAccounts(t *testing.T) {
 	t.Run("Contract Add for Account", func(t *testing.T) {
 		_, s, gw := setup()
 		gw.SendSignedTransaction.Run(func(args mock.Arguments) {
-			tx := args.Get(0).(*flowkit.Transaction)
+			tx := args.Get(1).(*flowkit.Transaction)
 			assert.Equal(t, tx.Signer().Address(), serviceAddress)
 			assert.True(t, strings.Contains(string(tx.FlowTransaction().Script), "signer.contracts.add"))
 
 			gw.SendSignedTransaction.Return(tests.NewTransaction(), nil)
 		})
 
-		account, err := s.Accounts.AddContract(
+		account, err := s.Accounts.AddContractWithContext(
+			ctx,
 			serviceAcc,
 			resourceToContract(tests.ContractHelloString),
 			false,
 		)
 
-		gw.Mock.AssertCalled(t, tests.GetAccountFunc, serviceAddress)
+		gw.Mock.AssertCalled(t, tests.GetAccountFunc, mock.Anything, serviceAddress)
 		gw.Mock.AssertNumberOfCalls(t, tests.GetAccountFunc, 2)
 		gw.Mock.AssertNumberOfCalls(t, tests.GetTransactionResultFunc, 1)
 		gw.Mock.AssertNumberOfCalls(t, tests.SendSignedTransactionFunc, 1)
@@ -186,20 +253,21 @@ func TestAccounts(t *testing.T) {
 	t.Run("Contract Update for Account", func(t *testing.T) {
 		_, s, gw := setup()
 		gw.SendSignedTransaction.Run(func(args mock.Arguments) {
-			tx := args.Get(0).(*flowkit.Transaction)
+			tx := args.Get(1).(*flowkit.Transaction)
 			assert.Equal(t, tx.Signer().Address(), serviceAddress)
 			assert.True(t, strings.Contains(string(tx.FlowTransaction().Script), "signer.contracts.update__experimental"))
 
 			gw.SendSignedTransaction.Return(tests.NewTransaction(), nil)
 		})
 
-		account, err := s.Accounts.AddContract(
+		account, err := s.Accounts.AddContractWithContext(
+			ctx,
 			serviceAcc,
 			resourceToContract(tests.ContractHelloString),
 			true,
 		)
 
-		gw.Mock.AssertCalled(t, tests.GetAccountFunc, serviceAddress)
+		gw.Mock.AssertCalled(t, tests.GetAccountFunc, mock.Anything, serviceAddress)
 		gw.Mock.AssertNumberOfCalls(t, tests.GetAccountFunc, 2)
 		gw.Mock.AssertNumberOfCalls(t, tests.GetTransactionResultFunc, 1)
 		gw.Mock.AssertNumberOfCalls(t, tests.SendSignedTransactionFunc, 1)
@@ -210,7 +278,7 @@ func TestAccounts(t *testing.T) {
 	t.Run("Contract Remove for Account", func(t *testing.T) {
 		_, s, gw := setup()
 		gw.SendSignedTransaction.Run(func(args mock.Arguments) {
-			tx := args.Get(0).(*flowkit.Transaction)
+			tx := args.Get(1).(*flowkit.Transaction)
 			assert.Equal(t, tx.Signer().Address(), serviceAddress)
 			assert.True(t, strings.Contains(string(tx.FlowTransaction().Script), "signer.contracts.remove"))
 
@@ -218,11 +286,12 @@ func TestAccounts(t *testing.T) {
 		})
 
 		account, err := s.Accounts.RemoveContract(
+			ctx,
 			serviceAcc,
 			tests.ContractHelloString.Filename,
 		)
 
-		gw.Mock.AssertCalled(t, tests.GetAccountFunc, serviceAddress)
+		gw.Mock.AssertCalled(t, tests.GetAccountFunc, mock.Anything, serviceAddress)
 		gw.Mock.AssertNumberOfCalls(t, tests.GetAccountFunc, 2)
 		gw.Mock.AssertNumberOfCalls(t, tests.GetTransactionResultFunc, 1)
 		gw.Mock.AssertNumberOfCalls(t, tests.SendSignedTransactionFunc, 1)
@@ -236,11 +305,11 @@ func TestAccounts(t *testing.T) {
 		count := 0
 		gw.ExecuteScript.Run(func(args mock.Arguments) {
 			count++
-			assert.True(t, strings.Contains(string(args.Get(0).([]byte)), "import FlowIDTableStaking from 0x9eca2b38b18b5dfe"))
+			assert.True(t, strings.Contains(string(args.Get(1).([]byte)), "import FlowIDTableStaking from 0x9eca2b38b18b5dfe"))
 			gw.ExecuteScript.Return(cadence.NewArray([]cadence.Value{}), nil)
 		})
 
-		val1, val2, err := s.Accounts.StakingInfo(flow.HexToAddress("df9c30eb2252f1fa"))
+		val1, val2, err := s.Accounts.StakingInfo(ctx, flow.HexToAddress("df9c30eb2252f1fa"))
 		assert.NoError(t, err)
 		assert.NotNil(t, val1)
 		assert.NotNil(t, val2)
@@ -251,7 +320,7 @@ func TestAccounts(t *testing.T) {
 
 		count := 0
 		gw.ExecuteScript.Run(func(args mock.Arguments) {
-			assert.True(t, strings.Contains(string(args.Get(0).([]byte)), "import FlowIDTableStaking from 0x9eca2b38b18b5dfe"))
+			assert.True(t, strings.Contains(string(args.Get(1).([]byte)), "import FlowIDTableStaking from 0x9eca2b38b18b5dfe"))
 			if count < 2 {
 				gw.ExecuteScript.Return(cadence.NewArray(
 					[]cadence.Value{
@@ -269,13 +338,13 @@ func TestAccounts(t *testing.T) {
 						},
 					}), nil)
 			} else {
-				assert.True(t, strings.Contains(args.Get(1).([]cadence.Value)[0].String(), "8f4d09dae7918afbf62c48fa968a9e8b0891cee8442065fa47cc05f4bc9a8a91"))
+				assert.True(t, strings.Contains(args.Get(2).([]cadence.Value)[0].String(), "8f4d09dae7918afbf62c48fa968a9e8b0891cee8442065fa47cc05f4bc9a8a91"))
 				gw.ExecuteScript.Return(cadence.NewUFix64("1.0"))
 			}
 			count++
 		})
 
-		val1, val2, err := s.Accounts.StakingInfo(flow.HexToAddress("df9c30eb2252f1fa"))
+		val1, val2, err := s.Accounts.StakingInfo(ctx, flow.HexToAddress("df9c30eb2252f1fa"))
 		assert.NoError(t, err)
 		assert.NotNil(t, val1)
 		assert.NotNil(t, val2)
@@ -402,7 +471,7 @@ func TestAccountsCreate_Integration(t *testing.T) {
 		}}
 
 		for i, a := range accIn {
-			acc, err := s.Accounts.Create(a.account, a.pubKeys, a.weights, a.sigAlgo, a.hashAlgo, a.args)
+			acc, err := s.Accounts.CreateWithContext(ctx, a.account, a.pubKeys, a.weights, a.sigAlgo, a.hashAlgo, a.args, flowkit.MaxGasLimit, DefaultGasMargin)
 			c := accOut[i]
 
 			assert.NoError(t, err)
@@ -499,7 +568,7 @@ func TestAccountsCreate_Integration(t *testing.T) {
 		}
 
 		for i, a := range accIn {
-			acc, err := s.Accounts.Create(a.account, a.pubKeys, a.weights, a.sigAlgo, a.hashAlgo, a.args)
+			acc, err := s.Accounts.CreateWithContext(ctx, a.account, a.pubKeys, a.weights, a.sigAlgo, a.hashAlgo, a.args, flowkit.MaxGasLimit, DefaultGasMargin)
 			errMsg := errOut[i]
 
 			assert.Nil(t, acc)
@@ -519,7 +588,8 @@ func TestAccountsAddContract_Integration(t *testing.T) {
 		state, s := setupIntegration()
 		srvAcc, _ := state.EmulatorServiceAccount()
 
-		acc, err := s.Accounts.AddContract(
+		acc, err := s.Accounts.AddContractWithContext(
+			ctx,
 			srvAcc,
 			resourceToContract(tests.ContractSimple),
 			false,
@@ -529,7 +599,8 @@ func TestAccountsAddContract_Integration(t *testing.T) {
 		require.NotNil(t, acc)
 		assert.Equal(t, acc.Contracts["Simple"], tests.ContractSimple.Source)
 
-		acc, err = s.Accounts.AddContract(
+		acc, err = s.Accounts.AddContractWithContext(
+			ctx,
 			srvAcc,
 			resourceToContract(tests.ContractSimpleUpdated),
 			true,
@@ -547,14 +618,16 @@ func TestAccountsAddContract_Integration(t *testing.T) {
 		srvAcc, _ := state.EmulatorServiceAccount()
 
 		// prepare existing contract
-		_, err := s.Accounts.AddContract(
+		_, err := s.Accounts.AddContractWithContext(
+			ctx,
 			srvAcc,
 			resourceToContract(tests.ContractSimple),
 			false,
 		)
 		assert.NoError(t, err)
 
-		_, err = s.Accounts.AddContract(
+		_, err = s.Accounts.AddContractWithContext(
+			ctx,
 			srvAcc,
 			resourceToContract(tests.ContractSimple),
 			false,
@@ -563,7 +636,8 @@ func TestAccountsAddContract_Integration(t *testing.T) {
 		require.Error(t, err)
 		assert.True(t, strings.Contains(err.Error(), "cannot overwrite existing contract with name \"Simple\""))
 
-		_, err = s.Accounts.AddContract(
+		_, err = s.Accounts.AddContractWithContext(
+			ctx,
 			srvAcc,
 			resourceToContract(tests.ContractHelloString),
 			true,
@@ -578,7 +652,8 @@ func TestAccountsAddContractWithArgs(t *testing.T) {
 	srvAcc, _ := state.EmulatorServiceAccount()
 
 	//adding contract without argument should return an error
-	acc, err := s.Accounts.AddContract(
+	acc, err := s.Accounts.AddContractWithContext(
+		ctx,
 		srvAcc,
 		resourceToContract(tests.ContractSimpleWithArgs),
 		false,
@@ -589,7 +664,7 @@ func TestAccountsAddContractWithArgs(t *testing.T) {
 	c := resourceToContract(tests.ContractSimpleWithArgs)
 	c.Args = []cadence.Value{cadence.UInt64(4)}
 
-	acc, err = s.Accounts.AddContract(srvAcc, c, false)
+	acc, err = s.Accounts.AddContractWithContext(ctx, srvAcc, c, false)
 	assert.NoError(t, err)
 	assert.NotNil(t, acc)
 	assert.Equal(t, acc.Contracts["Simple"], tests.ContractSimpleWithArgs.Source)
@@ -603,7 +678,8 @@ func TestAccountsRemoveContract_Integration(t *testing.T) {
 
 	c := tests.ContractSimple
 	// prepare existing contract
-	_, err := s.Accounts.AddContract(
+	_, err := s.Accounts.AddContractWithContext(
+		ctx,
 		srvAcc,
 		&Contract{
 			Name:     c.Name,
@@ -618,7 +694,7 @@ func TestAccountsRemoveContract_Integration(t *testing.T) {
 	t.Run("Remove Contract", func(t *testing.T) {
 		t.Parallel()
 
-		acc, err := s.Accounts.RemoveContract(srvAcc, tests.ContractSimple.Name)
+		acc, err := s.Accounts.RemoveContract(ctx, srvAcc, tests.ContractSimple.Name)
 
 		assert.NoError(t, err)
 		assert.Equal(t, acc.Contracts[tests.ContractSimple.Name], []byte(nil))
@@ -633,7 +709,7 @@ func TestAccountsGet_Integration(t *testing.T) {
 
 	t.Run("Get Account", func(t *testing.T) {
 		t.Parallel()
-		acc, err := s.Accounts.Get(srvAcc.Address())
+		acc, err := s.Accounts.GetWithContext(ctx, srvAcc.Address())
 
 		assert.NoError(t, err)
 		assert.NotNil(t, acc)
@@ -643,10 +719,19 @@ func TestAccountsGet_Integration(t *testing.T) {
 	t.Run("Get Account Invalid", func(t *testing.T) {
 		t.Parallel()
 
-		acc, err := s.Accounts.Get(flow.HexToAddress("0x1"))
+		acc, err := s.Accounts.GetWithContext(ctx, flow.HexToAddress("0x1"))
 		assert.Nil(t, acc)
 		assert.Equal(t, err.Error(), "could not find account with address 0000000000000001")
 	})
+
+	t.Run("Get Account at Block Height", func(t *testing.T) {
+		t.Parallel()
+		acc, err := s.Accounts.GetAtBlockHeight(ctx, srvAcc.Address(), 0)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, acc)
+		assert.Equal(t, acc.Address, srvAcc.Address())
+	})
 }
 
 func TestAccountsStakingInfo_Integration(t *testing.T) {
@@ -655,7 +740,198 @@ func TestAccountsStakingInfo_Integration(t *testing.T) {
 	srvAcc, _ := state.EmulatorServiceAccount()
 
 	t.Run("Get Staking Info", func(t *testing.T) {
-		_, _, err := s.Accounts.StakingInfo(srvAcc.Address()) // unfortunately can't do integration test
+		_, _, err := s.Accounts.StakingInfo(ctx, srvAcc.Address()) // unfortunately can't do integration test
 		assert.Equal(t, err.Error(), "emulator chain not supported")
 	})
 }
+
+func TestAccountKeys_Integration(t *testing.T) {
+	t.Parallel()
+
+	setupAlice := func(t *testing.T) (*flowkit.State, *Services, *flowkit.Account) {
+		state, s := setupIntegration()
+		setupAccounts(state, s)
+
+		alice, err := state.Accounts().ByName(tests.Alice().Name())
+		require.NoError(t, err)
+
+		return state, s, alice
+	}
+
+	t.Run("Add Key", func(t *testing.T) {
+		t.Parallel()
+		_, s, alice := setupAlice(t)
+
+		acc, err := s.Accounts.AddKey(ctx, alice, &flow.AccountKey{
+			PublicKey: tests.PubKeys()[0],
+			SigAlgo:   tests.SigAlgos()[0],
+			HashAlgo:  crypto.SHA3_256,
+			Weight:    500,
+		})
+		require.NoError(t, err)
+		require.Len(t, acc.Keys, 2)
+		assert.True(t, acc.Keys[1].PublicKey.Equals(tests.PubKeys()[0]))
+		assert.Equal(t, 500, acc.Keys[1].Weight)
+	})
+
+	t.Run("Revoke Key", func(t *testing.T) {
+		t.Parallel()
+		_, s, alice := setupAlice(t)
+
+		_, err := s.Accounts.AddKey(ctx, alice, &flow.AccountKey{
+			PublicKey: tests.PubKeys()[0],
+			SigAlgo:   tests.SigAlgos()[0],
+			HashAlgo:  crypto.SHA3_256,
+			Weight:    500,
+		})
+		require.NoError(t, err)
+
+		acc, err := s.Accounts.RevokeKey(ctx, alice, 1)
+		require.NoError(t, err)
+		assert.True(t, acc.Keys[1].Revoked)
+		assert.False(t, acc.Keys[0].Revoked)
+
+		_, err = s.Accounts.RevokeKey(ctx, alice, 1)
+		assert.EqualError(t, err, fmt.Sprintf("key 1 of account %s is already revoked", alice.Address()))
+
+		_, err = s.Accounts.RevokeKey(ctx, alice, 0)
+		assert.EqualError(t, err, "key 0 is the key of account Alice in the configuration, rotate it instead")
+	})
+
+	t.Run("Rotate Key", func(t *testing.T) {
+		t.Parallel()
+		state, s, alice := setupAlice(t)
+
+		// a rotation file cleared by a previous rotation is kept private
+		fs := state.ReaderWriter().(afero.Afero)
+		require.NoError(t, fs.WriteFile(flowkit.DefaultKeyRotationPath, nil, 0644))
+
+		rotation := flowkit.NewKeyRotation(alice, tests.PrivKeys()[1], crypto.SHA3_256)
+		rotated, err := s.Accounts.RotateKey(ctx, alice, rotation, flowkit.DefaultKeyRotationPath)
+		require.NoError(t, err)
+		assert.Equal(t, 1, rotated.Key().Index())
+
+		info, err := fs.Stat(flowkit.DefaultKeyRotationPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		acc, err := s.Accounts.GetWithContext(ctx, alice.Address())
+		require.NoError(t, err)
+		require.Len(t, acc.Keys, 2)
+		assert.True(t, acc.Keys[0].Revoked)
+		assert.Equal(t, flow.AccountKeyWeightThreshold, acc.Keys[1].Weight)
+
+		pending, err := s.Accounts.PendingKeyRotation(flowkit.DefaultKeyRotationPath)
+		require.NoError(t, err)
+		assert.Equal(t, rotation.TransactionID, pending.TransactionID)
+
+		require.NoError(t, s.Accounts.ClearKeyRotation(flowkit.DefaultKeyRotationPath))
+		pending, err = s.Accounts.PendingKeyRotation(flowkit.DefaultKeyRotationPath)
+		require.NoError(t, err)
+		assert.Nil(t, pending)
+
+		// the account signs with the new key
+		_, err = s.Accounts.RevokeKey(ctx, rotated, 0)
+		assert.EqualError(t, err, fmt.Sprintf("key 0 of account %s is already revoked", alice.Address()))
+		_, err = s.Accounts.AddKey(ctx, rotated, &flow.AccountKey{
+			PublicKey: tests.PubKeys()[2],
+			SigAlgo:   tests.SigAlgos()[2],
+			HashAlgo:  crypto.SHA3_256,
+			Weight:    500,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Resume Rotation", func(t *testing.T) {
+		t.Parallel()
+		state, s, alice := setupAlice(t)
+		oldKey := alice.Key()
+
+		rotation := flowkit.NewKeyRotation(alice, tests.PrivKeys()[1], crypto.SHA3_256)
+		_, err := s.Accounts.RotateKey(ctx, alice, rotation, flowkit.DefaultKeyRotationPath)
+		require.NoError(t, err)
+
+		// the rotation was interrupted before the configuration was saved
+		interrupted := flowkit.NewAccount(alice.Name()).SetAddress(alice.Address()).SetKey(oldKey)
+		state.Accounts().AddOrUpdate(interrupted)
+
+		pending, err := s.Accounts.PendingKeyRotation(flowkit.DefaultKeyRotationPath)
+		require.NoError(t, err)
+
+		rotated, err := s.Accounts.RotateKey(ctx, interrupted, pending, flowkit.DefaultKeyRotationPath)
+		require.NoError(t, err)
+		assert.Equal(t, 1, rotated.Key().Index())
+
+		acc, err := s.Accounts.GetWithContext(ctx, alice.Address())
+		require.NoError(t, err)
+		assert.Len(t, acc.Keys, 2)
+	})
+
+	t.Run("Rotate Other Account", func(t *testing.T) {
+		t.Parallel()
+		state, s, alice := setupAlice(t)
+
+		bob, err := state.Accounts().ByName(tests.Bob().Name())
+		require.NoError(t, err)
+
+		rotation := flowkit.NewKeyRotation(alice, tests.PrivKeys()[1], crypto.SHA3_256)
+		_, err = s.Accounts.RotateKey(ctx, bob, rotation, flowkit.DefaultKeyRotationPath)
+		assert.EqualError(t, err, fmt.Sprintf("key rotation is for account Alice (0x%s), not Bob", alice.Address()))
+	})
+}
+
+func TestAccountsFund_Integration(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Fund Account", func(t *testing.T) {
+		t.Parallel()
+		state, s := setupIntegration()
+		setupAccounts(state, s)
+
+		alice, err := state.Accounts().ByName(tests.Alice().Name())
+		require.NoError(t, err)
+
+		before, err := s.Accounts.GetWithContext(ctx, alice.Address())
+		require.NoError(t, err)
+
+		amount, _ := cadence.NewUFix64("100.5")
+		acc, err := s.Accounts.Fund(ctx, alice.Address(), amount)
+		require.NoError(t, err)
+		assert.Equal(t, before.Balance+uint64(amount), acc.Balance)
+	})
+
+	t.Run("Fund Invalid", func(t *testing.T) {
+		t.Parallel()
+		_, s := setupIntegration()
+
+		amount, _ := cadence.NewUFix64("1.0")
+		_, err := s.Accounts.Fund(ctx, flow.HexToAddress("1654653399040a61"), amount)
+		assert.EqualError(t, err, "funding accounts is only supported on the emulator, 1654653399040a61 is a flow-mainnet address")
+
+		_, err = s.Accounts.Fund(ctx, flow.HexToAddress("f8d6e0586b0a20c7"), 0)
+		assert.EqualError(t, err, "amount must be greater than zero")
+	})
+}
+
+func keyAddedEvent(address flow.Address, publicKey crypto.PublicKey, keyIndex int) flow.Event {
+	keyBytes := make([]cadence.Value, 0)
+	for _, b := range publicKey.Encode() {
+		keyBytes = append(keyBytes, cadence.NewUInt8(b))
+	}
+
+	return flow.Event{
+		Type: flow.EventAccountKeyAdded,
+		Value: cadence.NewEvent([]cadence.Value{
+			cadence.NewAddress(address),
+			cadence.NewStruct([]cadence.Value{cadence.NewArray(keyBytes)}),
+			cadence.NewInt(keyIndex),
+		}).WithType(&cadence.EventType{
+			QualifiedIdentifier: flow.EventAccountKeyAdded,
+			Fields: []cadence.Field{
+				{Identifier: "address", Type: cadence.AddressType{}},
+				{Identifier: "publicKey", Type: cadence.AnyStructType{}},
+				{Identifier: "keyIndex", Type: cadence.IntType{}},
+			},
+		}),
+	}
+}
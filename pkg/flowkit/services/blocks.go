@@ -19,6 +19,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -57,6 +58,7 @@ func NewBlocks(
 // - height (e.g. 123456789) : return block at this height
 // - ID                      : return block with this ID
 func (e *Blocks) GetBlock(
+	ctx context.Context,
 	query string,
 	eventType string,
 	verbose bool,
@@ -68,11 +70,11 @@ func (e *Blocks) GetBlock(
 	var err error
 	var block *flow.Block
 	if query == "latest" {
-		block, err = e.gateway.GetLatestBlock()
+		block, err = e.gateway.GetLatestBlock(ctx)
 	} else if height, ce := strconv.ParseUint(query, 10, 64); ce == nil {
-		block, err = e.gateway.GetBlockByHeight(height)
+		block, err = e.gateway.GetBlockByHeight(ctx, height)
 	} else if flow.HexToID(query) != flow.EmptyID {
-		block, err = e.gateway.GetBlockByID(flow.HexToID(query))
+		block, err = e.gateway.GetBlockByID(ctx, flow.HexToID(query))
 	} else {
 		return nil, nil, nil, fmt.Errorf("invalid query: %s, valid are: \"latest\", block height or block ID", query)
 	}
@@ -88,7 +90,7 @@ func (e *Blocks) GetBlock(
 	// if we specify event get events by the type
 	var events []flow.BlockEvents
 	if eventType != "" {
-		events, err = e.gateway.GetEvents(eventType, block.Height, block.Height)
+		events, err = e.gateway.GetEvents(ctx, eventType, block.Height, block.Height)
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -98,7 +100,7 @@ func (e *Blocks) GetBlock(
 	collections := make([]*flow.Collection, 0)
 	if verbose {
 		for _, guarantee := range block.CollectionGuarantees {
-			collection, err := e.gateway.GetCollection(guarantee.CollectionID)
+			collection, err := e.gateway.GetCollection(ctx, guarantee.CollectionID)
 			if err != nil {
 				return nil, nil, nil, err
 			}
@@ -112,8 +114,8 @@ func (e *Blocks) GetBlock(
 }
 
 // GetLatestBlockHeight returns the latest block height
-func (e *Blocks) GetLatestBlockHeight() (uint64, error) {
-	block, err := e.gateway.GetLatestBlock()
+func (e *Blocks) GetLatestBlockHeight(ctx context.Context) (uint64, error) {
+	block, err := e.gateway.GetLatestBlock(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -23,7 +23,9 @@ import (
 
 	"github.com/onflow/flow-go-sdk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
+	"github.com/onflow/flow-cli/pkg/flowkit"
 	"github.com/onflow/flow-cli/pkg/flowkit/tests"
 )
 
@@ -35,20 +37,20 @@ func TestBlocks(t *testing.T) {
 
 		_, s, gw := setup()
 
-		_, _, _, err := s.Blocks.GetBlock("latest", "flow.AccountCreated", false)
+		_, _, _, err := s.Blocks.GetBlock(ctx, "latest", "flow.AccountCreated", false)
 
-		gw.Mock.AssertCalled(t, tests.GetLatestBlockFunc)
-		gw.Mock.AssertCalled(t, tests.GetEventsFunc, "flow.AccountCreated", uint64(1), uint64(1))
-		gw.Mock.AssertNotCalled(t, tests.GetBlockByHeightFunc)
-		gw.Mock.AssertNotCalled(t, tests.GetBlockByIDFunc)
+		gw.Mock.AssertCalled(t, tests.GetLatestBlockFunc, mock.Anything)
+		gw.Mock.AssertCalled(t, tests.GetEventsFunc, mock.Anything, "flow.AccountCreated", uint64(1), uint64(1))
+		gw.Mock.AssertNotCalled(t, tests.GetBlockByHeightFunc, mock.Anything, mock.Anything)
+		gw.Mock.AssertNotCalled(t, tests.GetBlockByIDFunc, mock.Anything, mock.Anything)
 		assert.NoError(t, err)
 	})
 
 	t.Run("Get latest block height", func(t *testing.T) {
 		t.Parallel()
 		_, s, gw := setup()
-		height, err := s.Blocks.GetLatestBlockHeight()
-		gw.Mock.AssertCalled(t, tests.GetLatestBlockFunc)
+		height, err := s.Blocks.GetLatestBlockHeight(ctx)
+		gw.Mock.AssertCalled(t, tests.GetLatestBlockFunc, mock.Anything)
 		assert.NoError(t, err)
 		assert.Equal(t, height, uint64(1))
 
@@ -63,12 +65,12 @@ func TestBlocks(t *testing.T) {
 		block.Height = 10
 		gw.GetBlockByHeight.Return(block, nil)
 
-		_, _, _, err := s.Blocks.GetBlock("10", "flow.AccountCreated", false)
+		_, _, _, err := s.Blocks.GetBlock(ctx, "10", "flow.AccountCreated", false)
 
-		gw.Mock.AssertCalled(t, tests.GetBlockByHeightFunc, uint64(10))
-		gw.Mock.AssertCalled(t, tests.GetEventsFunc, "flow.AccountCreated", uint64(10), uint64(10))
-		gw.Mock.AssertNotCalled(t, tests.GetLatestBlockFunc)
-		gw.Mock.AssertNotCalled(t, tests.GetBlockByIDFunc)
+		gw.Mock.AssertCalled(t, tests.GetBlockByHeightFunc, mock.Anything, uint64(10))
+		gw.Mock.AssertCalled(t, tests.GetEventsFunc, mock.Anything, "flow.AccountCreated", uint64(10), uint64(10))
+		gw.Mock.AssertNotCalled(t, tests.GetLatestBlockFunc, mock.Anything)
+		gw.Mock.AssertNotCalled(t, tests.GetBlockByIDFunc, mock.Anything, mock.Anything)
 		assert.NoError(t, err)
 	})
 
@@ -78,13 +80,13 @@ func TestBlocks(t *testing.T) {
 		_, s, gw := setup()
 		ID := "a310685082f0b09f2a148b2e8905f08ea458ed873596b53b200699e8e1f6536f"
 
-		_, _, _, err := s.Blocks.GetBlock(ID, "flow.AccountCreated", false)
+		_, _, _, err := s.Blocks.GetBlock(ctx, ID, "flow.AccountCreated", false)
 
 		assert.NoError(t, err)
-		gw.Mock.AssertCalled(t, tests.GetBlockByIDFunc, flow.HexToID(ID))
-		gw.Mock.AssertCalled(t, tests.GetEventsFunc, "flow.AccountCreated", uint64(1), uint64(1))
-		gw.Mock.AssertNotCalled(t, tests.GetBlockByHeightFunc)
-		gw.Mock.AssertNotCalled(t, tests.GetLatestBlockFunc)
+		gw.Mock.AssertCalled(t, tests.GetBlockByIDFunc, mock.Anything, flow.HexToID(ID))
+		gw.Mock.AssertCalled(t, tests.GetEventsFunc, mock.Anything, "flow.AccountCreated", uint64(1), uint64(1))
+		gw.Mock.AssertNotCalled(t, tests.GetBlockByHeightFunc, mock.Anything, mock.Anything)
+		gw.Mock.AssertNotCalled(t, tests.GetLatestBlockFunc, mock.Anything)
 	})
 
 }
@@ -98,7 +100,7 @@ func TestBlocksGet_Integration(t *testing.T) {
 		state, s := setupIntegration()
 		srvAcc, _ := state.EmulatorServiceAccount()
 
-		block, blockEvents, collection, err := s.Blocks.GetBlock("latest", "", true)
+		block, blockEvents, collection, err := s.Blocks.GetBlock(ctx, "latest", "", true)
 
 		assert.NoError(t, err)
 		assert.Nil(t, blockEvents)
@@ -107,9 +109,9 @@ func TestBlocksGet_Integration(t *testing.T) {
 		assert.Equal(t, block.ID.String(), "13c7ff23bb65feb5757cc65fdd75cd243506518c126385fae530ddebdad10b17")
 
 		// create an event
-		_, _ = s.Accounts.Create(srvAcc, tests.PubKeys(), nil, tests.SigAlgos(), tests.HashAlgos(), nil)
+		_, _ = s.Accounts.CreateWithContext(ctx, srvAcc, tests.PubKeys(), nil, tests.SigAlgos(), tests.HashAlgos(), nil, flowkit.MaxGasLimit, DefaultGasMargin)
 
-		block, blockEvents, _, err = s.Blocks.GetBlock("latest", "flow.AccountCreated", true)
+		block, blockEvents, _, err = s.Blocks.GetBlock(ctx, "latest", "flow.AccountCreated", true)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, block)
@@ -123,7 +125,7 @@ func TestBlocksGet_Integration(t *testing.T) {
 
 		_, s := setupIntegration()
 
-		_, _, _, err := s.Blocks.GetBlock("foo", "flow.AccountCreated", true)
+		_, _, _, err := s.Blocks.GetBlock(ctx, "foo", "flow.AccountCreated", true)
 		assert.Equal(t, err.Error(), "invalid query: foo, valid are: \"latest\", block height or block ID")
 	})
 }
@@ -19,6 +19,8 @@
 package services
 
 import (
+	"context"
+
 	"github.com/onflow/flow-go-sdk"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
@@ -47,6 +49,6 @@ func NewCollections(
 }
 
 // Get returns a collection by ID.
-func (c *Collections) Get(id flow.Identifier) (*flow.Collection, error) {
-	return c.gateway.GetCollection(id)
+func (c *Collections) Get(ctx context.Context, id flow.Identifier) (*flow.Collection, error) {
+	return c.gateway.GetCollection(ctx, id)
 }
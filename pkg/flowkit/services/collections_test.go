@@ -23,6 +23,7 @@ import (
 
 	"github.com/onflow/flow-go-sdk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestCollections(t *testing.T) {
@@ -32,9 +33,9 @@ func TestCollections(t *testing.T) {
 		_, s, gw := setup()
 		ID := flow.HexToID("a310685082f0b09f2a148b2e8905f08ea458ed873596b53b200699e8e1f6536f")
 
-		_, err := s.Collections.Get(ID)
+		_, err := s.Collections.Get(ctx, ID)
 
 		assert.NoError(t, err)
-		gw.Mock.AssertCalled(t, "GetCollection", ID)
+		gw.Mock.AssertCalled(t, "GetCollection", mock.Anything, ID)
 	})
 }
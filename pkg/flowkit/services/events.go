@@ -19,11 +19,18 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/access/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
 
@@ -51,6 +58,9 @@ func NewEvents(
 	}
 }
 
+// maxEventsBlockCount is the largest block range access nodes serve in a single events request.
+const maxEventsBlockCount = 250
+
 func makeEventQueries(events []string, startHeight uint64, endHeight uint64, blockCount uint64) []grpc.EventRangeQuery {
 	var queries []grpc.EventRangeQuery
 	for startHeight <= endHeight {
@@ -72,7 +82,7 @@ func makeEventQueries(events []string, startHeight uint64, endHeight uint64, blo
 
 }
 
-func (e *Events) Get(events []string, startHeight uint64, endHeight uint64, blockCount uint64, workerCount int) ([]flow.BlockEvents, error) {
+func (e *Events) Get(ctx context.Context, events []string, startHeight uint64, endHeight uint64, blockCount uint64, workerCount int) ([]flow.BlockEvents, error) {
 	if endHeight < startHeight {
 		return nil, fmt.Errorf("cannot have end height (%d) of block range less that start height (%d)", endHeight, startHeight)
 	}
@@ -80,60 +90,450 @@ func (e *Events) Get(events []string, startHeight uint64, endHeight uint64, bloc
 	e.logger.StartProgress("Fetching events...")
 	defer e.logger.StopProgress()
 
-	queries := makeEventQueries(events, startHeight, endHeight, blockCount)
+	return e.get(ctx, events, startHeight, endHeight, blockCount, workerCount, func(fetched uint64, total uint64) {
+		e.logger.StartProgress(fmt.Sprintf("Fetching events... %d/%d blocks", fetched, total))
+	})
+}
+
+// GetFiltered gets the events in the block range and returns only the events matching the filter.
+func (e *Events) GetFiltered(
+	ctx context.Context,
+	events []string,
+	startHeight uint64,
+	endHeight uint64,
+	blockCount uint64,
+	workerCount int,
+	filter *flowkit.EventFilter,
+) ([]flow.BlockEvents, error) {
+	blockEvents, err := e.Get(ctx, events, startHeight, endHeight, blockCount, workerCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter.FilterBlockEvents(blockEvents)
+}
+
+// EventsHandler is called with the events of each new range of blocks while watching events.
+type EventsHandler func(blockEvents []flow.BlockEvents) error
+
+// Watch follows the chain from the start height and calls the handler with the events of every new
+// range of sealed blocks, ordered by height, until the context is done or the handler returns an error.
+//
+// Sealed blocks are final, so each height is fetched exactly once. When the chain is ahead by more
+// than the worker pool can fetch in one round, the range is capped and the remaining heights are
+// fetched in the next round, the handler is called synchronously so a slow consumer slows down fetching.
+func (e *Events) Watch(
+	ctx context.Context,
+	events []string,
+	startHeight uint64,
+	blockCount uint64,
+	workerCount int,
+	pollInterval time.Duration,
+	handler EventsHandler,
+) error {
+	blockCount, workerCount = eventsPoolSize(blockCount, workerCount)
+	maxRange := blockCount * uint64(workerCount)
+	next := startHeight
+
+	for {
+		latest, err := e.gateway.GetLatestBlock(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		caughtUp := true
+		if latest.Height >= next {
+			end := latest.Height
+			if end-next+1 > maxRange {
+				end = next + maxRange - 1
+				caughtUp = false
+			}
+
+			blockEvents, err := e.get(ctx, events, next, end, blockCount, workerCount, nil)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			err = handler(blockEvents)
+			if err != nil {
+				return err
+			}
+
+			next = end + 1
+		}
+
+		if caughtUp {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(pollInterval):
+			}
+		}
+	}
+}
+
+// HeightRange is an inclusive range of block heights.
+type HeightRange struct {
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+}
+
+// ExportCheckpoint records which height ranges of an events export were already written,
+// so an interrupted export can be resumed from where it stopped.
+type ExportCheckpoint struct {
+	Events      []string      `json:"events"`
+	StartHeight uint64        `json:"startHeight"`
+	EndHeight   uint64        `json:"endHeight"`
+	Completed   []HeightRange `json:"completed"`
+}
+
+// NewExportCheckpoint returns a new checkpoint for exporting the events in the height range.
+func NewExportCheckpoint(events []string, startHeight uint64, endHeight uint64) *ExportCheckpoint {
+	return &ExportCheckpoint{
+		Events:      events,
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+		Completed:   make([]HeightRange, 0),
+	}
+}
+
+// Remaining returns the height ranges which weren't exported yet.
+func (c *ExportCheckpoint) Remaining() []HeightRange {
+	remaining := make([]HeightRange, 0)
+	next := c.StartHeight
+	for _, completed := range c.Completed {
+		if completed.Start > next {
+			remaining = append(remaining, HeightRange{Start: next, End: completed.Start - 1})
+		}
+		if completed.End >= next {
+			next = completed.End + 1
+		}
+	}
+
+	if next <= c.EndHeight {
+		remaining = append(remaining, HeightRange{Start: next, End: c.EndHeight})
+	}
+
+	return remaining
+}
+
+// complete marks the height range as exported, overlapping and adjacent ranges are merged.
+func (c *ExportCheckpoint) complete(r HeightRange) {
+	completed := append(c.Completed, r)
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].Start < completed[j].Start
+	})
+
+	merged := make([]HeightRange, 0, len(completed))
+	for _, current := range completed {
+		last := len(merged) - 1
+		if last >= 0 && current.Start <= merged[last].End+1 {
+			if current.End > merged[last].End {
+				merged[last].End = current.End
+			}
+			continue
+		}
+		merged = append(merged, current)
+	}
+
+	c.Completed = merged
+}
+
+// EventsWriter writes exported events.
+type EventsWriter interface {
+	Write(blockEvents []flow.BlockEvents) error
+}
+
+// Export fetches the events of the checkpoint height ranges which weren't exported yet and writes them
+// to the writer in height order, one range at a time so the events are never all held in memory.
+//
+// After each range is written the checkpoint is updated and passed to the save function. The writer
+// must persist the events before returning, otherwise a resumed export could skip them.
+func (e *Events) Export(
+	ctx context.Context,
+	checkpoint *ExportCheckpoint,
+	blockCount uint64,
+	workerCount int,
+	writer EventsWriter,
+	saveCheckpoint func(checkpoint *ExportCheckpoint) error,
+) error {
+	if checkpoint.EndHeight < checkpoint.StartHeight {
+		return fmt.Errorf(
+			"cannot have end height (%d) of block range less that start height (%d)",
+			checkpoint.EndHeight,
+			checkpoint.StartHeight,
+		)
+	}
+
+	defer e.logger.StopProgress()
+
+	blockCount, workerCount = eventsPoolSize(blockCount, workerCount)
+	rangeSize := blockCount * uint64(workerCount)
+	for _, remaining := range checkpoint.Remaining() {
+		for start := remaining.Start; start <= remaining.End; {
+			end := remaining.End
+			if end-start >= rangeSize {
+				end = start + rangeSize - 1
+			}
+
+			e.logger.StartProgress(fmt.Sprintf("Exporting events from blocks %d to %d...", start, end))
+
+			blockEvents, err := e.get(ctx, checkpoint.Events, start, end, blockCount, workerCount, nil)
+			if err != nil {
+				return fmt.Errorf("failed to export events from blocks %d to %d: %w", start, end, err)
+			}
+
+			err = writer.Write(blockEvents)
+			if err != nil {
+				return err
+			}
 
-	jobChan := make(chan grpc.EventRangeQuery, workerCount)
+			checkpoint.complete(HeightRange{Start: start, End: end})
+			err = saveCheckpoint(checkpoint)
+			if err != nil {
+				return fmt.Errorf("failed to save export checkpoint: %w", err)
+			}
+
+			if end == remaining.End {
+				break
+			}
+			start = end + 1
+		}
+	}
+
+	return nil
+}
+
+// eventsPoolSize returns the block count and the worker count raised to at least one,
+// so the ranges fetched by the worker pool are never empty.
+func eventsPoolSize(blockCount uint64, workerCount int) (uint64, int) {
+	if blockCount == 0 {
+		blockCount = 1
+	}
+	if workerCount <= 0 {
+		workerCount = 1
+	}
+
+	return blockCount, workerCount
+}
+
+// eventsProgressHandler is called with the number of blocks fetched so far out of the total.
+type eventsProgressHandler func(fetched uint64, total uint64)
+
+// get fetches the events in the block range with a pool of workers and returns them ordered by height,
+// events of the same height are ordered as the requested event types.
+//
+// The block count adapts to the access node, when a range is rejected as too large or times out it's
+// split in half and retried, and later ranges use the smaller block count. After each successful
+// range the block count grows again, up to the larger of the requested count and maxEventsBlockCount.
+func (e *Events) get(
+	ctx context.Context,
+	events []string,
+	startHeight uint64,
+	endHeight uint64,
+	blockCount uint64,
+	workerCount int,
+	progress eventsProgressHandler,
+) ([]flow.BlockEvents, error) {
+	blockCount, workerCount = eventsPoolSize(blockCount, workerCount)
+
+	maxBlockCount := blockCount
+	if maxBlockCount < maxEventsBlockCount {
+		maxBlockCount = maxEventsBlockCount
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	jobs := make(chan grpc.EventRangeQuery)
 	results := make(chan EventWorkerResult)
 
 	var wg sync.WaitGroup
-
 	for i := 0; i < workerCount; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			e.eventWorker(jobChan, results)
+			e.eventWorker(ctx, jobs, results)
 		}()
 	}
 
-	// wait on the workers to finish and close the result channel
-	// to signal downstream that all work is done
-	go func() {
-		defer close(results)
+	// on return stop the workers, including any still fetching after an error
+	defer func() {
+		cancel()
+		close(jobs)
 		wg.Wait()
 	}()
 
-	go func() {
-		defer close(jobChan)
-		for _, query := range queries {
-			jobChan <- query
+	total := endHeight - startHeight + 1
+	var fetched uint64 // number of heights fetched for all the event types
+
+	var pending []grpc.EventRangeQuery
+	var completed []EventWorkerResult
+	next := startHeight
+	inFlight := 0
+
+	for {
+		if len(pending) == 0 && next <= endHeight {
+			end := endHeight
+			if end-next >= blockCount {
+				end = next + blockCount - 1
+			}
+			pending = makeEventQueries(events, next, end, blockCount)
+			next = end + 1
 		}
-	}()
 
-	var resultEvents []flow.BlockEvents
-	for eventResult := range results {
-		if eventResult.Error != nil {
-			return nil, eventResult.Error
+		if len(pending) == 0 && inFlight == 0 {
+			break
+		}
+
+		// only send when there's a query pending, sending on a nil channel blocks forever
+		var sendJobs chan<- grpc.EventRangeQuery
+		var query grpc.EventRangeQuery
+		if len(pending) > 0 {
+			sendJobs = jobs
+			query = pending[0]
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case sendJobs <- query:
+			pending = pending[1:]
+			inFlight++
+
+		case result := <-results:
+			inFlight--
+			q := result.Query
+			size := q.EndHeight - q.StartHeight + 1
+
+			if result.Error != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				if size == 1 || !isEventsRangeError(result.Error) {
+					return nil, result.Error
+				}
+
+				half := size / 2
+				e.logger.Debug(fmt.Sprintf(
+					"Fetching %s events from blocks %d to %d failed, retrying with %d blocks per request: %s",
+					q.Type, q.StartHeight, q.EndHeight, half, result.Error,
+				))
+
+				// retry the halves before any new ranges
+				pending = append([]grpc.EventRangeQuery{
+					{Type: q.Type, StartHeight: q.StartHeight, EndHeight: q.StartHeight + half - 1},
+					{Type: q.Type, StartHeight: q.StartHeight + half, EndHeight: q.EndHeight},
+				}, pending...)
+
+				if half < blockCount {
+					blockCount = half
+				}
+				continue
+			}
+
+			completed = append(completed, result)
+
+			if size >= blockCount && blockCount < maxBlockCount {
+				blockCount += blockCount/2 + 1
+				if blockCount > maxBlockCount {
+					blockCount = maxBlockCount
+				}
+			}
+
+			fetched += size
+			if progress != nil {
+				progress(fetched/uint64(len(events)), total)
+			}
+		}
+	}
+
+	return orderBlockEvents(events, completed), nil
+}
+
+// orderBlockEvents returns the fetched block events ordered by height and then by the order of the event types.
+func orderBlockEvents(events []string, results []EventWorkerResult) []flow.BlockEvents {
+	typeOrder := make(map[string]int, len(events))
+	for i, event := range events {
+		if _, ok := typeOrder[event]; !ok {
+			typeOrder[event] = i
+		}
+	}
+
+	type orderedBlockEvents struct {
+		blockEvents flow.BlockEvents
+		typeOrder   int
+	}
+
+	var ordered []orderedBlockEvents
+	for _, result := range results {
+		for _, blockEvents := range result.Events {
+			ordered = append(ordered, orderedBlockEvents{
+				blockEvents: blockEvents,
+				typeOrder:   typeOrder[result.Query.Type],
+			})
+		}
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].blockEvents.Height != ordered[j].blockEvents.Height {
+			return ordered[i].blockEvents.Height < ordered[j].blockEvents.Height
 		}
+		return ordered[i].typeOrder < ordered[j].typeOrder
+	})
 
-		resultEvents = append(resultEvents, eventResult.Events...)
+	blockEvents := make([]flow.BlockEvents, len(ordered))
+	for i, o := range ordered {
+		blockEvents[i] = o.blockEvents
 	}
 
-	return resultEvents, nil
+	return blockEvents
+}
+
+// isEventsRangeError returns true if the error could be caused by requesting too many blocks at once,
+// so the request is expected to succeed with a smaller range.
+func isEventsRangeError(err error) bool {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return errors.Is(err, context.DeadlineExceeded)
+	}
 
+	switch grpcErr.GRPCStatus().Code() {
+	case codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	case codes.InvalidArgument, codes.OutOfRange:
+		message := strings.ToLower(grpcErr.GRPCStatus().Message())
+		return strings.Contains(message, "exceeds") ||
+			strings.Contains(message, "too large") ||
+			strings.Contains(message, "limit")
+	default:
+		return false
+	}
 }
 
-func (e *Events) eventWorker(jobChan <-chan grpc.EventRangeQuery, results chan<- EventWorkerResult) {
+// eventWorker fetches the events of each query and sends the result, it stops when the context is done.
+func (e *Events) eventWorker(ctx context.Context, jobChan <-chan grpc.EventRangeQuery, results chan<- EventWorkerResult) {
 	for q := range jobChan {
-		//e.logger.Debug(fmt.Sprintf("Fetching events %v", q))
-		blockEvents, err := e.gateway.GetEvents(q.Type, q.StartHeight, q.EndHeight)
+		blockEvents, err := e.gateway.GetEvents(ctx, q.Type, q.StartHeight, q.EndHeight)
 		if err != nil {
-			results <- EventWorkerResult{nil, err}
+			blockEvents = nil
+		}
+
+		select {
+		case results <- EventWorkerResult{Query: q, Events: blockEvents, Error: err}:
+		case <-ctx.Done():
+			return
 		}
-		results <- EventWorkerResult{blockEvents, nil}
 	}
 }
 
 type EventWorkerResult struct {
+	Query  grpc.EventRangeQuery
 	Events []flow.BlockEvents
 	Error  error
 }
@@ -19,14 +19,21 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/onflow/cadence"
 	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/access/grpc"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/onflow/flow-cli/pkg/flowkit"
 	"github.com/onflow/flow-cli/pkg/flowkit/tests"
 )
 
@@ -37,17 +44,17 @@ func TestEvents(t *testing.T) {
 		t.Parallel()
 
 		_, s, gw := setup()
-		_, err := s.Events.Get([]string{"flow.CreateAccount"}, 0, 0, 250, 1)
+		_, err := s.Events.Get(ctx, []string{"flow.CreateAccount"}, 0, 0, 250, 1)
 
 		assert.NoError(t, err)
-		gw.Mock.AssertCalled(t, tests.GetEventsFunc, "flow.CreateAccount", uint64(0), uint64(0))
+		gw.Mock.AssertCalled(t, tests.GetEventsFunc, mock.Anything, "flow.CreateAccount", uint64(0), uint64(0))
 	})
 
 	t.Run("Should have larger endHeight then startHeight", func(t *testing.T) {
 		t.Parallel()
 
 		_, s, _ := setup()
-		_, err := s.Events.Get([]string{"flow.CreateAccount"}, 10, 0, 250, 1)
+		_, err := s.Events.Get(ctx, []string{"flow.CreateAccount"}, 10, 0, 250, 1)
 		assert.EqualError(t, err, "cannot have end height (0) of block range less that start height (10)")
 	})
 
@@ -71,11 +78,230 @@ func TestEvents(t *testing.T) {
 
 		gw.GetEvents.Return([]flow.BlockEvents{}, errors.New("failed getting event"))
 
-		_, err := s.Events.Get([]string{"flow.CreateAccount"}, 0, 1, 250, 1)
+		_, err := s.Events.Get(ctx, []string{"flow.CreateAccount"}, 0, 1, 250, 1)
 
 		assert.EqualError(t, err, "failed getting event")
 	})
 
+	t.Run("Get Events Ordered By Height", func(t *testing.T) {
+		t.Parallel()
+
+		_, s, gw := setup()
+
+		gw.GetEvents.Run(func(args mock.Arguments) {
+			var blockEvents []flow.BlockEvents
+			for height := args.Get(3).(uint64); height >= args.Get(2).(uint64); height-- {
+				blockEvents = append(blockEvents, flow.BlockEvents{
+					Height: height,
+					Events: []flow.Event{{Type: args.Get(1).(string)}},
+				})
+			}
+			gw.GetEvents.Return(blockEvents, nil)
+		})
+
+		events, err := s.Events.Get(ctx, []string{"B", "A"}, 1, 4, 2, 1)
+		assert.NoError(t, err)
+
+		var order []string
+		for _, blockEvents := range events {
+			order = append(order, fmt.Sprintf("%d%s", blockEvents.Height, blockEvents.Events[0].Type))
+		}
+		assert.Equal(t, []string{"1B", "1A", "2B", "2A", "3B", "3A", "4B", "4A"}, order)
+	})
+
+	t.Run("Split Range Rejected As Too Large", func(t *testing.T) {
+		t.Parallel()
+
+		_, s, gw := setup()
+
+		gw.GetEvents.Run(func(args mock.Arguments) {
+			start, end := args.Get(2).(uint64), args.Get(3).(uint64)
+			if end-start+1 > 3 {
+				gw.GetEvents.Return(
+					[]flow.BlockEvents(nil),
+					status.Error(codes.InvalidArgument, "height range (10) exceeds maximum (3)"),
+				)
+				return
+			}
+
+			var blockEvents []flow.BlockEvents
+			for height := start; height <= end; height++ {
+				blockEvents = append(blockEvents, flow.BlockEvents{Height: height})
+			}
+			gw.GetEvents.Return(blockEvents, nil)
+		})
+
+		events, err := s.Events.Get(ctx, []string{"flow.CreateAccount"}, 1, 10, 10, 1)
+		assert.NoError(t, err)
+
+		var heights []uint64
+		for _, blockEvents := range events {
+			heights = append(heights, blockEvents.Height)
+		}
+		assert.Equal(t, []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, heights)
+	})
+
+	t.Run("Should not split a range on other errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, s, gw := setup()
+
+		gw.GetEvents.Return([]flow.BlockEvents(nil), status.Error(codes.NotFound, "event type not found"))
+
+		_, err := s.Events.Get(ctx, []string{"flow.CreateAccount"}, 1, 100, 50, 4)
+		assert.Error(t, err)
+		assert.LessOrEqual(t, len(gw.Mock.Calls), 4)
+	})
+
+	t.Run("Get Filtered Events", func(t *testing.T) {
+		t.Parallel()
+
+		_, s, gw := setup()
+
+		fields := []cadence.Field{{Identifier: "amount", Type: cadence.IntType{}}}
+		small := tests.NewEvent(0, "Deposit", fields, []cadence.Value{cadence.NewInt(1)})
+		large := tests.NewEvent(1, "Deposit", fields, []cadence.Value{cadence.NewInt(100)})
+		gw.GetEvents.Return([]flow.BlockEvents{{Height: 1, Events: []flow.Event{*small, *large}}}, nil)
+
+		filter, err := flowkit.ParseEventFilter("amount > 10")
+		assert.NoError(t, err)
+
+		events, err := s.Events.GetFiltered(ctx, []string{"Deposit"}, 1, 1, 250, 1, filter)
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+		assert.Equal(t, []flow.Event{*large}, events[0].Events)
+	})
+
+	t.Run("Watch Events", func(t *testing.T) {
+		t.Parallel()
+
+		_, s, gw := setup()
+
+		block := tests.NewBlock()
+		block.Height = 5
+		gw.GetLatestBlock.Return(block, nil)
+
+		gw.GetEvents.Run(func(args mock.Arguments) {
+			var blockEvents []flow.BlockEvents
+			for height := args.Get(2).(uint64); height <= args.Get(3).(uint64); height++ {
+				blockEvents = append(blockEvents, flow.BlockEvents{Height: height})
+			}
+			gw.GetEvents.Return(blockEvents, nil)
+		})
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var heights []uint64
+		err := s.Events.Watch(watchCtx, []string{"flow.CreateAccount"}, 3, 2, 1, time.Second, func(blockEvents []flow.BlockEvents) error {
+			for _, be := range blockEvents {
+				heights = append(heights, be.Height)
+			}
+			if heights[len(heights)-1] == block.Height {
+				cancel()
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{3, 4, 5}, heights)
+		gw.Mock.AssertNumberOfCalls(t, tests.GetEventsFunc, 2)
+	})
+
+	t.Run("Watch Events Without Workers", func(t *testing.T) {
+		t.Parallel()
+
+		_, s, gw := setup()
+
+		block := tests.NewBlock()
+		block.Height = 4
+		gw.GetLatestBlock.Return(block, nil)
+
+		gw.GetEvents.Run(func(args mock.Arguments) {
+			gw.GetEvents.Return([]flow.BlockEvents{{Height: args.Get(2).(uint64)}}, nil)
+		})
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// the zero batch and worker count are raised to one, so the watch still advances
+		var heights []uint64
+		err := s.Events.Watch(watchCtx, []string{"flow.CreateAccount"}, 3, 0, 0, time.Second, func(blockEvents []flow.BlockEvents) error {
+			for _, be := range blockEvents {
+				heights = append(heights, be.Height)
+			}
+			if heights[len(heights)-1] == block.Height {
+				cancel()
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{3, 4}, heights)
+	})
+
+	t.Run("Export Checkpoint", func(t *testing.T) {
+		checkpoint := NewExportCheckpoint([]string{"flow.CreateAccount"}, 0, 100)
+		checkpoint.complete(HeightRange{Start: 20, End: 29})
+		checkpoint.complete(HeightRange{Start: 0, End: 9})
+		checkpoint.complete(HeightRange{Start: 10, End: 19})
+		checkpoint.complete(HeightRange{Start: 50, End: 59})
+
+		assert.Equal(t, []HeightRange{{Start: 0, End: 29}, {Start: 50, End: 59}}, checkpoint.Completed)
+		assert.Equal(t, []HeightRange{{Start: 30, End: 49}, {Start: 60, End: 100}}, checkpoint.Remaining())
+	})
+
+	t.Run("Export Events", func(t *testing.T) {
+		t.Parallel()
+
+		_, s, gw := setup()
+
+		gw.GetEvents.Run(func(args mock.Arguments) {
+			var blockEvents []flow.BlockEvents
+			for height := args.Get(3).(uint64); height >= args.Get(2).(uint64); height-- {
+				blockEvents = append(blockEvents, flow.BlockEvents{Height: height})
+			}
+			gw.GetEvents.Return(blockEvents, nil)
+		})
+
+		checkpoint := NewExportCheckpoint([]string{"flow.CreateAccount"}, 1, 10)
+		checkpoint.complete(HeightRange{Start: 1, End: 4})
+
+		writer := &testEventsWriter{}
+		saved := 0
+		err := s.Events.Export(ctx, checkpoint, 2, 2, writer, func(checkpoint *ExportCheckpoint) error {
+			saved++
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{5, 6, 7, 8, 9, 10}, writer.heights)
+		assert.Equal(t, []HeightRange{{Start: 1, End: 10}}, checkpoint.Completed)
+		assert.Len(t, checkpoint.Remaining(), 0)
+		assert.Equal(t, 2, saved)
+	})
+
+	t.Run("Export Events Without Workers", func(t *testing.T) {
+		t.Parallel()
+
+		_, s, gw := setup()
+
+		gw.GetEvents.Run(func(args mock.Arguments) {
+			gw.GetEvents.Return([]flow.BlockEvents{{Height: args.Get(2).(uint64)}}, nil)
+		})
+
+		// the zero batch and worker count are raised to one, so the export still advances
+		checkpoint := NewExportCheckpoint([]string{"flow.CreateAccount"}, 0, 2)
+		writer := &testEventsWriter{}
+		err := s.Events.Export(ctx, checkpoint, 0, 0, writer, func(checkpoint *ExportCheckpoint) error {
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{0, 1, 2}, writer.heights)
+		assert.Len(t, checkpoint.Remaining(), 0)
+	})
+
 }
 
 func TestEvents_Integration(t *testing.T) {
@@ -86,7 +312,7 @@ func TestEvents_Integration(t *testing.T) {
 
 		_, s := setupIntegration()
 
-		events, err := s.Events.Get([]string{"nonexisting"}, 0, 0, 250, 1)
+		events, err := s.Events.Get(ctx, []string{"nonexisting"}, 0, 0, 250, 1)
 		assert.NoError(t, err)
 		assert.Len(t, events, 1)
 		assert.Len(t, events[0].Events, 0)
@@ -99,7 +325,8 @@ func TestEvents_Integration(t *testing.T) {
 		srvAcc, _ := state.EmulatorServiceAccount()
 
 		// create events
-		_, err := s.Accounts.AddContract(
+		_, err := s.Accounts.AddContractWithContext(
+			ctx,
 			srvAcc,
 			resourceToContract(tests.ContractEvents),
 			false,
@@ -108,7 +335,7 @@ func TestEvents_Integration(t *testing.T) {
 		assert.NoError(t, err)
 		for x := 'A'; x <= 'J'; x++ { // test contract emits events named from A to J
 			eName := fmt.Sprintf("A.%s.ContractEvents.Event%c", srvAcc.Address().String(), x)
-			events, err := s.Events.Get([]string{eName}, 0, 1, 250, 1)
+			events, err := s.Events.Get(ctx, []string{eName}, 0, 1, 250, 1)
 			assert.NoError(t, err)
 			assert.Len(t, events, 2)
 			assert.Len(t, events[1].Events, 1)
@@ -123,7 +350,8 @@ func TestEvents_Integration(t *testing.T) {
 		srvAcc, _ := state.EmulatorServiceAccount()
 
 		// create events
-		_, err := s.Accounts.AddContract(
+		_, err := s.Accounts.AddContractWithContext(
+			ctx,
 			srvAcc,
 			resourceToContract(tests.ContractEvents),
 			false,
@@ -137,9 +365,25 @@ func TestEvents_Integration(t *testing.T) {
 			eventNames = append(eventNames, eName)
 		}
 
-		events, err := s.Events.Get(eventNames, 0, 1, 250, 5)
+		events, err := s.Events.Get(ctx, eventNames, 0, 1, 250, 5)
 		assert.NoError(t, err)
 		assert.Len(t, events, 20)
-		assert.Len(t, events[1].Events, 1)
+		// events are ordered by height, so the events emitted at height 1 come after the empty height 0 results
+		for i, blockEvents := range events[10:] {
+			assert.Equal(t, uint64(1), blockEvents.Height)
+			assert.Len(t, blockEvents.Events, 1)
+			assert.Equal(t, eventNames[i], blockEvents.Events[0].Type)
+		}
 	})
 }
+
+type testEventsWriter struct {
+	heights []uint64
+}
+
+func (w *testEventsWriter) Write(blockEvents []flow.BlockEvents) error {
+	for _, be := range blockEvents {
+		w.heights = append(w.heights, be.Height)
+	}
+	return nil
+}
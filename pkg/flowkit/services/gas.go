@@ -0,0 +1,106 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/gateway"
+	"github.com/onflow/flow-cli/pkg/flowkit/output"
+)
+
+// DefaultGasMargin is the default safety margin added to estimated gas limits, in percent of the estimated computation.
+const DefaultGasMargin uint64 = 20
+
+// gasEstimation estimates gas limits of transactions by executing them on an in-process emulator.
+type gasEstimation struct {
+	gateway gateway.Gateway
+	logger  output.Logger
+}
+
+func newGasEstimation(gateway gateway.Gateway, logger output.Logger) *gasEstimation {
+	return &gasEstimation{
+		gateway: gateway,
+		logger:  logger,
+	}
+}
+
+// estimate returns the gas limit of the transaction, which is the computation it used on the emulator
+// with the margin added, in percent of the computation.
+//
+// The networks meter computation with different weights, so the transaction is executed with the default
+// weights and the execution effort weights, and the larger computation is used.
+func (g *gasEstimation) estimate(ctx context.Context, tx *flowkit.Transaction, margin uint64) (uint64, error) {
+	g.logger.StartProgress("Estimating gas limit...")
+	defer g.logger.StopProgress()
+
+	var computation uint64
+	for _, executionEffortWeights := range []bool{false, true} {
+		estimator, err := gateway.NewGasEstimator(g.gateway, executionEffortWeights)
+		if err != nil {
+			return 0, err
+		}
+
+		used, err := estimator.ComputationUsed(ctx, tx.FlowTransaction())
+		if err != nil {
+			return 0, fmt.Errorf("failed to estimate gas limit: %w", err)
+		}
+
+		if used > computation {
+			computation = used
+		}
+	}
+
+	if computation > flowkit.MaxGasLimit {
+		return 0, fmt.Errorf(
+			"failed to estimate gas limit: transaction uses %d computation, more than the maximum gas limit %d",
+			computation, flowkit.MaxGasLimit,
+		)
+	}
+
+	gasLimit := computation + computation*margin/100 + 1
+	if gasLimit > flowkit.MaxGasLimit {
+		gasLimit = flowkit.MaxGasLimit
+	}
+
+	g.logger.Debug(fmt.Sprintf(
+		"Estimated gas limit %d, transaction used %d computation with a %d%% margin", gasLimit, computation, margin,
+	))
+
+	return gasLimit, nil
+}
+
+// setGasLimit sets the gas limit of the transaction, if the gas limit is zero it's estimated with the margin.
+//
+// The estimation fails for transactions using the storage of their authorizers, since the emulator
+// only has a copy of the contracts, then the gas limit must be provided.
+func (g *gasEstimation) setGasLimit(ctx context.Context, tx *flowkit.Transaction, gasLimit uint64, margin uint64) error {
+	if gasLimit == 0 {
+		estimated, err := g.estimate(ctx, tx, margin)
+		if err != nil {
+			return err
+		}
+		gasLimit = estimated
+	}
+
+	tx.SetGasLimit(gasLimit)
+	return nil
+}
@@ -20,9 +20,19 @@ package services
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path"
+	"sort"
 	"strings"
 
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser"
+
+	"github.com/pmezard/go-difflib/difflib"
+
 	"github.com/onflow/flow-cli/pkg/flowkit"
 	"github.com/onflow/flow-go-sdk"
 
@@ -40,6 +50,7 @@ type Project struct {
 	gateway gateway.Gateway
 	state   *flowkit.State
 	logger  output.Logger
+	gas     *gasEstimation
 }
 
 // NewProject returns a new state service.
@@ -52,6 +63,7 @@ func NewProject(
 		gateway: gateway,
 		state:   state,
 		logger:  logger,
+		gas:     newGasEstimation(gateway, logger),
 	}
 }
 
@@ -213,7 +225,16 @@ func (p *Project) CheckForStandardContractUsageOnMainnet() error {
 // Retrieve all the contracts for specified network, sort them for deployment
 // deploy one by one and replace the imports in the contract source so it corresponds
 // to the account name the contract was deployed to.
-func (p *Project) Deploy(network string, update bool) ([]*contracts.Contract, error) {
+//
+// If the gas limit is zero it's estimated for each contract by executing the deployment on an in-process
+// emulator and adding the gas margin, see Transactions.Build.
+func (p *Project) Deploy(
+	ctx context.Context,
+	network string,
+	update bool,
+	gasLimit uint64,
+	gasMargin uint64,
+) ([]*contracts.Contract, error) {
 	if p.state == nil {
 		return nil, config.ErrDoesNotExist
 	}
@@ -224,41 +245,7 @@ func (p *Project) Deploy(network string, update bool) ([]*contracts.Contract, er
 		)
 	}
 
-	// create new processor for contract
-	processor := contracts.NewPreprocessor(
-		contracts.FilesystemLoader{
-			Reader: p.state.ReaderWriter(),
-		},
-		p.state.AliasesForNetwork(network),
-	)
-
-	// add all contracts needed to deploy to processor
-	contractsNetwork, err := p.state.DeploymentContractsByNetwork(network)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, contract := range contractsNetwork {
-		err := processor.AddContractSource(
-			contract.Name,
-			contract.Source,
-			contract.AccountAddress,
-			contract.AccountName,
-			contract.Args,
-		)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	// resolve imports assigns accounts to imports
-	err = processor.ResolveImports()
-	if err != nil {
-		return nil, err
-	}
-
-	// sort correct deployment order of contracts so we don't have import that is not yet deployed
-	orderedContracts, err := processor.ContractDeploymentOrder()
+	orderedContracts, err := p.deploymentContracts(network)
 	if err != nil {
 		return nil, err
 	}
@@ -273,7 +260,7 @@ func (p *Project) Deploy(network string, update bool) ([]*contracts.Contract, er
 	deployErr := false
 	numOfUpdates := 0
 	for _, contract := range orderedContracts {
-		block, err := p.gateway.GetLatestBlock()
+		block, err := p.gateway.GetLatestBlock(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -285,7 +272,7 @@ func (p *Project) Deploy(network string, update bool) ([]*contracts.Contract, er
 		}
 
 		// get deployment account
-		targetAccountInfo, err := p.gateway.GetAccount(targetAccount.Address())
+		targetAccountInfo, err := p.gateway.GetAccount(ctx, targetAccount.Address())
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch information for account %s with error %s", targetAccount.Address(), err.Error())
 		}
@@ -340,6 +327,13 @@ func (p *Project) Deploy(network string, update bool) ([]*contracts.Contract, er
 			return nil, err
 		}
 
+		err = p.gas.setGasLimit(ctx, tx, gasLimit, gasMargin)
+		if err != nil {
+			p.logger.Error(fmt.Sprintf("%s error: %s", contract.Name(), err))
+			deployErr = true
+			continue
+		}
+
 		tx, err = tx.Sign()
 		if err != nil {
 			p.logger.Error(fmt.Sprintf("%s error: %s", contract.Name(), err))
@@ -351,7 +345,7 @@ func (p *Project) Deploy(network string, update bool) ([]*contracts.Contract, er
 			fmt.Sprintf("%s deploying...", output.Bold(contract.Name())),
 		)
 
-		sentTx, err := p.gateway.SendSignedTransaction(tx)
+		sentTx, err := p.gateway.SendSignedTransaction(ctx, tx)
 		if err != nil {
 			p.logger.StopProgress()
 			p.logger.Error(fmt.Sprintf("%s error: %s", contract.Name(), err))
@@ -359,7 +353,7 @@ func (p *Project) Deploy(network string, update bool) ([]*contracts.Contract, er
 			continue
 		}
 
-		result, err := p.gateway.GetTransactionResult(sentTx.ID(), true)
+		result, err := p.gateway.GetTransactionResult(ctx, sentTx.ID(), true)
 		if err != nil {
 			p.logger.StopProgress()
 			p.logger.Error(fmt.Sprintf("%s error: %s", contract.Name(), err))
@@ -418,3 +412,413 @@ func (p *Project) Deploy(network string, update bool) ([]*contracts.Contract, er
 
 	return orderedContracts, nil
 }
+
+// ContractDiff is the difference between a contract resolved for deployment and the code deployed on-chain.
+type ContractDiff struct {
+	Name    string
+	Account string
+	Address flow.Address
+	// Source is the location of the local contract, empty if the contract is missing from the deployment config.
+	Source string
+	Local  string
+	Remote string
+	// Deployed is false if the contract doesn't exist on-chain.
+	Deployed bool
+	// Configured is false if the contract exists on-chain but is missing from the deployment config.
+	Configured bool
+}
+
+// Changed returns true if the local and on-chain contracts don't match.
+func (d *ContractDiff) Changed() bool {
+	return !d.Deployed || !d.Configured || d.Local != d.Remote
+}
+
+// Unified returns the unified diff from the on-chain code to the local code.
+func (d *ContractDiff) Unified() (string, error) {
+	source := d.Source
+	if source == "" {
+		source = "/dev/null"
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(d.Remote),
+		B:        splitLines(d.Local),
+		FromFile: fmt.Sprintf("0x%s/%s", d.Address, d.Name),
+		ToFile:   source,
+		Context:  3,
+	})
+}
+
+func splitLines(code string) []string {
+	if code == "" {
+		return nil
+	}
+	return difflib.SplitLines(code)
+}
+
+// Diff compares the contracts resolved for deployment on the network to the code deployed on-chain.
+//
+// Contracts deployed to the deployment accounts but missing from the deployment config are included
+// in the result, after the configured contracts.
+func (p *Project) Diff(ctx context.Context, network string) ([]*ContractDiff, error) {
+	if p.state == nil {
+		return nil, config.ErrDoesNotExist
+	}
+
+	orderedContracts, err := p.deploymentContracts(network)
+	if err != nil {
+		return nil, err
+	}
+
+	var addresses []flow.Address
+	accounts := make(map[flow.Address]*flow.Account)
+	accountNames := make(map[flow.Address]string)
+	configured := make(map[flow.Address]map[string]bool)
+
+	diffs := make([]*ContractDiff, 0, len(orderedContracts))
+	for _, contract := range orderedContracts {
+		address := contract.Target()
+		account, ok := accounts[address]
+		if !ok {
+			account, err = p.gateway.GetAccount(ctx, address)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch information for account %s with error %s", address, err.Error())
+			}
+			addresses = append(addresses, address)
+			accounts[address] = account
+			accountNames[address] = contract.AccountName()
+			configured[address] = make(map[string]bool)
+		}
+		configured[address][contract.Name()] = true
+
+		code, deployed := account.Contracts[contract.Name()]
+		diffs = append(diffs, &ContractDiff{
+			Name:       contract.Name(),
+			Account:    contract.AccountName(),
+			Address:    address,
+			Source:     contract.Source(),
+			Local:      contract.TranspiledCode(),
+			Remote:     string(code),
+			Deployed:   deployed,
+			Configured: true,
+		})
+	}
+
+	for _, address := range addresses {
+		names := make([]string, 0, len(accounts[address].Contracts))
+		for name := range accounts[address].Contracts {
+			if !configured[address][name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			diffs = append(diffs, &ContractDiff{
+				Name:     name,
+				Account:  accountNames[address],
+				Address:  address,
+				Remote:   string(accounts[address].Contracts[name]),
+				Deployed: true,
+			})
+		}
+	}
+
+	return diffs, nil
+}
+
+// Pull downloads the contract deployed to the address on the network and the contracts it imports
+// from addresses, recursively, writes their code to the directory and adds them to the state
+// as aliases for the network.
+//
+// The code hashes of the pulled contracts are recorded in the lock at the lock path. If a contract
+// changed on-chain since it was locked nothing is pulled, unless update is set.
+// Imported contracts already configured as aliases of their address for the network are not pulled,
+// and contracts already configured with another source are only added as aliases, keeping their source.
+func (p *Project) Pull(
+	ctx context.Context,
+	network string,
+	address flow.Address,
+	name string,
+	dir string,
+	lockPath string,
+	update bool,
+) ([]flowkit.LockedContract, error) {
+	if p.state == nil {
+		return nil, config.ErrDoesNotExist
+	}
+
+	lock := &flowkit.ContractsLock{}
+	data, err := p.state.ReadFile(lockPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		lock, err = flowkit.ParseContractsLock(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	accounts := make(map[flow.Address]*flow.Account)
+	getAccount := func(address flow.Address) (*flow.Account, error) {
+		if account, ok := accounts[address]; ok {
+			return account, nil
+		}
+		account, err := p.gateway.GetAccount(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch information for account %s with error %s", address, err.Error())
+		}
+		accounts[address] = account
+		return account, nil
+	}
+
+	type location struct {
+		address flow.Address
+		name    string
+	}
+
+	var pulled []flowkit.LockedContract
+	var aliased []config.Contract
+	var updated []string
+	codes := make(map[string][]byte)
+	pulledAddresses := make(map[string]flow.Address)
+
+	queue := []location{{address: address, name: name}}
+	for len(queue) > 0 {
+		contract := queue[0]
+		queue = queue[1:]
+
+		// contract names are unique in the configuration, so a name can only be pulled from one address
+		if pulledAddress, ok := pulledAddresses[contract.name]; ok {
+			if pulledAddress != contract.address {
+				return nil, fmt.Errorf(
+					"contract %s is imported from both 0x%s and 0x%s",
+					contract.name,
+					pulledAddress,
+					contract.address,
+				)
+			}
+			continue
+		}
+
+		source := path.Join(dir, fmt.Sprintf("%s.cdc", contract.name))
+		locked := lock.ByAddress(network, contract.address, contract.name)
+
+		configured, configuredSource, err := p.configuredAlias(network, contract.address, contract.name)
+		if err != nil {
+			return nil, err
+		}
+		if configured && locked == nil {
+			continue
+		}
+
+		// a configuration can only have one source per contract name, so the existing source is aliased
+		if configuredSource != "" && path.Clean(configuredSource) != path.Clean(source) {
+			pulledAddresses[contract.name] = contract.address
+			aliased = append(aliased, config.Contract{
+				Name:    contract.name,
+				Source:  configuredSource,
+				Network: network,
+				Alias:   contract.address.Hex(),
+			})
+			continue
+		}
+
+		account, err := getAccount(contract.address)
+		if err != nil {
+			return nil, err
+		}
+
+		code, ok := account.Contracts[contract.name]
+		if !ok {
+			return nil, fmt.Errorf("contract %s is not deployed to 0x%s", contract.name, contract.address)
+		}
+
+		if locked != nil && !locked.Matches(code) {
+			updated = append(updated, fmt.Sprintf("%s (0x%s)", contract.name, contract.address))
+		}
+
+		pulledAddresses[contract.name] = contract.address
+		codes[contract.name] = code
+		pulled = append(pulled, flowkit.NewLockedContract(contract.name, network, contract.address, source, code))
+
+		imports, err := addressImports(code)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse contract %s: %w", contract.name, err)
+		}
+
+		for _, imp := range imports {
+			names := imp.names
+			// importing an address without identifiers imports all of its contracts
+			if len(names) == 0 {
+				importedAccount, err := getAccount(imp.address)
+				if err != nil {
+					return nil, err
+				}
+				for importedName := range importedAccount.Contracts {
+					names = append(names, importedName)
+				}
+				sort.Strings(names)
+			}
+
+			for _, importedName := range names {
+				queue = append(queue, location{address: imp.address, name: importedName})
+			}
+		}
+	}
+
+	if len(updated) > 0 && !update {
+		return nil, fmt.Errorf(
+			"contracts changed on-chain since they were pulled: %s. Use the --update flag to pull the changes",
+			strings.Join(updated, ", "),
+		)
+	}
+
+	for _, contract := range pulled {
+		err := p.state.ReaderWriter().WriteFile(contract.Source, codes[contract.Name], 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write contract %s: %w", contract.Name, err)
+		}
+
+		addContractAlias(p.state.Contracts(), config.Contract{
+			Name:    contract.Name,
+			Source:  contract.Source,
+			Network: network,
+			Alias:   contract.Address,
+		})
+		lock.AddOrUpdate(contract)
+	}
+
+	for _, contract := range aliased {
+		addContractAlias(p.state.Contracts(), contract)
+	}
+
+	data, err = lock.MarshalIndent()
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.state.ReaderWriter().WriteFile(lockPath, data, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save contracts lock: %w", err)
+	}
+
+	return pulled, nil
+}
+
+// configuredAlias checks if the contract is configured as an alias of the address for the network,
+// and returns an error if it's configured as an alias of another address for the network.
+// If it isn't an alias for the network, the source the contract name is configured with is returned, if any.
+func (p *Project) configuredAlias(network string, address flow.Address, name string) (bool, string, error) {
+	source := ""
+	for _, contract := range *p.state.Contracts() {
+		if contract.Name != name {
+			continue
+		}
+
+		if contract.Network == network && contract.IsAlias() {
+			if flow.HexToAddress(contract.Alias) != address {
+				return false, "", fmt.Errorf(
+					"contract %s is already configured as an alias of 0x%s for network %s",
+					name,
+					flow.HexToAddress(contract.Alias),
+					network,
+				)
+			}
+			return true, "", nil
+		}
+
+		if source == "" {
+			source = contract.Source
+		}
+	}
+
+	return false, source, nil
+}
+
+// addContractAlias adds the contract alias for its network, replacing the contract
+// if it's configured without aliases, since a contract can't be configured both ways.
+func addContractAlias(contracts *config.Contracts, contract config.Contract) {
+	for i, existing := range *contracts {
+		if existing.Name == contract.Name && existing.Network == "" {
+			(*contracts)[i] = contract
+			return
+		}
+	}
+
+	contracts.AddOrUpdate(contract.Name, contract)
+}
+
+type addressImport struct {
+	address flow.Address
+	names   []string
+}
+
+// addressImports returns the imports of the code from addresses.
+func addressImports(code []byte) ([]addressImport, error) {
+	program, err := parser.ParseProgram(code, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var imports []addressImport
+	for _, imp := range program.ImportDeclarations() {
+		location, ok := imp.Location.(common.AddressLocation)
+		if !ok {
+			continue
+		}
+
+		var names []string
+		for _, identifier := range imp.Identifiers {
+			names = append(names, identifier.Identifier)
+		}
+
+		imports = append(imports, addressImport{
+			address: flow.BytesToAddress(location.Address.Bytes()),
+			names:   names,
+		})
+	}
+
+	return imports, nil
+}
+
+// deploymentContracts resolves the imports of all the contracts deployed on the network
+// and returns them sorted in the deployment order.
+func (p *Project) deploymentContracts(network string) ([]*contracts.Contract, error) {
+	// create new processor for contract
+	processor := contracts.NewPreprocessor(
+		contracts.FilesystemLoader{
+			Reader: p.state.ReaderWriter(),
+		},
+		p.state.AliasesForNetwork(network),
+	)
+
+	// add all contracts needed to deploy to processor
+	contractsNetwork, err := p.state.DeploymentContractsByNetwork(network)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, contract := range contractsNetwork {
+		err := processor.AddContractSource(
+			contract.Name,
+			contract.Source,
+			contract.AccountAddress,
+			contract.AccountName,
+			contract.Args,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// resolve imports assigns accounts to imports
+	err = processor.ResolveImports()
+	if err != nil {
+		return nil, err
+	}
+
+	// sort correct deployment order of contracts so we don't have import that is not yet deployed
+	return processor.ContractDeploymentOrder()
+}
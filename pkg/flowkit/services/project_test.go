@@ -19,6 +19,7 @@
 package services
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/onflow/flow-go-sdk/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
 	"github.com/onflow/flow-cli/pkg/flowkit/config"
@@ -95,19 +97,19 @@ func TestProject(t *testing.T) {
 		state.Deployments().AddOrUpdate(d)
 
 		gw.SendSignedTransaction.Run(func(args mock.Arguments) {
-			tx := args.Get(0).(*flowkit.Transaction)
+			tx := args.Get(1).(*flowkit.Transaction)
 			assert.Equal(t, tx.FlowTransaction().Payer, a.Address())
 			assert.True(t, strings.Contains(string(tx.FlowTransaction().Script), "signer.contracts.add"))
 
 			gw.SendSignedTransaction.Return(tests.NewTransaction(), nil)
 		})
 
-		contracts, err := s.Project.Deploy("emulator", false)
+		contracts, err := s.Project.Deploy(ctx, "emulator", false, flowkit.MaxGasLimit, DefaultGasMargin)
 
 		assert.NoError(t, err)
 		assert.Equal(t, len(contracts), 1)
-		gw.Mock.AssertCalled(t, tests.GetLatestBlockFunc)
-		gw.Mock.AssertCalled(t, tests.GetAccountFunc, a.Address())
+		gw.Mock.AssertCalled(t, tests.GetLatestBlockFunc, mock.Anything)
+		gw.Mock.AssertCalled(t, tests.GetAccountFunc, mock.Anything, a.Address())
 		gw.Mock.AssertNumberOfCalls(t, tests.GetTransactionResultFunc, 1)
 	})
 
@@ -146,14 +148,14 @@ func TestProject(t *testing.T) {
 		state.Deployments().AddOrUpdate(d)
 
 		gw.SendSignedTransaction.Run(func(args mock.Arguments) {
-			tx := args.Get(0).(*flowkit.Transaction)
+			tx := args.Get(1).(*flowkit.Transaction)
 			assert.Equal(t, tx.FlowTransaction().Payer, acct2.Address())
 			assert.True(t, strings.Contains(string(tx.FlowTransaction().Script), "signer.contracts.add"))
 
 			gw.SendSignedTransaction.Return(tests.NewTransaction(), nil)
 		})
 
-		contracts, err := s.Project.Deploy("emulator", false)
+		contracts, err := s.Project.Deploy(ctx, "emulator", false, flowkit.MaxGasLimit, DefaultGasMargin)
 
 		assert.NoError(t, err)
 		assert.Equal(t, len(contracts), 1)
@@ -189,7 +191,7 @@ func simpleDeploy(state *flowkit.State, s *Services, update bool) ([]*contracts.
 	}
 	state.Deployments().AddOrUpdate(d)
 
-	return s.Project.Deploy(n.Name, update)
+	return s.Project.Deploy(ctx, n.Name, update, flowkit.MaxGasLimit, DefaultGasMargin)
 }
 
 func TestProject_Integration(t *testing.T) {
@@ -255,7 +257,7 @@ func TestProject_Integration(t *testing.T) {
 		}
 		state.Deployments().AddOrUpdate(d)
 
-		contracts, err := s.Project.Deploy(n.Name, false)
+		contracts, err := s.Project.Deploy(ctx, n.Name, false, flowkit.MaxGasLimit, DefaultGasMargin)
 		assert.NoError(t, err)
 		assert.Len(t, contracts, 3)
 		assert.Equal(t, contracts[0].Name(), tests.ContractA.Name)
@@ -290,4 +292,233 @@ func TestProject_Integration(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("Diff Project", func(t *testing.T) {
+		t.Parallel()
+
+		state, s := setupIntegration()
+		setupAccounts(state, s)
+		alice, _ := state.Accounts().ByName(tests.Alice().Name())
+
+		hello := config.Contract{
+			Name:    tests.ContractHelloString.Name,
+			Source:  tests.ContractHelloString.Filename,
+			Network: "emulator",
+		}
+		state.Contracts().AddOrUpdate(hello.Name, hello)
+		state.Deployments().AddOrUpdate(config.Deployment{
+			Network:   "emulator",
+			Account:   alice.Name(),
+			Contracts: []config.ContractDeployment{{Name: hello.Name}},
+		})
+
+		_, err := s.Project.Deploy(ctx, "emulator", false, flowkit.MaxGasLimit, DefaultGasMargin)
+		require.NoError(t, err)
+
+		// deployed contract missing from the deployment config
+		_, err = s.Accounts.AddContractWithContext(ctx, alice, resourceToContract(tests.ContractSimple), false)
+		require.NoError(t, err)
+
+		diffs, err := s.Project.Diff(ctx, "emulator")
+		require.NoError(t, err)
+		require.Len(t, diffs, 2)
+		assert.Equal(t, tests.ContractHelloString.Name, diffs[0].Name)
+		assert.False(t, diffs[0].Changed())
+		assert.Equal(t, tests.ContractSimple.Name, diffs[1].Name)
+		assert.Equal(t, alice.Address(), diffs[1].Address)
+		assert.False(t, diffs[1].Configured)
+		assert.True(t, diffs[1].Changed())
+
+		c := config.Contract{
+			Name:    tests.ContractSimpleUpdated.Name,
+			Source:  tests.ContractSimpleUpdated.Filename,
+			Network: "emulator",
+		}
+		state.Contracts().AddOrUpdate(c.Name, c)
+		state.Deployments().AddOrUpdate(config.Deployment{
+			Network: "emulator",
+			Account: alice.Name(),
+			Contracts: []config.ContractDeployment{
+				{Name: hello.Name},
+				{Name: c.Name},
+			},
+		})
+
+		diffs, err = s.Project.Diff(ctx, "emulator")
+		require.NoError(t, err)
+		require.Len(t, diffs, 2)
+		assert.False(t, diffs[0].Changed())
+		assert.True(t, diffs[1].Configured)
+		assert.True(t, diffs[1].Changed())
+
+		unified, err := diffs[1].Unified()
+		require.NoError(t, err)
+		assert.Contains(t, unified, "--- 0x"+alice.Address().String()+"/Simple\n")
+		assert.Contains(t, unified, "+++ "+tests.ContractSimpleUpdated.Filename+"\n")
+		assert.Contains(t, unified, "+\t\t\tpub fun newFunc() {}\n")
+	})
+
+	t.Run("Diff Project Not Deployed", func(t *testing.T) {
+		t.Parallel()
+
+		state, s := setupIntegration()
+		setupAccounts(state, s)
+		alice, _ := state.Accounts().ByName(tests.Alice().Name())
+
+		c := config.Contract{
+			Name:    tests.ContractSimple.Name,
+			Source:  tests.ContractSimple.Filename,
+			Network: "emulator",
+		}
+		state.Contracts().AddOrUpdate(c.Name, c)
+		state.Deployments().AddOrUpdate(config.Deployment{
+			Network:   "emulator",
+			Account:   alice.Name(),
+			Contracts: []config.ContractDeployment{{Name: c.Name}},
+		})
+
+		diffs, err := s.Project.Diff(ctx, "emulator")
+		require.NoError(t, err)
+		require.Len(t, diffs, 1)
+		assert.False(t, diffs[0].Deployed)
+		assert.True(t, diffs[0].Changed())
+
+		unified, err := diffs[0].Unified()
+		require.NoError(t, err)
+		assert.Contains(t, unified, "+\t\tpub contract Simple {}\n")
+	})
+
+	t.Run("Pull Contracts", func(t *testing.T) {
+		t.Parallel()
+
+		state, s := setupIntegration()
+		setupAccounts(state, s)
+		alice, _ := state.Accounts().ByName(tests.Alice().Name())
+		bob, _ := state.Accounts().ByName(tests.Bob().Name())
+
+		_, err := s.Accounts.AddContractWithContext(ctx, bob, resourceToContract(tests.ContractSimple), false)
+		require.NoError(t, err)
+
+		importer := &Contract{
+			Name: "Importer",
+			Source: []byte(fmt.Sprintf(`
+				import Simple from 0x%s
+				pub contract Importer {}
+			`, bob.Address())),
+		}
+		_, err = s.Accounts.AddContractWithContext(ctx, alice, importer, false)
+		require.NoError(t, err)
+
+		pulled, err := s.Project.Pull(ctx, "emulator", alice.Address(), "Importer", "imports", "flow.lock.json", false)
+		require.NoError(t, err)
+		require.Len(t, pulled, 2)
+		assert.Equal(t, "Importer", pulled[0].Name)
+		assert.Equal(t, "imports/Importer.cdc", pulled[0].Source)
+		assert.Equal(t, "Simple", pulled[1].Name)
+		assert.Equal(t, bob.Address().Hex(), pulled[1].Address)
+
+		code, err := state.ReadFile("imports/Simple.cdc")
+		require.NoError(t, err)
+		assert.Equal(t, tests.ContractSimple.Source, code)
+
+		simple, err := state.Contracts().ByNameAndNetwork("Simple", "emulator")
+		require.NoError(t, err)
+		assert.Equal(t, "imports/Simple.cdc", simple.Source)
+		assert.Equal(t, bob.Address().Hex(), simple.Alias)
+
+		data, err := state.ReadFile("flow.lock.json")
+		require.NoError(t, err)
+		lock, err := flowkit.ParseContractsLock(data)
+		require.NoError(t, err)
+		assert.Len(t, lock.Contracts, 2)
+
+		// pulling again doesn't change the lock
+		_, err = s.Project.Pull(ctx, "emulator", alice.Address(), "Importer", "imports", "flow.lock.json", false)
+		require.NoError(t, err)
+		pulledData, err := state.ReadFile("flow.lock.json")
+		require.NoError(t, err)
+		assert.Equal(t, data, pulledData)
+
+		_, err = s.Accounts.AddContractWithContext(ctx, bob, resourceToContract(tests.ContractSimpleUpdated), true)
+		require.NoError(t, err)
+
+		_, err = s.Project.Pull(ctx, "emulator", alice.Address(), "Importer", "imports", "flow.lock.json", false)
+		assert.ErrorContains(t, err, fmt.Sprintf("contracts changed on-chain since they were pulled: Simple (0x%s)", bob.Address()))
+
+		_, err = s.Project.Pull(ctx, "emulator", alice.Address(), "Importer", "imports", "flow.lock.json", true)
+		require.NoError(t, err)
+
+		code, err = state.ReadFile("imports/Simple.cdc")
+		require.NoError(t, err)
+		assert.Equal(t, tests.ContractSimpleUpdated.Source, code)
+	})
+
+	t.Run("Pull Contracts Configured", func(t *testing.T) {
+		t.Parallel()
+
+		state, s := setupIntegration()
+		setupAccounts(state, s)
+		bob, _ := state.Accounts().ByName(tests.Bob().Name())
+
+		_, err := s.Accounts.AddContractWithContext(ctx, bob, resourceToContract(tests.ContractSimple), false)
+		require.NoError(t, err)
+
+		state.Contracts().AddOrUpdate("Simple", config.Contract{
+			Name:   "Simple",
+			Source: tests.ContractSimple.Filename,
+		})
+
+		pulled, err := s.Project.Pull(ctx, "emulator", bob.Address(), "Simple", "imports", "flow.lock.json", false)
+		require.NoError(t, err)
+		assert.Empty(t, pulled)
+
+		// the configured source is aliased instead of writing another source
+		_, err = state.ReadFile("imports/Simple.cdc")
+		assert.Error(t, err)
+
+		simple, err := state.Contracts().ByNameAndNetwork("Simple", "emulator")
+		require.NoError(t, err)
+		assert.Equal(t, tests.ContractSimple.Filename, simple.Source)
+		assert.Equal(t, bob.Address().Hex(), simple.Alias)
+		assert.Len(t, *state.Contracts(), 1)
+	})
+
+	t.Run("Pull Contracts Aliased On Multiple Networks", func(t *testing.T) {
+		t.Parallel()
+
+		state, s := setupIntegration()
+		setupAccounts(state, s)
+		alice, _ := state.Accounts().ByName(tests.Alice().Name())
+		bob, _ := state.Accounts().ByName(tests.Bob().Name())
+
+		_, err := s.Accounts.AddContractWithContext(ctx, bob, resourceToContract(tests.ContractSimple), false)
+		require.NoError(t, err)
+
+		importer := &Contract{
+			Name: "Importer",
+			Source: []byte(fmt.Sprintf(`
+				import Simple from 0x%s
+				pub contract Importer {}
+			`, bob.Address())),
+		}
+		_, err = s.Accounts.AddContractWithContext(ctx, alice, importer, false)
+		require.NoError(t, err)
+
+		for network, alias := range map[string]string{"emulator": bob.Address().Hex(), "testnet": "9a0766d93b6608b7"} {
+			state.Contracts().AddOrUpdate("Simple", config.Contract{
+				Name:    "Simple",
+				Source:  "./cadence/Simple.cdc",
+				Network: network,
+				Alias:   alias,
+			})
+		}
+
+		pulled, err := s.Project.Pull(ctx, "emulator", alice.Address(), "Importer", "imports", "flow.lock.json", false)
+		require.NoError(t, err)
+		require.Len(t, pulled, 1)
+		assert.Equal(t, "Importer", pulled[0].Name)
+
+		_, err = state.ReadFile("imports/Simple.cdc")
+		assert.Error(t, err)
+	})
+
 }
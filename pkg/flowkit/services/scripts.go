@@ -19,6 +19,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
@@ -26,6 +27,7 @@ import (
 	"github.com/onflow/flow-cli/pkg/flowkit/config"
 
 	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
 
 	"github.com/onflow/flow-cli/pkg/flowkit/contracts"
 	"github.com/onflow/flow-cli/pkg/flowkit/gateway"
@@ -53,7 +55,58 @@ func NewScripts(
 }
 
 // Execute script code with passed arguments on the selected network.
+//
+// Deprecated: use ExecuteWithContext, Execute is kept for callers without a context and can't be cancelled.
 func (s *Scripts) Execute(code []byte, args []cadence.Value, scriptPath string, network string) (cadence.Value, error) {
+	return s.ExecuteWithContext(context.Background(), code, args, scriptPath, network)
+}
+
+// ExecuteWithContext executes script code with passed arguments on the selected network.
+func (s *Scripts) ExecuteWithContext(ctx context.Context, code []byte, args []cadence.Value, scriptPath string, network string) (cadence.Value, error) {
+	code, err := s.resolveImports(code, scriptPath, network)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.gateway.ExecuteScript(ctx, code, args)
+}
+
+// ExecuteAtBlockHeight executes script code with passed arguments against the state at the block height.
+func (s *Scripts) ExecuteAtBlockHeight(
+	ctx context.Context,
+	code []byte,
+	args []cadence.Value,
+	scriptPath string,
+	network string,
+	height uint64,
+) (cadence.Value, error) {
+	code, err := s.resolveImports(code, scriptPath, network)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.gateway.ExecuteScriptAtBlockHeight(ctx, code, args, height)
+}
+
+// ExecuteAtBlockID executes script code with passed arguments against the state at the block ID.
+func (s *Scripts) ExecuteAtBlockID(
+	ctx context.Context,
+	code []byte,
+	args []cadence.Value,
+	scriptPath string,
+	network string,
+	id flow.Identifier,
+) (cadence.Value, error) {
+	code, err := s.resolveImports(code, scriptPath, network)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.gateway.ExecuteScriptAtBlockID(ctx, code, args, id)
+}
+
+// resolveImports replaces file imports in the script code with addresses of contracts deployed to the network.
+func (s *Scripts) resolveImports(code []byte, scriptPath string, network string) ([]byte, error) {
 	resolver, err := contracts.NewResolver(code)
 	if err != nil {
 		return nil, err
@@ -85,5 +138,5 @@ func (s *Scripts) Execute(code []byte, args []cadence.Value, scriptPath string,
 		}
 	}
 
-	return s.gateway.ExecuteScript(code, args)
+	return code, nil
 }
@@ -22,11 +22,15 @@ import (
 	"testing"
 
 	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-cli/pkg/flowkit"
 	"github.com/onflow/flow-cli/pkg/flowkit/config"
 	"github.com/onflow/flow-cli/pkg/flowkit/tests"
+	"github.com/onflow/flow-cli/pkg/flowkit/util"
 )
 
 func TestScripts(t *testing.T) {
@@ -36,17 +40,38 @@ func TestScripts(t *testing.T) {
 		_, s, gw := setup()
 
 		gw.ExecuteScript.Run(func(args mock.Arguments) {
-			assert.Len(t, string(args.Get(0).([]byte)), 78)
-			assert.Equal(t, "\"Foo\"", args.Get(1).([]cadence.Value)[0].String())
+			assert.Len(t, string(args.Get(1).([]byte)), 78)
+			assert.Equal(t, "\"Foo\"", args.Get(2).([]cadence.Value)[0].String())
 			gw.ExecuteScript.Return(cadence.MustConvertValue(""), nil)
 		})
 
 		args := []cadence.Value{cadence.String("Foo")}
-		_, err := s.Scripts.Execute(tests.ScriptArgString.Source, args, "", "")
+		_, err := s.Scripts.ExecuteWithContext(ctx, tests.ScriptArgString.Source, args, "", "")
 
 		assert.NoError(t, err)
 	})
 
+	t.Run("Execute Script at Block Height", func(t *testing.T) {
+		_, s, gw := setup()
+
+		args := []cadence.Value{cadence.String("Foo")}
+		_, err := s.Scripts.ExecuteAtBlockHeight(ctx, tests.ScriptArgString.Source, args, "", "", 10)
+
+		assert.NoError(t, err)
+		gw.Mock.AssertCalled(t, tests.ExecuteScriptAtBlockHeightFunc, mock.Anything, tests.ScriptArgString.Source, args, uint64(10))
+	})
+
+	t.Run("Execute Script at Block ID", func(t *testing.T) {
+		_, s, gw := setup()
+
+		blockID := flow.HexToID("a310685082f0b09f2a148b2e8905f08ea458ed873596b53b200699e8e1f6536f")
+		args := []cadence.Value{cadence.String("Foo")}
+		_, err := s.Scripts.ExecuteAtBlockID(ctx, tests.ScriptArgString.Source, args, "", "", blockID)
+
+		assert.NoError(t, err)
+		gw.Mock.AssertCalled(t, tests.ExecuteScriptAtBlockIDFunc, mock.Anything, tests.ScriptArgString.Source, args, blockID)
+	})
+
 }
 
 func TestScripts_Integration(t *testing.T) {
@@ -57,7 +82,18 @@ func TestScripts_Integration(t *testing.T) {
 		_, s := setupIntegration()
 
 		args := []cadence.Value{cadence.String("Foo")}
-		res, err := s.Scripts.Execute(tests.ScriptArgString.Source, args, "", "")
+		res, err := s.Scripts.ExecuteWithContext(ctx, tests.ScriptArgString.Source, args, "", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "\"Hello Foo\"", res.String())
+	})
+
+	t.Run("Execute at Block Height", func(t *testing.T) {
+		t.Parallel()
+		_, s := setupIntegration()
+
+		args := []cadence.Value{cadence.String("Foo")}
+		res, err := s.Scripts.ExecuteAtBlockHeight(ctx, tests.ScriptArgString.Source, args, "", "", 0)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "\"Hello Foo\"", res.String())
@@ -67,7 +103,7 @@ func TestScripts_Integration(t *testing.T) {
 		t.Parallel()
 		_, s := setupIntegration()
 		args := []cadence.Value{cadence.String("Foo")}
-		res, err := s.Scripts.Execute(tests.ScriptWithError.Source, args, "", "")
+		res, err := s.Scripts.ExecuteWithContext(ctx, tests.ScriptWithError.Source, args, "", "")
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "cannot find type in this scope")
@@ -75,6 +111,55 @@ func TestScripts_Integration(t *testing.T) {
 
 	})
 
+	t.Run("Execute Account Storage Script", func(t *testing.T) {
+		t.Parallel()
+		state, s := setupIntegration()
+		srvAcc, _ := state.EmulatorServiceAccount()
+
+		script := flowkit.NewAccountStorageScript(util.EnvFromNetwork(flow.Emulator))
+		args := []cadence.Value{cadence.NewAddress(srvAcc.Address())}
+		res, err := s.Scripts.ExecuteWithContext(ctx, script, args, "", "")
+		require.NoError(t, err)
+
+		storage, err := flowkit.NewAccountStorageFromValue(res)
+		require.NoError(t, err)
+		assert.NotZero(t, storage.Used)
+		assert.Greater(t, storage.Capacity, storage.Used)
+
+		vaults := storage.Vaults()
+		require.Len(t, vaults, 1)
+		assert.Equal(t, "/storage/flowTokenVault", vaults[0].Path)
+		assert.Equal(t, "A.0ae53cb6e3f42a79.FlowToken.Vault", vaults[0].Type)
+		assert.NotZero(t, *vaults[0].Balance)
+
+		var receiver *flowkit.AccountStorageItem
+		for i, item := range storage.Items {
+			if item.Path == "/public/flowTokenReceiver" {
+				receiver = &storage.Items[i]
+			}
+		}
+		require.NotNil(t, receiver)
+		assert.Equal(t, flowkit.StorageDomainPublic, receiver.Domain)
+		assert.Equal(t, "/storage/flowTokenVault", receiver.Target)
+	})
+
+	t.Run("Parse Invalid Account Storage", func(t *testing.T) {
+		t.Parallel()
+
+		value := cadence.NewStruct([]cadence.Value{cadence.NewUInt64(1)})
+		_, err := flowkit.NewAccountStorageFromValue(value)
+		assert.EqualError(t, err, "account storage struct must have a type")
+
+		_, err = flowkit.NewAccountStorageFromValue(value.WithType(&cadence.StructType{
+			QualifiedIdentifier: "AccountStorage",
+			Fields: []cadence.Field{
+				{Identifier: "used", Type: cadence.UInt64Type{}},
+				{Identifier: "capacity", Type: cadence.UInt64Type{}},
+			},
+		}))
+		assert.EqualError(t, err, "account storage struct has 1 fields, its type AccountStorage has 2 fields")
+	})
+
 	t.Run("Execute With Imports", func(t *testing.T) {
 		t.Parallel()
 		state, s := setupIntegration()
@@ -103,13 +188,14 @@ func TestScripts_Integration(t *testing.T) {
 			}},
 		}
 		state.Deployments().AddOrUpdate(d)
-		_, _ = s.Accounts.AddContract(
+		_, _ = s.Accounts.AddContractWithContext(
+			ctx,
 			srvAcc,
 			resourceToContract(tests.ContractHelloString),
 			false,
 		)
 
-		res, err := s.Scripts.Execute(tests.ScriptImport.Source, nil, tests.ScriptImport.Filename, n.Name)
+		res, err := s.Scripts.ExecuteWithContext(ctx, tests.ScriptImport.Source, nil, tests.ScriptImport.Filename, n.Name)
 		assert.NoError(t, err)
 		assert.Equal(t, res.String(), "\"Hello Hello, World!\"")
 	})
@@ -130,7 +216,7 @@ func TestScripts_Integration(t *testing.T) {
 		}
 
 		for x, i := range in {
-			_, err := s.Scripts.Execute(tests.ScriptImport.Source, nil, i[0], i[1])
+			_, err := s.Scripts.ExecuteWithContext(ctx, tests.ScriptImport.Source, nil, i[0], i[1])
 			assert.NotNil(t, err)
 			assert.Equal(t, err.Error(), out[x])
 		}
@@ -38,6 +38,10 @@ type Services struct {
 	Status       *Status
 	Snapshot     *Snapshot
 	Tests        *Tests
+
+	// Logger is the logger the services report to, commands use it to report warnings
+	// so they respect the log level and aren't mixed into a formatted output.
+	Logger output.Logger
 }
 
 // NewServices returns a new services collection for a state,
@@ -59,5 +63,6 @@ func NewServices(
 		Status:       NewStatus(gateway, state, logger),
 		Snapshot:     NewSnapshot(gateway, state, logger),
 		Tests:        NewTests(state, logger),
+		Logger:       logger,
 	}
 }
@@ -0,0 +1,588 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/gateway"
+	"github.com/onflow/flow-cli/pkg/flowkit/output"
+)
+
+// SigningServerPath is the path the signing server hosts the payloads under.
+const SigningServerPath = "/payloads"
+
+// maxSigningPayloadSize is the maximum size of a posted payload, the hex encoded
+// RLP of the largest transaction accepted by the network fits in it.
+const maxSigningPayloadSize = 4 << 20
+
+var (
+	errPayloadNotFound    = errors.New("payload not found")
+	errSignaturesNotFound = errors.New("signatures not found")
+	errSignConflict       = errors.New("signatures conflict with the hosted payload")
+	errPayloadUnsigned    = errors.New("payload is not fully signed")
+)
+
+// SigningServer hosts transaction payloads so multiple co-signers can sign them over HTTP.
+//
+// Each payload is available at /payloads/{id}, which is compatible with the
+// sign command --from-remote-url flag: a GET returns the hex encoded RLP payload
+// and a POST of the signed RLP payload adds its signatures to the hosted payload.
+// The signatures are merged, so co-signers can sign the same payload at the same time,
+// only the payer envelope signature must be added once all the payload signatures are collected.
+//
+// The signatures are verified against the account keys fetched through the gateway before they are collected,
+// a new signature made with the same account key replaces the collected one.
+type SigningServer struct {
+	gateway  gateway.Gateway
+	accounts *flowkit.Accounts
+	logger   output.Logger
+	mu       sync.Mutex
+	ids      []string
+	payloads map[string]*flow.Transaction
+}
+
+// SigningPayload is a decoded preview of a payload hosted by the signing server.
+type SigningPayload struct {
+	ID             string                     `json:"id"`
+	Complete       bool                       `json:"complete"`
+	MissingSigners []string                   `json:"missingSigners"`
+	Transaction    *flowkit.TransactionBundle `json:"transaction,omitempty"`
+}
+
+// NewSigningServer returns a new signing server verifying the signatures with the account keys fetched from the gateway,
+// the account names in the previews are resolved from the state if provided.
+func NewSigningServer(gateway gateway.Gateway, state *flowkit.State, logger output.Logger) *SigningServer {
+	var accounts *flowkit.Accounts
+	if state != nil {
+		accounts = state.Accounts()
+	}
+
+	return &SigningServer{
+		gateway:  gateway,
+		accounts: accounts,
+		logger:   logger,
+		payloads: make(map[string]*flow.Transaction),
+	}
+}
+
+// Add hosts the transaction payload and returns its ID.
+//
+// The ID doesn't depend on the signatures, so adding a payload which is already hosted merges its signatures.
+func (s *SigningServer) Add(ctx context.Context, tx *flowkit.Transaction) (string, error) {
+	id := signingPayloadID(tx.FlowTransaction())
+
+	s.mu.Lock()
+	_, exists := s.payloads[id]
+	if !exists {
+		unsigned := *tx.FlowTransaction()
+		unsigned.PayloadSignatures = nil
+		unsigned.EnvelopeSignatures = nil
+		s.payloads[id] = &unsigned
+		s.ids = append(s.ids, id)
+	}
+	s.mu.Unlock()
+
+	err := s.Sign(ctx, id, tx.FlowTransaction())
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// Sign adds the signatures of the signed transaction to the hosted payload with the ID.
+//
+// The signed transaction must have the same payload as the hosted one and its new signatures must be valid
+// for the keys of the signing accounts. A signature made with the same account key as a collected one replaces it.
+// Envelope signatures are only accepted if they were made over all the payload signatures collected by the server.
+func (s *SigningServer) Sign(ctx context.Context, id string, signed *flow.Transaction) error {
+	s.mu.Lock()
+	tx, ok := s.payloads[id]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", errPayloadNotFound, id)
+	}
+	newPayloadSigs := newSignatures(tx.PayloadSignatures, signed.PayloadSignatures)
+	newEnvelopeSigs := newSignatures(tx.EnvelopeSignatures, signed.EnvelopeSignatures)
+	s.mu.Unlock()
+
+	if !bytes.Equal(signed.PayloadMessage(), tx.PayloadMessage()) {
+		return fmt.Errorf("signed transaction doesn't match the payload %s", id)
+	}
+
+	roles := signingRoles(tx)
+	for _, sig := range append(newPayloadSigs, newEnvelopeSigs...) {
+		if !roles[sig.Address] {
+			return fmt.Errorf("account %s has no role in the transaction and can't sign it", sig.Address)
+		}
+	}
+
+	// the signatures are verified without holding the lock, since the account keys are fetched from the network
+	accounts := make(map[flow.Address]*flow.Account)
+	err := s.verifySignatures(ctx, accounts, newPayloadSigs, signed.PayloadMessage())
+	if err != nil {
+		return err
+	}
+	err = s.verifySignatures(ctx, accounts, newEnvelopeSigs, signed.EnvelopeMessage())
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the signatures collected meanwhile are compared again, the new signatures can only be fewer
+	newPayloadSigs = newSignatures(tx.PayloadSignatures, signed.PayloadSignatures)
+	newEnvelopeSigs = newSignatures(tx.EnvelopeSignatures, signed.EnvelopeSignatures)
+
+	if len(newPayloadSigs) > 0 && len(tx.EnvelopeSignatures) > 0 {
+		return fmt.Errorf("%w: the envelope is already signed, payload signatures can't be added", errSignConflict)
+	}
+
+	if len(newEnvelopeSigs) > 0 &&
+		(len(newPayloadSigs) > 0 || len(newSignatures(signed.PayloadSignatures, tx.PayloadSignatures)) > 0) {
+		return fmt.Errorf(
+			"%w: the envelope was signed over different payload signatures, get the payload again and sign it",
+			errSignConflict,
+		)
+	}
+
+	for _, sig := range newPayloadSigs {
+		tx.PayloadSignatures = withoutKeySignature(tx.PayloadSignatures, sig)
+		tx.AddPayloadSignature(sig.Address, sig.KeyIndex, sig.Signature)
+		s.logger.Info(fmt.Sprintf("Payload %s signed by account %s with key index %d", id, sig.Address, sig.KeyIndex))
+	}
+	for _, sig := range newEnvelopeSigs {
+		tx.EnvelopeSignatures = withoutKeySignature(tx.EnvelopeSignatures, sig)
+		tx.AddEnvelopeSignature(sig.Address, sig.KeyIndex, sig.Signature)
+		s.logger.Info(fmt.Sprintf("Envelope %s signed by account %s with key index %d", id, sig.Address, sig.KeyIndex))
+	}
+
+	return nil
+}
+
+// RemoveSignatures removes the signatures of the account from the hosted payload with the ID.
+//
+// The envelope signatures are removed too when payload signatures are removed, since they were made over them.
+func (s *SigningServer) RemoveSignatures(id string, address flow.Address) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.payloads[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", errPayloadNotFound, id)
+	}
+
+	payloadSigs := withoutAccountSignatures(tx.PayloadSignatures, address)
+	envelopeSigs := withoutAccountSignatures(tx.EnvelopeSignatures, address)
+	removedPayloadSigs := len(payloadSigs) != len(tx.PayloadSignatures)
+	if !removedPayloadSigs && len(envelopeSigs) == len(tx.EnvelopeSignatures) {
+		return fmt.Errorf("%w: payload %s has no signatures of account %s", errSignaturesNotFound, id, address)
+	}
+
+	if removedPayloadSigs {
+		envelopeSigs = nil
+	}
+
+	tx.PayloadSignatures = payloadSigs
+	tx.EnvelopeSignatures = envelopeSigs
+	s.logger.Info(fmt.Sprintf("Signatures of account %s removed from payload %s", address, id))
+
+	return nil
+}
+
+// verifySignatures verifies the signatures of the message with the keys of the signing accounts,
+// the accounts are fetched once and kept in the provided map.
+func (s *SigningServer) verifySignatures(
+	ctx context.Context,
+	accounts map[flow.Address]*flow.Account,
+	sigs []flow.TransactionSignature,
+	message []byte,
+) error {
+	for _, sig := range sigs {
+		account, ok := accounts[sig.Address]
+		if !ok {
+			var err error
+			account, err = s.gateway.GetAccount(ctx, sig.Address)
+			if err != nil {
+				return fmt.Errorf("failed to get account %s to verify its signature: %w", sig.Address, err)
+			}
+			accounts[sig.Address] = account
+		}
+
+		if sig.KeyIndex < 0 || sig.KeyIndex >= len(account.Keys) {
+			return fmt.Errorf("account %s has no key with index %d", sig.Address, sig.KeyIndex)
+		}
+		key := account.Keys[sig.KeyIndex]
+		if key.Revoked {
+			return fmt.Errorf("key with index %d of account %s is revoked", sig.KeyIndex, sig.Address)
+		}
+
+		hasher, err := crypto.NewHasher(key.HashAlgo)
+		if err != nil {
+			return err
+		}
+
+		valid, err := key.PublicKey.Verify(sig.Signature, append(flow.TransactionDomainTag[:], message...), hasher)
+		if err != nil || !valid {
+			return fmt.Errorf("invalid signature of account %s with key index %d", sig.Address, sig.KeyIndex)
+		}
+	}
+
+	return nil
+}
+
+// Payload returns the hosted payload with the ID and all the signatures collected so far.
+func (s *SigningServer) Payload(id string) (*flowkit.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.payloads[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errPayloadNotFound, id)
+	}
+
+	// the payload is copied through its encoding, so signatures collected later don't change the returned transaction
+	return flowkit.NewTransactionFromPayload([]byte(hex.EncodeToString(tx.Encode())))
+}
+
+// Envelope returns the hosted payload with the ID once it is signed by all the accounts with a role in it.
+func (s *SigningServer) Envelope(id string) (*flowkit.Transaction, error) {
+	tx, err := s.Payload(id)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := missingSigners(tx.FlowTransaction())
+	if len(missing) > 0 {
+		return nil, fmt.Errorf(
+			"%w: payload %s is missing signatures of accounts: %s",
+			errPayloadUnsigned,
+			id,
+			strings.Join(missing, ", "),
+		)
+	}
+
+	return tx, nil
+}
+
+// List returns the previews of all the hosted payloads in the order they were added.
+func (s *SigningServer) List() []*SigningPayload {
+	s.mu.Lock()
+	ids := append([]string(nil), s.ids...)
+	s.mu.Unlock()
+
+	previews := make([]*SigningPayload, 0, len(ids))
+	for _, id := range ids {
+		preview, err := s.Preview(id)
+		if err != nil {
+			continue
+		}
+		previews = append(previews, preview)
+	}
+
+	return previews
+}
+
+// Preview returns the decoded preview of the hosted payload with the ID.
+func (s *SigningServer) Preview(id string) (*SigningPayload, error) {
+	tx, err := s.Payload(id)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := missingSigners(tx.FlowTransaction())
+	preview := &SigningPayload{
+		ID:             id,
+		Complete:       len(missing) == 0,
+		MissingSigners: missing,
+	}
+
+	// payloads which can't be bundled are still hosted, only without the decoded transaction
+	bundle, err := flowkit.NewTransactionBundle(tx, s.accounts)
+	if err == nil {
+		preview.Transaction = bundle
+	}
+
+	return preview, nil
+}
+
+// ServeHTTP serves the signing server endpoints:
+//
+//	GET    /payloads                              list the previews of the hosted payloads
+//	POST   /payloads                              host a new hex encoded RLP payload or transaction bundle
+//	GET    /payloads/{id}                         get the hex encoded RLP payload with the collected signatures
+//	POST   /payloads/{id}                         add the signatures of the posted hex encoded RLP payload
+//	GET    /payloads/{id}/preview                 get the decoded preview of the payload
+//	GET    /payloads/{id}/envelope                get the hex encoded RLP payload once it's fully signed
+//	DELETE /payloads/{id}/signatures/{address}    remove the signatures of the account from the payload
+func (s *SigningServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == SigningServerPath || r.URL.Path == SigningServerPath+"/" {
+		switch r.Method {
+		case http.MethodGet:
+			writeSigningJSON(w, s.List())
+		case http.MethodPost:
+			s.serveAdd(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, SigningServerPath+"/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, SigningServerPath+"/"), "/")
+	id := parts[0]
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		tx, err := s.Payload(id)
+		if err != nil {
+			writeSigningError(w, err)
+			return
+		}
+		writeSigningRLP(w, tx)
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		s.serveSign(w, r, id)
+	case len(parts) == 2 && parts[1] == "preview" && r.Method == http.MethodGet:
+		preview, err := s.Preview(id)
+		if err != nil {
+			writeSigningError(w, err)
+			return
+		}
+		writeSigningJSON(w, preview)
+	case len(parts) == 2 && parts[1] == "envelope" && r.Method == http.MethodGet:
+		tx, err := s.Envelope(id)
+		if err != nil {
+			writeSigningError(w, err)
+			return
+		}
+		writeSigningRLP(w, tx)
+	case len(parts) == 3 && parts[1] == "signatures" && r.Method == http.MethodDelete:
+		err := s.RemoveSignatures(id, flow.HexToAddress(parts[2]))
+		if err != nil {
+			writeSigningError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *SigningServer) serveAdd(w http.ResponseWriter, r *http.Request) {
+	payload, err := readSigningPayload(w, r)
+	if err != nil {
+		return
+	}
+
+	tx, err := flowkit.NewTransactionFromPayload(bytes.TrimSpace(payload))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := s.Add(r.Context(), tx)
+	if err != nil {
+		writeSigningError(w, err)
+		return
+	}
+
+	s.logger.Info(fmt.Sprintf("Payload %s added", id))
+
+	preview, err := s.Preview(id)
+	if err != nil {
+		writeSigningError(w, err)
+		return
+	}
+	writeSigningJSON(w, preview)
+}
+
+func (s *SigningServer) serveSign(w http.ResponseWriter, r *http.Request, id string) {
+	payload, err := readSigningPayload(w, r)
+	if err != nil {
+		return
+	}
+
+	signed, err := flowkit.NewTransactionFromPayload(bytes.TrimSpace(payload))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.Sign(r.Context(), id, signed.FlowTransaction())
+	if err != nil {
+		writeSigningError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// readSigningPayload reads the posted payload and writes the error response if it can't be read or is too large.
+func readSigningPayload(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSigningPayloadSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+func writeSigningRLP(w http.ResponseWriter, tx *flowkit.Transaction) {
+	w.Header().Set("Content-Type", "application/text")
+	_, _ = w.Write([]byte(hex.EncodeToString(tx.FlowTransaction().Encode())))
+}
+
+func writeSigningJSON(w http.ResponseWriter, value any) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+func writeSigningError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, errPayloadNotFound) || errors.Is(err, errSignaturesNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, errSignConflict) || errors.Is(err, errPayloadUnsigned) {
+		status = http.StatusConflict
+	}
+
+	http.Error(w, err.Error(), status)
+}
+
+// signingPayloadID is the ID of the transaction without any signatures, so it doesn't change as the payload is signed.
+func signingPayloadID(tx *flow.Transaction) string {
+	unsigned := *tx
+	unsigned.PayloadSignatures = nil
+	unsigned.EnvelopeSignatures = nil
+	return unsigned.ID().Hex()
+}
+
+// signingRoles returns the addresses of the accounts with a role in the transaction.
+func signingRoles(tx *flow.Transaction) map[flow.Address]bool {
+	roles := map[flow.Address]bool{
+		tx.ProposalKey.Address: true,
+		tx.Payer:               true,
+	}
+	for _, authorizer := range tx.Authorizers {
+		roles[authorizer] = true
+	}
+
+	return roles
+}
+
+// newSignatures returns the signatures which are not in the existing signatures.
+func newSignatures(existing []flow.TransactionSignature, sigs []flow.TransactionSignature) []flow.TransactionSignature {
+	var added []flow.TransactionSignature
+	for _, sig := range sigs {
+		found := false
+		for _, e := range existing {
+			if e.Address == sig.Address && e.KeyIndex == sig.KeyIndex && bytes.Equal(e.Signature, sig.Signature) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			added = append(added, sig)
+		}
+	}
+
+	return added
+}
+
+// withoutKeySignature returns the signatures without the ones made with the same account key as the signature.
+func withoutKeySignature(sigs []flow.TransactionSignature, sig flow.TransactionSignature) []flow.TransactionSignature {
+	kept := make([]flow.TransactionSignature, 0, len(sigs))
+	for _, s := range sigs {
+		if s.Address != sig.Address || s.KeyIndex != sig.KeyIndex {
+			kept = append(kept, s)
+		}
+	}
+
+	return kept
+}
+
+// withoutAccountSignatures returns the signatures without the ones made by the account.
+func withoutAccountSignatures(sigs []flow.TransactionSignature, address flow.Address) []flow.TransactionSignature {
+	kept := make([]flow.TransactionSignature, 0, len(sigs))
+	for _, sig := range sigs {
+		if sig.Address != address {
+			kept = append(kept, sig)
+		}
+	}
+
+	return kept
+}
+
+// missingSigners returns the addresses of the accounts with a role in the transaction which haven't signed it yet.
+//
+// The proposer and authorizers sign the payload, unless they are also the payer who signs the envelope.
+func missingSigners(tx *flow.Transaction) []string {
+	signed := func(sigs []flow.TransactionSignature, address flow.Address) bool {
+		for _, sig := range sigs {
+			if sig.Address == address {
+				return true
+			}
+		}
+		return false
+	}
+
+	missing := make([]string, 0)
+	seen := make(map[flow.Address]bool)
+	for _, address := range append([]flow.Address{tx.ProposalKey.Address}, tx.Authorizers...) {
+		if seen[address] || address == tx.Payer {
+			continue
+		}
+		seen[address] = true
+
+		if !signed(tx.PayloadSignatures, address) {
+			missing = append(missing, address.Hex())
+		}
+	}
+
+	if !signed(tx.EnvelopeSignatures, tx.Payer) {
+		missing = append(missing, tx.Payer.Hex())
+	}
+
+	return missing
+}
@@ -0,0 +1,322 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+	"github.com/onflow/flow-cli/pkg/flowkit/output"
+	"github.com/onflow/flow-cli/pkg/flowkit/tests"
+)
+
+func TestSigningServer(t *testing.T) {
+	t.Parallel()
+
+	alice, bob, charlie := tests.Alice(), tests.Bob(), tests.Charlie()
+
+	setupServer := func(t *testing.T) (*Services, *httptest.Server, string) {
+		_, s, gw := setup()
+
+		signers := map[flow.Address]*flowkit.Account{
+			alice.Address():   alice,
+			bob.Address():     bob,
+			charlie.Address(): charlie,
+		}
+		gw.GetAccount.Run(func(args mock.Arguments) {
+			address := args.Get(1).(flow.Address)
+			privateKey, err := signers[address].Key().PrivateKey()
+			require.NoError(t, err)
+
+			gw.GetAccount.Return(&flow.Account{
+				Address: address,
+				Keys: []*flow.AccountKey{{
+					PublicKey: (*privateKey).PublicKey(),
+					SigAlgo:   (*privateKey).Algorithm(),
+					HashAlgo:  crypto.SHA3_256,
+					Weight:    flow.AccountKeyWeightThreshold,
+				}},
+			}, nil)
+		})
+
+		tx := flow.NewTransaction().
+			SetScript(tests.TransactionTwoAuth.Source).
+			SetReferenceBlockID(flow.HexToID("01")).
+			SetGasLimit(1000).
+			SetProposalKey(alice.Address(), 0, 1).
+			SetPayer(charlie.Address()).
+			AddAuthorizer(alice.Address()).
+			AddAuthorizer(bob.Address())
+
+		payload, err := flowkit.NewTransactionFromPayload([]byte(hex.EncodeToString(tx.Encode())))
+		require.NoError(t, err)
+
+		server := NewSigningServer(gw.Mock, nil, output.NewStdoutLogger(output.NoneLog))
+		id, err := server.Add(context.Background(), payload)
+		require.NoError(t, err)
+
+		httpServer := httptest.NewServer(server)
+		t.Cleanup(httpServer.Close)
+
+		return s, httpServer, fmt.Sprintf("%s%s/%s", httpServer.URL, SigningServerPath, id)
+	}
+
+	signRemote := func(t *testing.T, s *Services, url string, signer *flowkit.Account) (*flowkit.Transaction, error) {
+		payload, err := s.Transactions.GetRLP(url)
+		require.NoError(t, err)
+
+		signed, err := s.Transactions.Sign(signer, payload, true)
+		require.NoError(t, err)
+
+		return signed, s.Transactions.PostRLP(url, signed.FlowTransaction())
+	}
+
+	t.Run("Collect Signatures", func(t *testing.T) {
+		t.Parallel()
+
+		s, _, url := setupServer(t)
+
+		// co-signers sign the same payload at the same time
+		aliceRLP, err := s.Transactions.GetRLP(url)
+		require.NoError(t, err)
+		bobRLP, err := s.Transactions.GetRLP(url)
+		require.NoError(t, err)
+
+		for signer, payload := range map[*flowkit.Account][]byte{alice: aliceRLP, bob: bobRLP} {
+			signed, err := s.Transactions.Sign(signer, payload, true)
+			require.NoError(t, err)
+			require.NoError(t, s.Transactions.PostRLP(url, signed.FlowTransaction()))
+		}
+
+		_, err = s.Transactions.GetRLP(url + "/envelope")
+		assert.Error(t, err)
+
+		_, err = signRemote(t, s, url, charlie)
+		require.NoError(t, err)
+
+		envelope, err := s.Transactions.GetRLP(url + "/envelope")
+		require.NoError(t, err)
+
+		tx, err := flowkit.NewTransactionFromPayload(envelope)
+		require.NoError(t, err)
+		assert.Len(t, tx.FlowTransaction().PayloadSignatures, 2)
+		assert.Len(t, tx.FlowTransaction().EnvelopeSignatures, 1)
+	})
+
+	t.Run("List Payloads", func(t *testing.T) {
+		t.Parallel()
+
+		s, httpServer, url := setupServer(t)
+
+		_, err := signRemote(t, s, url, alice)
+		require.NoError(t, err)
+
+		resp, err := http.Get(httpServer.URL + SigningServerPath)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var payloads []*SigningPayload
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&payloads))
+		require.Len(t, payloads, 1)
+
+		assert.False(t, payloads[0].Complete)
+		assert.Equal(t, []string{bob.Address().Hex(), charlie.Address().Hex()}, payloads[0].MissingSigners)
+		assert.Equal(t, string(tests.TransactionTwoAuth.Source), payloads[0].Transaction.Code)
+		assert.Len(t, payloads[0].Transaction.PayloadSignatures, 1)
+	})
+
+	t.Run("Envelope Signed Over Stale Payload", func(t *testing.T) {
+		t.Parallel()
+
+		s, _, url := setupServer(t)
+
+		_, err := signRemote(t, s, url, alice)
+		require.NoError(t, err)
+
+		// the payer signs before bob's signature is collected
+		payerRLP, err := s.Transactions.GetRLP(url)
+		require.NoError(t, err)
+
+		_, err = signRemote(t, s, url, bob)
+		require.NoError(t, err)
+
+		signed, err := s.Transactions.Sign(charlie, payerRLP, true)
+		require.NoError(t, err)
+
+		resp, err := http.Post(url, "application/text", signedBody(signed))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		_, err = signRemote(t, s, url, charlie)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Reject Different Payload", func(t *testing.T) {
+		t.Parallel()
+
+		s, _, url := setupServer(t)
+
+		payload, err := s.Transactions.GetRLP(url)
+		require.NoError(t, err)
+
+		tx, err := flowkit.NewTransactionFromPayload(payload)
+		require.NoError(t, err)
+		tx.SetGasLimit(9999)
+
+		signed, err := s.Transactions.Sign(alice, []byte(hex.EncodeToString(tx.FlowTransaction().Encode())), true)
+		require.NoError(t, err)
+
+		resp, err := http.Post(url, "application/text", signedBody(signed))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Reject Invalid Signature", func(t *testing.T) {
+		t.Parallel()
+
+		s, _, url := setupServer(t)
+
+		payload, err := s.Transactions.GetRLP(url)
+		require.NoError(t, err)
+
+		signed, err := s.Transactions.Sign(alice, payload, true)
+		require.NoError(t, err)
+		signed.FlowTransaction().PayloadSignatures[0].Signature[0] ^= 0xff
+
+		resp, err := http.Post(url, "application/text", signedBody(signed))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		// a payer envelope signature can't be collected with an invalid signature and block the payload signatures
+		signed, err = s.Transactions.Sign(bob, payload, true)
+		require.NoError(t, err)
+		signed.FlowTransaction().AddEnvelopeSignature(charlie.Address(), 0, []byte("invalid"))
+
+		resp, err = http.Post(url, "application/text", signedBody(signed))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		_, err = signRemote(t, s, url, alice)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Replace And Remove Signatures", func(t *testing.T) {
+		t.Parallel()
+
+		s, _, url := setupServer(t)
+
+		unsigned, err := s.Transactions.GetRLP(url)
+		require.NoError(t, err)
+		_, err = signRemote(t, s, url, alice)
+		require.NoError(t, err)
+
+		// signing again with the same key replaces the collected signature
+		signed, err := s.Transactions.Sign(alice, unsigned, true)
+		require.NoError(t, err)
+		require.NoError(t, s.Transactions.PostRLP(url, signed.FlowTransaction()))
+
+		for _, signer := range []*flowkit.Account{bob, charlie} {
+			_, err := signRemote(t, s, url, signer)
+			require.NoError(t, err)
+		}
+
+		payload, err := s.Transactions.GetRLP(url)
+		require.NoError(t, err)
+		tx, err := flowkit.NewTransactionFromPayload(payload)
+		require.NoError(t, err)
+		assert.Len(t, tx.FlowTransaction().PayloadSignatures, 2)
+		assert.Len(t, tx.FlowTransaction().EnvelopeSignatures, 1)
+
+		// removing a payload signature removes the envelope signature made over it
+		remove := func(address flow.Address) int {
+			req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/signatures/%s", url, address), nil)
+			require.NoError(t, err)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+		assert.Equal(t, http.StatusOK, remove(bob.Address()))
+		assert.Equal(t, http.StatusNotFound, remove(bob.Address()))
+
+		payload, err = s.Transactions.GetRLP(url)
+		require.NoError(t, err)
+		tx, err = flowkit.NewTransactionFromPayload(payload)
+		require.NoError(t, err)
+		assert.Len(t, tx.FlowTransaction().PayloadSignatures, 1)
+		assert.Empty(t, tx.FlowTransaction().EnvelopeSignatures)
+
+		for _, signer := range []*flowkit.Account{bob, charlie} {
+			_, err := signRemote(t, s, url, signer)
+			require.NoError(t, err)
+		}
+
+		_, err = s.Transactions.GetRLP(url + "/envelope")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Reject Oversized Payload", func(t *testing.T) {
+		t.Parallel()
+
+		_, httpServer, url := setupServer(t)
+
+		for _, target := range []string{httpServer.URL + SigningServerPath, url} {
+			body := strings.NewReader(strings.Repeat("0", maxSigningPayloadSize+1))
+			resp, err := http.Post(target, "application/text", body)
+			require.NoError(t, err)
+			message, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.Contains(t, string(message), "too large")
+		}
+	})
+
+	t.Run("Payload Not Found", func(t *testing.T) {
+		t.Parallel()
+
+		s, httpServer, _ := setupServer(t)
+
+		_, err := s.Transactions.GetRLP(httpServer.URL + SigningServerPath + "/missing")
+		assert.Error(t, err)
+	})
+}
+
+func signedBody(tx *flowkit.Transaction) io.Reader {
+	return strings.NewReader(hex.EncodeToString(tx.FlowTransaction().Encode()))
+}
@@ -19,6 +19,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
@@ -47,14 +48,14 @@ func NewSnapshot(
 }
 
 // GetLatestProtocolStateSnapshot returns the latest finalized protocol snapshot
-func (s *Snapshot) GetLatestProtocolStateSnapshot() ([]byte, error) {
+func (s *Snapshot) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
 	s.logger.StartProgress("Downloading protocol snapshot...")
 
 	if !s.gateway.SecureConnection() {
 		s.logger.Info(fmt.Sprintf("%s warning: using insecure client connection to download snapshot, you should use a secure network configuration...", output.WarningEmoji()))
 	}
 
-	b, err := s.gateway.GetLatestProtocolStateSnapshot()
+	b, err := s.gateway.GetLatestProtocolStateSnapshot(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get latest finalized protocol snapshot from gateway: %w", err)
 	}
@@ -19,6 +19,8 @@
 package services
 
 import (
+	"context"
+
 	"github.com/onflow/flow-cli/pkg/flowkit"
 	"github.com/onflow/flow-cli/pkg/flowkit/gateway"
 	"github.com/onflow/flow-cli/pkg/flowkit/output"
@@ -45,8 +47,8 @@ func NewStatus(
 }
 
 // Ping sends Ping request to network.
-func (s *Status) Ping(network string) (string, error) {
-	err := s.gateway.Ping()
+func (s *Status) Ping(ctx context.Context, network string) (string, error) {
+	err := s.gateway.Ping(ctx)
 	if err != nil {
 		return "", err
 	}
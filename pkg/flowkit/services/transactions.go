@@ -20,10 +20,14 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
 
@@ -31,6 +35,7 @@ import (
 
 	"github.com/onflow/flow-cli/pkg/flowkit/contracts"
 
+	"github.com/onflow/flow-emulator/types"
 	"github.com/onflow/flow-go-sdk"
 
 	"github.com/onflow/flow-cli/pkg/flowkit/gateway"
@@ -39,9 +44,12 @@ import (
 
 // Transactions is a service that handles all transaction-related interactions.
 type Transactions struct {
-	gateway gateway.Gateway
-	state   *flowkit.State
-	logger  output.Logger
+	gateway      gateway.Gateway
+	state        *flowkit.State
+	logger       output.Logger
+	gas          *gasEstimation
+	proposerKeys *flowkit.ProposerKeyPool
+	pollInterval time.Duration // interval at which the transaction result is polled while waiting for a status
 }
 
 // NewTransactions returns a new transactions service.
@@ -51,35 +59,346 @@ func NewTransactions(
 	logger output.Logger,
 ) *Transactions {
 	return &Transactions{
-		gateway: gateway,
-		state:   state,
-		logger:  logger,
+		gateway:      gateway,
+		state:        state,
+		logger:       logger,
+		gas:          newGasEstimation(gateway, logger),
+		pollInterval: defaultTransactionStatusPollInterval,
 	}
 }
 
-// GetStatus of transaction.
-func (t *Transactions) GetStatus(
+// SetProposerKeyPool sets the pool of proposal keys used by the transactions sent with the pool account as the proposer.
+func (t *Transactions) SetProposerKeyPool(pool *flowkit.ProposerKeyPool) {
+	t.proposerKeys = pool
+}
+
+// defaultTransactionStatusPollInterval is the interval at which the transaction result is polled while waiting for a status.
+const defaultTransactionStatusPollInterval = time.Second
+
+// TransactionStatusUpdate is a status transition of a transaction observed while waiting for its result.
+type TransactionStatusUpdate struct {
+	Status flow.TransactionStatus
+	Time   time.Time
+	Result *flow.TransactionResult
+}
+
+// TransactionStatusHandler is called with every status transition of a transaction.
+type TransactionStatusHandler func(update TransactionStatusUpdate)
+
+// GetStatus of transaction, waiting for it to be sealed if waitSeal is set.
+//
+// Deprecated: use GetStatusWithContext, GetStatus is kept for callers without a context and can't be cancelled.
+func (t *Transactions) GetStatus(id flow.Identifier, waitSeal bool) (*flow.Transaction, *flow.TransactionResult, error) {
+	waitFor := flow.TransactionStatusUnknown
+	if waitSeal {
+		waitFor = flow.TransactionStatusSealed
+	}
+
+	return t.GetStatusWithContext(context.Background(), id, waitFor)
+}
+
+// GetStatusWithContext returns the status of transaction.
+//
+// If waitFor is set to a status other than unknown, the result is returned once the transaction
+// reaches the status and the transitions on the way are logged.
+func (t *Transactions) GetStatusWithContext(
+	ctx context.Context,
 	id flow.Identifier,
-	waitSeal bool,
+	waitFor flow.TransactionStatus,
 ) (*flow.Transaction, *flow.TransactionResult, error) {
 	t.logger.StartProgress("Fetching Transaction...")
+	defer t.logger.StopProgress()
 
-	tx, err := t.gateway.GetTransaction(id)
+	tx, err := t.gateway.GetTransaction(ctx, id)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	if waitSeal {
-		t.logger.StartProgress("Waiting for transaction to be sealed...")
+	var onUpdate TransactionStatusHandler
+	if waitFor != flow.TransactionStatusUnknown {
+		onUpdate = t.logStatusUpdate(waitFor)
 	}
 
-	result, err := t.gateway.GetTransactionResult(id, waitSeal)
-	t.logger.StopProgress()
-
+	result, err := t.WaitForStatus(ctx, id, waitFor, onUpdate)
 	return tx, result, err
 }
 
+// WaitForStatus polls the transaction result until the transaction reaches the status and calls the
+// handler with every status transition seen on the way, the handler is optional.
+//
+// Statuses are ordered from pending to sealed, an expired transaction never reaches the requested
+// status so its result is returned as soon as it expires.
+func (t *Transactions) WaitForStatus(
+	ctx context.Context,
+	id flow.Identifier,
+	status flow.TransactionStatus,
+	onUpdate TransactionStatusHandler,
+) (*flow.TransactionResult, error) {
+	lastStatus := flow.TransactionStatusUnknown
+	for {
+		result, err := t.gateway.GetTransactionResult(ctx, id, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.Status != lastStatus {
+			lastStatus = result.Status
+			if onUpdate != nil {
+				onUpdate(TransactionStatusUpdate{
+					Status: result.Status,
+					Time:   time.Now(),
+					Result: result,
+				})
+			}
+		}
+
+		if result.Status >= status {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(t.pollInterval):
+		}
+	}
+}
+
+// logStatusUpdate returns a handler logging the status transitions while waiting for the status.
+func (t *Transactions) logStatusUpdate(waitFor flow.TransactionStatus) TransactionStatusHandler {
+	return func(update TransactionStatusUpdate) {
+		t.logger.StopProgress()
+		t.logger.Info(fmt.Sprintf(
+			"Transaction %s at %s",
+			strings.ToLower(update.Status.String()),
+			update.Time.Format("15:04:05.000"),
+		))
+
+		if update.Status < waitFor {
+			t.logger.StartProgress(fmt.Sprintf("Waiting for transaction to be %s...", strings.ToLower(waitFor.String())))
+		}
+	}
+}
+
+// TransactionReplay is a network transaction with its result and the result of replaying it on an in-process emulator.
+type TransactionReplay struct {
+	Transaction *flow.Transaction
+	Result      *flow.TransactionResult
+	Replayed    *types.TransactionResult
+	// Addresses maps the emulator addresses in the replayed result to the network addresses they replace.
+	Addresses map[flow.Address]flow.Address
+}
+
+// ReplayDifference is a difference between the network result and the replayed result of a transaction.
+type ReplayDifference struct {
+	Field    string
+	Result   string
+	Replayed string
+}
+
+// ReplayStatus returns the status of a network or replayed transaction result with the error.
+func ReplayStatus(err error) string {
+	if err != nil {
+		return "failed"
+	}
+	return "succeeded"
+}
+
+// Differences compares the network result with the replayed result.
+//
+// The emulator addresses in the replayed error and event fields are replaced with the network addresses
+// before they are compared, the event types are compared by their contract and event names.
+// The network result doesn't include the computation used, it's compared through the fields of the fees event
+// when the network charges transaction fees.
+func (r *TransactionReplay) Differences() []ReplayDifference {
+	var differences []ReplayDifference
+
+	if ReplayStatus(r.Result.Error) != ReplayStatus(r.Replayed.Error) {
+		differences = append(differences, ReplayDifference{
+			Field:    "status",
+			Result:   ReplayStatus(r.Result.Error),
+			Replayed: ReplayStatus(r.Replayed.Error),
+		})
+	} else if r.Result.Error != nil {
+		result, replayed := r.Result.Error.Error(), r.networkText(r.Replayed.Error.Error())
+		if result != replayed {
+			differences = append(differences, ReplayDifference{
+				Field:    "error",
+				Result:   result,
+				Replayed: replayed,
+			})
+		}
+	}
+
+	events := len(r.Result.Events)
+	if len(r.Replayed.Events) > events {
+		events = len(r.Replayed.Events)
+	}
+
+	for i := 0; i < events; i++ {
+		var result, replayed string
+		if i < len(r.Result.Events) {
+			result = eventName(r.Result.Events[i].Type)
+		}
+		if i < len(r.Replayed.Events) {
+			replayed = eventName(r.Replayed.Events[i].Type)
+		}
+
+		if result != replayed {
+			differences = append(differences, ReplayDifference{
+				Field:    fmt.Sprintf("event %d", i),
+				Result:   result,
+				Replayed: replayed,
+			})
+			continue
+		}
+
+		differences = append(differences, r.eventFieldDifferences(i)...)
+	}
+
+	return differences
+}
+
+// eventFieldDifferences compares the fields of the network and replayed events at the index, which have the same name.
+func (r *TransactionReplay) eventFieldDifferences(index int) []ReplayDifference {
+	resultEvent, replayedEvent := r.Result.Events[index].Value, r.Replayed.Events[index].Value
+
+	fields := len(resultEvent.Fields)
+	if len(replayedEvent.Fields) > fields {
+		fields = len(replayedEvent.Fields)
+	}
+
+	var differences []ReplayDifference
+	for i := 0; i < fields; i++ {
+		var result, replayed string
+		if i < len(resultEvent.Fields) {
+			result = resultEvent.Fields[i].String()
+		}
+		if i < len(replayedEvent.Fields) {
+			replayed = r.networkText(replayedEvent.Fields[i].String())
+		}
+
+		if result != replayed {
+			differences = append(differences, ReplayDifference{
+				Field:    fmt.Sprintf("event %d %s", index, eventFieldName(resultEvent, replayedEvent, i)),
+				Result:   result,
+				Replayed: replayed,
+			})
+		}
+	}
+
+	return differences
+}
+
+// networkText replaces the emulator addresses in the replayed text with the network addresses they replace.
+func (r *TransactionReplay) networkText(text string) string {
+	for emulatorAddress, networkAddress := range r.Addresses {
+		text = strings.ReplaceAll(text, emulatorAddress.Hex(), networkAddress.Hex())
+	}
+
+	return text
+}
+
+// eventName returns the event type without the contract address, the types of core events are returned as they are.
+func eventName(eventType string) string {
+	parts := strings.SplitN(eventType, ".", 3)
+	if len(parts) == 3 && parts[0] == "A" {
+		return parts[2]
+	}
+
+	return eventType
+}
+
+// eventFieldName returns the name of the field at the index of either event, or the index if the events have no field types.
+func eventFieldName(result cadence.Event, replayed cadence.Event, index int) string {
+	for _, event := range []cadence.Event{result, replayed} {
+		if event.EventType != nil && index < len(event.EventType.Fields) {
+			return event.EventType.Fields[index].Identifier
+		}
+	}
+
+	return strconv.Itoa(index)
+}
+
+// Replay fetches an executed transaction and its result from the network and replays it on an in-process emulator.
+//
+// The emulator is seeded with the contracts of the transaction authorizers and the contracts imported by the
+// transaction as they were at the block before the transaction, if the network still serves the state at that height.
+func (t *Transactions) Replay(ctx context.Context, id flow.Identifier) (*TransactionReplay, error) {
+	t.logger.StartProgress("Fetching Transaction...")
+	defer t.logger.StopProgress()
+
+	tx, err := t.gateway.GetTransaction(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := t.gateway.GetTransactionResult(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Status != flow.TransactionStatusExecuted && result.Status != flow.TransactionStatusSealed {
+		return nil, fmt.Errorf(
+			"transaction %s is %s, only executed transactions can be replayed",
+			id,
+			strings.ToLower(result.Status.String()),
+		)
+	}
+
+	// not every access node sets the height of the result, so it's taken from the block if it's missing
+	height := result.BlockHeight
+	if height == 0 && result.BlockID != flow.EmptyID {
+		block, err := t.gateway.GetBlockByID(ctx, result.BlockID)
+		if err != nil {
+			return nil, err
+		}
+		height = block.Height
+	}
+	if height > 0 {
+		height--
+	} else {
+		t.logger.Info(fmt.Sprintf(
+			"⚠️  The block of transaction %s is unknown, the transaction is replayed with the latest account state.", id,
+		))
+	}
+
+	// networks charging fees emit a fees event for every transaction
+	transactionFees := false
+	for _, event := range result.Events {
+		if strings.HasSuffix(event.Type, ".FlowFees.FeesDeducted") {
+			transactionFees = true
+		}
+	}
+
+	t.logger.StartProgress("Replaying transaction on the emulator...")
+
+	replayer, err := gateway.NewTransactionReplayer(t.gateway, height, transactionFees)
+	if err != nil {
+		return nil, err
+	}
+
+	replayed, err := replayer.Replay(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay transaction: %w", err)
+	}
+
+	addresses, err := replayer.NetworkAddresses(tx.Payer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransactionReplay{
+		Transaction: tx,
+		Result:      result,
+		Replayed:    replayed,
+		Addresses:   addresses,
+	}, nil
+}
+
 // Build builds a transaction with specified payer, proposer and authorizer.
+//
+// Deprecated: use BuildWithContext, Build is kept for callers without a context and can't be cancelled.
 func (t *Transactions) Build(
 	proposer flow.Address,
 	authorizers []flow.Address,
@@ -92,15 +411,77 @@ func (t *Transactions) Build(
 	network string,
 	approveBuild bool,
 ) (*flowkit.Transaction, error) {
+	return t.BuildWithContext(
+		context.Background(),
+		proposer,
+		authorizers,
+		payer,
+		proposerKeyIndex,
+		code,
+		codeFilename,
+		gasLimit,
+		DefaultGasMargin,
+		args,
+		network,
+		approveBuild,
+	)
+}
 
-	latestBlock, err := t.gateway.GetLatestBlock()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get latest sealed block: %w", err)
-	}
+// BuildWithContext builds a transaction with specified payer, proposer and authorizer.
+//
+// If the gas limit is zero it's estimated by executing the transaction on an in-process emulator
+// seeded with the account state from the network, and adding the gas margin, in percent of the computation used.
+func (t *Transactions) BuildWithContext(
+	ctx context.Context,
+	proposer flow.Address,
+	authorizers []flow.Address,
+	payer flow.Address,
+	proposerKeyIndex int,
+	code []byte,
+	codeFilename string,
+	gasLimit uint64,
+	gasMargin uint64,
+	args []cadence.Value,
+	network string,
+	approveBuild bool,
+) (*flowkit.Transaction, error) {
+	return t.build(
+		ctx,
+		proposer,
+		authorizers,
+		payer,
+		proposerKeyIndex,
+		nil,
+		code,
+		codeFilename,
+		gasLimit,
+		gasMargin,
+		args,
+		network,
+		approveBuild,
+	)
+}
 
-	proposerAccount, err := t.gateway.GetAccount(proposer)
+// build builds the transaction, see BuildWithContext. The proposer account is fetched for the sequence
+// number of the proposer key, unless a proposal key leased from the proposer key pool is provided.
+func (t *Transactions) build(
+	ctx context.Context,
+	proposer flow.Address,
+	authorizers []flow.Address,
+	payer flow.Address,
+	proposerKeyIndex int,
+	proposalKey *flowkit.ProposerKey,
+	code []byte,
+	codeFilename string,
+	gasLimit uint64,
+	gasMargin uint64,
+	args []cadence.Value,
+	network string,
+	approveBuild bool,
+) (*flowkit.Transaction, error) {
+	latestBlock, err := t.gateway.GetLatestBlock(ctx)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get latest sealed block: %w", err)
 	}
 
 	tx := flowkit.NewTransaction().
@@ -108,8 +489,17 @@ func (t *Transactions) Build(
 		SetGasLimit(gasLimit).
 		SetBlockReference(latestBlock)
 
-	if err := tx.SetProposer(proposerAccount, proposerKeyIndex); err != nil {
-		return nil, err
+	if proposalKey != nil {
+		tx.FlowTransaction().SetProposalKey(proposer, proposalKey.Index, proposalKey.SequenceNumber)
+	} else {
+		proposerAccount, err := t.gateway.GetAccount(ctx, proposer)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := tx.SetProposer(proposerAccount, proposerKeyIndex); err != nil {
+			return nil, err
+		}
 	}
 
 	resolver, err := contracts.NewResolver(code)
@@ -140,16 +530,119 @@ func (t *Transactions) Build(
 		}
 	}
 
-	err = tx.SetScriptWithArgs(code, args)
+	tx, err = setTransactionCode(tx, code, args, authorizers)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.gas.setGasLimit(ctx, tx, gasLimit, gasMargin)
+	if err != nil {
+		return nil, err
+	}
+
+	if approveBuild {
+		return tx, nil
+	}
+
+	if !output.ApproveTransactionForBuildingPrompt(tx) {
+		return nil, fmt.Errorf("transaction was not approved")
+	}
+
+	return tx, nil
+}
+
+// Reference returns the latest block and the sequence number of the proposer key at the key index,
+// which are used to build a transaction offline, see BuildOffline.
+//
+// The transactions built with the reference expire with the reference block, which is logged as a warning.
+func (t *Transactions) Reference(
+	ctx context.Context,
+	proposer flow.Address,
+	proposerKeyIndex int,
+) (*flowkit.TransactionReference, error) {
+	latestBlock, err := t.gateway.GetLatestBlock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest sealed block: %w", err)
+	}
+
+	proposerAccount, err := t.gateway.GetAccount(ctx, proposer)
+	if err != nil {
+		return nil, err
+	}
+
+	reference, err := flowkit.NewTransactionReference(latestBlock, proposerAccount, proposerKeyIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	t.logReferenceExpiry(reference)
+	return reference, nil
+}
+
+// BuildOffline builds a transaction without network access, using the reference block and
+// proposer key sequence number of the transaction reference.
+//
+// Imports are resolved from the contract aliases of the network only and the gas limit can't be
+// estimated. The transaction must be sent before the reference block expires, which is logged as a warning.
+func (t *Transactions) BuildOffline(
+	reference *flowkit.TransactionReference,
+	authorizers []flow.Address,
+	payer flow.Address,
+	code []byte,
+	codeFilename string,
+	gasLimit uint64,
+	args []cadence.Value,
+	network string,
+	approveBuild bool,
+) (*flowkit.Transaction, error) {
+	if gasLimit == 0 {
+		return nil, fmt.Errorf("gas limit can't be estimated when building offline, specify the gas limit")
+	}
+
+	referenceBlockID, err := reference.BlockID()
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err = tx.AddAuthorizers(authorizers)
+	proposer, err := reference.ProposerAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	tx := flowkit.NewTransaction().
+		SetPayer(payer).
+		SetGasLimit(gasLimit)
+	tx.FlowTransaction().
+		SetReferenceBlockID(referenceBlockID).
+		SetProposalKey(proposer, reference.Proposer.KeyIndex, reference.Proposer.SequenceNumber)
+
+	resolver, err := contracts.NewResolver(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolver.HasFileImports() {
+		if network == "" {
+			return nil, fmt.Errorf("missing network, specify which network aliases to use to resolve imports in transaction code")
+		}
+		if codeFilename == "" {
+			return nil, fmt.Errorf("resolving imports in transactions not supported")
+		}
+
+		// deployed contract addresses aren't known offline, only aliases are
+		code, err = resolver.ResolveImports(codeFilename, nil, t.state.AliasesForNetwork(network))
+		if err != nil {
+			return nil, fmt.Errorf("%w, offline builds resolve imports from the network aliases only", err)
+		}
+	}
+
+	tx, err = setTransactionCode(tx, code, args, authorizers)
 	if err != nil {
 		return nil, err
 	}
 
+	t.logReferenceExpiry(reference)
+
 	if approveBuild {
 		return tx, nil
 	}
@@ -161,11 +654,82 @@ func (t *Transactions) Build(
 	return tx, nil
 }
 
+// logReferenceExpiry warns that the transactions built with the reference expire with the reference block.
+func (t *Transactions) logReferenceExpiry(reference *flowkit.TransactionReference) {
+	expiresAt := reference.ExpiresAt()
+	if expiresAt.IsZero() {
+		t.logger.Info(fmt.Sprintf(
+			"⚠️  Transactions built with the reference expire %d blocks after the reference block %d, about %s after the reference was exported. Sign and send them before they expire.",
+			flowkit.TransactionExpiry,
+			reference.ReferenceBlockHeight,
+			flowkit.TransactionExpiryDuration,
+		))
+		return
+	}
+
+	if time.Now().After(expiresAt) {
+		t.logger.Info(fmt.Sprintf(
+			"⚠️  The reference block %d is from %s and has likely expired, export a new reference on an online machine.",
+			reference.ReferenceBlockHeight,
+			reference.ReferenceBlockTimestamp.Format(time.RFC3339),
+		))
+		return
+	}
+
+	t.logger.Info(fmt.Sprintf(
+		"⚠️  Transactions built with the reference expire %d blocks after the reference block %d, at about %s (in %s). Sign and send them before they expire.",
+		flowkit.TransactionExpiry,
+		reference.ReferenceBlockHeight,
+		expiresAt.Format(time.RFC3339),
+		time.Until(expiresAt).Round(time.Second),
+	))
+}
+
+// setTransactionCode sets the code, arguments and authorizers of the transaction.
+func setTransactionCode(
+	tx *flowkit.Transaction,
+	code []byte,
+	args []cadence.Value,
+	authorizers []flow.Address,
+) (*flowkit.Transaction, error) {
+	err := tx.SetScriptWithArgs(code, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.AddAuthorizers(authorizers)
+}
+
 // Sign transaction payload using the signer account.
 func (t *Transactions) Sign(
 	signer *flowkit.Account,
 	payload []byte,
 	approveSigning bool,
+) (*flowkit.Transaction, error) {
+	return t.sign(payload, approveSigning, func(tx *flowkit.Transaction) error {
+		return tx.SetSigner(signer)
+	})
+}
+
+// SignWithKey signs the transaction payload using the signer account key at the key index.
+//
+// Signing with each key of an account adds partial signatures, which are accumulated until
+// the account reaches the signature weight threshold, see SignatureWeights.
+func (t *Transactions) SignWithKey(
+	signer *flowkit.Account,
+	keyIndex int,
+	payload []byte,
+	approveSigning bool,
+) (*flowkit.Transaction, error) {
+	return t.sign(payload, approveSigning, func(tx *flowkit.Transaction) error {
+		return tx.SetSignerWithKey(signer, keyIndex)
+	})
+}
+
+func (t *Transactions) sign(
+	payload []byte,
+	approveSigning bool,
+	setSigner func(tx *flowkit.Transaction) error,
 ) (*flowkit.Transaction, error) {
 	if t.state == nil {
 		return nil, fmt.Errorf("missing configuration, initialize it: flow state init")
@@ -176,7 +740,7 @@ func (t *Transactions) Sign(
 		return nil, err
 	}
 
-	err = tx.SetSigner(signer)
+	err = setSigner(tx)
 	if err != nil {
 		return nil, err
 	}
@@ -192,15 +756,167 @@ func (t *Transactions) Sign(
 	return tx.Sign()
 }
 
-// SendSigned sends the transaction that is already signed.
-func (t *Transactions) SendSigned(
+// Transaction roles of the accounts which sign a transaction.
+const (
+	RoleProposer   = "proposer"
+	RoleAuthorizer = "authorizer"
+	RolePayer      = "payer"
+)
+
+// SignatureWeight is the accumulated weight of the signatures an account added to a transaction.
+type SignatureWeight struct {
+	Address flow.Address
+	// Roles of the account in the transaction, an account can have multiple roles.
+	Roles []string
+	// Weight is the sum of the weights of the account keys which signed the transaction.
+	Weight int
+	// Required is the weight the account signatures must reach, a proposer which is not also
+	// an authorizer or the payer only requires the signature of the proposal key.
+	Required int
+	// ProposalKeySigned is true if the account is the proposer and the proposal key signed.
+	ProposalKeySigned bool
+}
+
+// HasRole checks whether the account has the role in the transaction.
+func (w *SignatureWeight) HasRole(role string) bool {
+	for _, r := range w.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete checks whether the account signatures are sufficient for the transaction to be sent.
+func (w *SignatureWeight) Complete() bool {
+	if w.HasRole(RoleProposer) && !w.ProposalKeySigned {
+		return false
+	}
+	return w.Weight >= w.Required
+}
+
+// SignatureWeights returns the accumulated signature weight of each account that has to sign the transaction.
+//
+// The payer signs the envelope while the proposer and authorizers sign the payload, unless they are also
+// the payer. Signatures of revoked keys don't count towards the weight.
+func (t *Transactions) SignatureWeights(ctx context.Context, tx *flowkit.Transaction) ([]*SignatureWeight, error) {
+	flowTx := tx.FlowTransaction()
+
+	var weights []*SignatureWeight
+	addRole := func(address flow.Address, role string) {
+		for _, w := range weights {
+			if w.Address == address {
+				if !w.HasRole(role) {
+					w.Roles = append(w.Roles, role)
+				}
+				return
+			}
+		}
+		weights = append(weights, &SignatureWeight{Address: address, Roles: []string{role}})
+	}
+
+	addRole(flowTx.ProposalKey.Address, RoleProposer)
+	for _, authorizer := range flowTx.Authorizers {
+		addRole(authorizer, RoleAuthorizer)
+	}
+	addRole(flowTx.Payer, RolePayer)
+
+	for _, w := range weights {
+		account, err := t.gateway.GetAccount(ctx, w.Address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account %s: %w", w.Address, err)
+		}
+
+		signatures := flowTx.PayloadSignatures
+		if w.Address == flowTx.Payer {
+			signatures = flowTx.EnvelopeSignatures
+		}
+
+		signed := make(map[int]bool)
+		for _, sig := range signatures {
+			if sig.Address == w.Address {
+				signed[sig.KeyIndex] = true
+			}
+		}
+
+		for _, key := range account.Keys {
+			if signed[key.Index] && !key.Revoked {
+				w.Weight += key.Weight
+			}
+		}
+
+		if w.HasRole(RoleAuthorizer) || w.HasRole(RolePayer) {
+			w.Required = flow.AccountKeyWeightThreshold
+		}
+
+		if w.HasRole(RoleProposer) {
+			w.ProposalKeySigned = signed[flowTx.ProposalKey.KeyIndex]
+		}
+	}
+
+	return weights, nil
+}
+
+// checkSignatureWeights returns an error if any account that has to sign the transaction didn't reach the required weight.
+func (t *Transactions) checkSignatureWeights(ctx context.Context, tx *flowkit.Transaction) error {
+	weights, err := t.SignatureWeights(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, w := range weights {
+		if w.Complete() {
+			continue
+		}
+
+		if w.HasRole(RoleProposer) && !w.ProposalKeySigned {
+			missing = append(missing, fmt.Sprintf(
+				"proposer %s is missing the signature of the proposal key %d",
+				w.Address, tx.FlowTransaction().ProposalKey.KeyIndex,
+			))
+		}
+		if w.Weight < w.Required {
+			missing = append(missing, fmt.Sprintf(
+				"account %s (%s) has signature weight %d of the required %d",
+				w.Address, strings.Join(w.Roles, ", "), w.Weight, w.Required,
+			))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("transaction is not signed with enough weight to be sent:\n%s", strings.Join(missing, "\n"))
+	}
+
+	return nil
+}
+
+// SendSigned sends the transaction that is already signed and waits for it to be sealed.
+//
+// Deprecated: use SendSignedWithContext, SendSigned is kept for callers without a context and can't be cancelled.
+func (t *Transactions) SendSigned(payload []byte, approveSend bool) (*flow.Transaction, *flow.TransactionResult, error) {
+	return t.SendSignedWithContext(context.Background(), payload, approveSend, flow.TransactionStatusSealed)
+}
+
+// SendSignedWithContext sends the transaction that is already signed and waits for it to reach the waitFor status.
+//
+// The transaction isn't sent unless every account that has to sign it reached the required signature weight.
+func (t *Transactions) SendSignedWithContext(
+	ctx context.Context,
 	payload []byte,
 	approveSend bool,
+	waitFor flow.TransactionStatus,
 ) (*flow.Transaction, *flow.TransactionResult, error) {
 	tx, err := flowkit.NewTransactionFromPayload(payload)
 	if err != nil {
 		return nil, nil, err
 	}
+
+	err = t.checkSignatureWeights(ctx, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if !approveSend && !output.ApproveTransactionForSendingPrompt(tx) {
 		return nil, nil, fmt.Errorf("transaction was not approved for sending")
 	}
@@ -208,12 +924,12 @@ func (t *Transactions) SendSigned(
 	t.logger.StartProgress(fmt.Sprintf("Sending transaction with ID: %s", tx.FlowTransaction().ID()))
 	defer t.logger.StopProgress()
 
-	sentTx, err := t.gateway.SendSignedTransaction(tx)
+	sentTx, err := t.gateway.SendSignedTransaction(ctx, tx)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	res, err := t.gateway.GetTransactionResult(sentTx.ID(), true)
+	res, err := t.WaitForStatus(ctx, sentTx.ID(), waitFor, t.logStatusUpdate(waitFor))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -222,64 +938,253 @@ func (t *Transactions) SendSigned(
 }
 
 // Send a transaction code using the signer account and arguments for the specified network.
+//
+// The signer is the proposer, payer and the only authorizer of the transaction. The result is returned
+// once the transaction reaches the waitFor status. If the gas limit is zero it's estimated, see Build.
 func (t *Transactions) Send(
+	ctx context.Context,
 	signer *flowkit.Account,
 	code []byte,
 	codeFilename string,
 	gasLimit uint64,
+	gasMargin uint64,
 	args []cadence.Value,
 	network string,
+	waitFor flow.TransactionStatus,
 ) (*flow.Transaction, *flow.TransactionResult, error) {
-	if t.state == nil {
-		return nil, nil, fmt.Errorf("missing configuration, initialize it: flow state init")
-	}
-
-	signerKeyIndex := signer.Key().Index()
-
-	tx, err := t.Build(
-		signer.Address(),
-		[]flow.Address{signer.Address()},
-		signer.Address(),
-		signerKeyIndex,
+	return t.SendWithRoles(
+		ctx,
+		signer,
+		[]*flowkit.Account{signer},
+		signer,
 		code,
 		codeFilename,
 		gasLimit,
+		gasMargin,
 		args,
 		network,
-		true,
+		waitFor,
 	)
+}
+
+// SendWithRoles sends a transaction code with separate proposer, authorizer and payer accounts.
+//
+// The proposer and the authorizers sign the payload and the payer signs the envelope last, each with the
+// key from the configuration. An account with multiple roles signs only once. The result is returned
+// once the transaction reaches the waitFor status. If the gas limit is zero it's estimated, see Build.
+//
+// If a proposer key pool is set for the proposer account, the proposal key is leased from the pool until
+// the transaction reaches the waitFor status, see SetProposerKeyPool. The sequence number of the leased key
+// is used without fetching the proposer account, unless it's unknown whether the transaction was included.
+func (t *Transactions) SendWithRoles(
+	ctx context.Context,
+	proposer *flowkit.Account,
+	authorizers []*flowkit.Account,
+	payer *flowkit.Account,
+	code []byte,
+	codeFilename string,
+	gasLimit uint64,
+	gasMargin uint64,
+	args []cadence.Value,
+	network string,
+	waitFor flow.TransactionStatus,
+) (*flow.Transaction, *flow.TransactionResult, error) {
+	if t.state == nil {
+		return nil, nil, fmt.Errorf("missing configuration, initialize it: flow state init")
+	}
+
+	pool := t.proposerKeys
+	if pool == nil || pool.Address() != proposer.Address() {
+		return t.send(ctx, proposer, nil, authorizers, payer, code, codeFilename, gasLimit, gasMargin, args, network, waitFor)
+	}
+
+	key, err := pool.Acquire(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	err = tx.SetSigner(signer)
+	signer := pool.Signer(key)
+	tx, err := t.buildSigned(ctx, signer, key, authorizers, payer, code, codeFilename, gasLimit, gasMargin, args, network)
 	if err != nil {
+		pool.Release(key, false)
 		return nil, nil, err
 	}
 
-	signed, err := tx.Sign()
+	sentTx, res, err := t.sendSigned(ctx, tx, waitFor)
+	if res != nil && res.Status >= flow.TransactionStatusFinalized && res.Status <= flow.TransactionStatusSealed {
+		pool.Release(key, true)
+	} else {
+		// the transaction may or may not have been received, e.g. when sending timed out
+		t.releaseWithOnChainSequenceNumber(pool, key, sentTx != nil)
+	}
+
+	return sentTx, res, err
+}
+
+// releaseWithOnChainSequenceNumber releases the proposal key with the sequence number of the key on-chain,
+// used when it's not known whether the transaction proposed with the key was included in a block.
+//
+// If the account can't be fetched the key is released as if the transaction was sent when sent is set.
+func (t *Transactions) releaseWithOnChainSequenceNumber(pool *flowkit.ProposerKeyPool, key *flowkit.ProposerKey, sent bool) {
+	// the context of the transaction could be cancelled already
+	account, err := t.gateway.GetAccount(context.Background(), pool.Address())
+	if err == nil && key.Index < len(account.Keys) {
+		pool.ReleaseWithSequenceNumber(key, account.Keys[key.Index].SequenceNumber)
+		return
+	}
+
+	if err == nil {
+		err = fmt.Errorf("account has no key with index %d", key.Index)
+	}
+	t.logger.Info(fmt.Sprintf("⚠️  Failed to get the sequence number of proposal key %d: %s", key.Index, err))
+	pool.Release(key, sent)
+}
+
+// send builds, signs and sends the transaction.
+func (t *Transactions) send(
+	ctx context.Context,
+	proposer *flowkit.Account,
+	proposalKey *flowkit.ProposerKey,
+	authorizers []*flowkit.Account,
+	payer *flowkit.Account,
+	code []byte,
+	codeFilename string,
+	gasLimit uint64,
+	gasMargin uint64,
+	args []cadence.Value,
+	network string,
+	waitFor flow.TransactionStatus,
+) (*flow.Transaction, *flow.TransactionResult, error) {
+	tx, err := t.buildSigned(ctx, proposer, proposalKey, authorizers, payer, code, codeFilename, gasLimit, gasMargin, args, network)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	t.logger.Info(fmt.Sprintf("Transaction ID: %s", signed.FlowTransaction().ID()))
+	return t.sendSigned(ctx, tx, waitFor)
+}
+
+// buildSigned builds the transaction and signs it with all the signer roles, the proposal key sequence
+// number is used instead of the on-chain sequence number if a proposal key is leased from the pool.
+func (t *Transactions) buildSigned(
+	ctx context.Context,
+	proposer *flowkit.Account,
+	proposalKey *flowkit.ProposerKey,
+	authorizers []*flowkit.Account,
+	payer *flowkit.Account,
+	code []byte,
+	codeFilename string,
+	gasLimit uint64,
+	gasMargin uint64,
+	args []cadence.Value,
+	network string,
+) (*flowkit.Transaction, error) {
+	authorizerAddresses := make([]flow.Address, len(authorizers))
+	for i, authorizer := range authorizers {
+		authorizerAddresses[i] = authorizer.Address()
+	}
+
+	tx, err := t.build(
+		ctx,
+		proposer.Address(),
+		authorizerAddresses,
+		payer.Address(),
+		proposer.Key().Index(),
+		proposalKey,
+		code,
+		codeFilename,
+		gasLimit,
+		gasMargin,
+		args,
+		network,
+		true,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, signer := range transactionSigners(tx, proposer, authorizers, payer) {
+		err = tx.SetSigner(signer)
+		if err != nil {
+			return nil, err
+		}
+
+		tx, err = tx.Sign()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+// sendSigned sends the signed transaction and waits for the waitFor status.
+func (t *Transactions) sendSigned(
+	ctx context.Context,
+	tx *flowkit.Transaction,
+	waitFor flow.TransactionStatus,
+) (*flow.Transaction, *flow.TransactionResult, error) {
+	t.logger.Info(fmt.Sprintf("Transaction ID: %s", tx.FlowTransaction().ID()))
 	t.logger.StartProgress("Sending transaction...")
 	defer t.logger.StopProgress()
 
-	sentTx, err := t.gateway.SendSignedTransaction(signed)
+	sentTx, err := t.gateway.SendSignedTransaction(ctx, tx)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	t.logger.StartProgress("Waiting for transaction to be sealed...")
-
-	res, err := t.gateway.GetTransactionResult(sentTx.ID(), true)
+	res, err := t.WaitForStatus(ctx, sentTx.ID(), waitFor, t.logStatusUpdate(waitFor))
 
 	t.logger.StopProgress()
 
 	return sentTx, res, err
 }
 
+// transactionSigners returns the accounts signing the transaction in the signing order, payload signers
+// first and the payer signers last.
+//
+// An account key signs only once, and authorizers are skipped when the transaction has none because
+// they were dropped by the build.
+func transactionSigners(
+	tx *flowkit.Transaction,
+	proposer *flowkit.Account,
+	authorizers []*flowkit.Account,
+	payer *flowkit.Account,
+) []*flowkit.Account {
+	candidates := []*flowkit.Account{proposer}
+	if len(tx.FlowTransaction().Authorizers) > 0 {
+		candidates = append(candidates, authorizers...)
+	}
+	candidates = append(candidates, payer)
+
+	type signature struct {
+		address  flow.Address
+		keyIndex int
+	}
+
+	var payload, envelope []*flowkit.Account
+	signed := make(map[signature]bool)
+	for _, account := range candidates {
+		sig := signature{address: account.Address(), keyIndex: account.Key().Index()}
+		if signed[sig] {
+			continue
+		}
+		signed[sig] = true
+
+		if account.Address() == payer.Address() {
+			envelope = append(envelope, account)
+		} else {
+			payload = append(payload, account)
+		}
+	}
+
+	return append(payload, envelope...)
+}
+
+// SigningServer returns a new signing server, which hosts transaction payloads for co-signers to sign them over HTTP.
+func (t *Transactions) SigningServer() *SigningServer {
+	return NewSigningServer(t.gateway, t.state, t.logger)
+}
+
 func (t *Transactions) GetRLP(rlpUrl string) ([]byte, error) {
 
 	client := http.Client{
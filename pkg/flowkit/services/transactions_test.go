@@ -19,18 +19,23 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/onflow/flow-cli/pkg/flowkit/config"
 
 	"github.com/onflow/flow-go-sdk/crypto"
 
 	"github.com/onflow/cadence"
+	"github.com/onflow/flow-emulator/types"
 	"github.com/onflow/flow-go-sdk"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-cli/pkg/flowkit"
 	"github.com/onflow/flow-cli/pkg/flowkit/tests"
@@ -50,11 +55,46 @@ func TestTransactions(t *testing.T) {
 		_, s, gw := setup()
 		txs := tests.NewTransaction()
 
-		_, _, err := s.Transactions.GetStatus(txs.ID(), true)
+		_, _, err := s.Transactions.GetStatusWithContext(ctx, txs.ID(), flow.TransactionStatusSealed)
 
 		assert.NoError(t, err)
 		gw.Mock.AssertNumberOfCalls(t, tests.GetTransactionResultFunc, 1)
-		gw.Mock.AssertCalled(t, tests.GetTransactionFunc, txs.ID())
+		gw.Mock.AssertCalled(t, tests.GetTransactionFunc, mock.Anything, txs.ID())
+	})
+
+	t.Run("Wait for Transaction Status", func(t *testing.T) {
+		t.Parallel()
+		_, s, gw := setup()
+		s.Transactions.pollInterval = time.Millisecond
+		txs := tests.NewTransaction()
+
+		statuses := []flow.TransactionStatus{
+			flow.TransactionStatusPending,
+			flow.TransactionStatusPending,
+			flow.TransactionStatusFinalized,
+		}
+		calls := 0
+		gw.GetTransactionResult.Run(func(args mock.Arguments) {
+			result := tests.NewTransactionResult(nil)
+			result.Status = statuses[calls]
+			calls++
+			gw.GetTransactionResult.Return(result, nil)
+		})
+
+		var updates []flow.TransactionStatus
+		result, err := s.Transactions.WaitForStatus(
+			ctx,
+			txs.ID(),
+			flow.TransactionStatusFinalized,
+			func(update TransactionStatusUpdate) {
+				updates = append(updates, update.Status)
+			},
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusFinalized, result.Status)
+		assert.Equal(t, []flow.TransactionStatus{flow.TransactionStatusPending, flow.TransactionStatusFinalized}, updates)
+		gw.Mock.AssertNumberOfCalls(t, tests.GetTransactionResultFunc, 3)
 	})
 
 	t.Run("Send Transaction args", func(t *testing.T) {
@@ -63,7 +103,7 @@ func TestTransactions(t *testing.T) {
 
 		var txID flow.Identifier
 		gw.SendSignedTransaction.Run(func(args mock.Arguments) {
-			tx := args.Get(0).(*flowkit.Transaction)
+			tx := args.Get(1).(*flowkit.Transaction)
 			arg, err := tx.FlowTransaction().Argument(0)
 			assert.NoError(t, err)
 			assert.Equal(t, "\"Bar\"", arg.String())
@@ -76,18 +116,21 @@ func TestTransactions(t *testing.T) {
 		})
 
 		gw.GetTransactionResult.Run(func(args mock.Arguments) {
-			assert.Equal(t, txID, args.Get(0).(flow.Identifier))
+			assert.Equal(t, txID, args.Get(1).(flow.Identifier))
 			gw.GetTransactionResult.Return(tests.NewTransactionResult(nil), nil)
 		})
 
 		args := []cadence.Value{cadence.String("Bar")}
 		_, _, err := s.Transactions.Send(
+			ctx,
 			serviceAcc,
 			tests.TransactionArgString.Source,
 			"",
 			gasLimit,
+			DefaultGasMargin,
 			args,
 			"",
+			flow.TransactionStatusSealed,
 		)
 
 		assert.NoError(t, err)
@@ -95,6 +138,274 @@ func TestTransactions(t *testing.T) {
 		gw.Mock.AssertNumberOfCalls(t, tests.GetTransactionResultFunc, 1)
 	})
 
+	t.Run("Send Transaction with Proposer Key Pool", func(t *testing.T) {
+		t.Parallel()
+		_, s, gw := setup()
+
+		onChain := &flow.Account{
+			Address: serviceAddress,
+			Keys: []*flow.AccountKey{
+				{Index: 0, SequenceNumber: 1},
+				{Index: 1, SequenceNumber: 3},
+			},
+		}
+		pool, err := flowkit.NewProposerKeyPool(serviceAcc, onChain, []int{1})
+		assert.NoError(t, err)
+		s.Transactions.SetProposerKeyPool(pool)
+
+		// the transaction was received, but sending it timed out
+		gw.SendSignedTransaction.Run(func(args mock.Arguments) {
+			tx := args.Get(1).(*flowkit.Transaction)
+			assert.Equal(t, uint64(3), tx.FlowTransaction().ProposalKey.SequenceNumber)
+			gw.SendSignedTransaction.Return(nil, context.DeadlineExceeded)
+		})
+		gw.GetAccount.Run(func(args mock.Arguments) {
+			onChain.Keys[1].SequenceNumber = 4
+			gw.GetAccount.Return(onChain, nil)
+		})
+
+		_, _, err = s.Transactions.SendWithRoles(
+			ctx,
+			serviceAcc,
+			[]*flowkit.Account{serviceAcc},
+			serviceAcc,
+			tests.TransactionSingleAuth.Source,
+			tests.TransactionSingleAuth.Filename,
+			gasLimit,
+			DefaultGasMargin,
+			nil,
+			"",
+			flow.TransactionStatusSealed,
+		)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// the proposer account is only fetched for the on-chain sequence number after the ambiguous send
+		gw.Mock.AssertNumberOfCalls(t, tests.GetAccountFunc, 1)
+		key, err := pool.Acquire(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(4), key.SequenceNumber)
+	})
+
+	t.Run("Sign With Multiple Keys", func(t *testing.T) {
+		t.Parallel()
+		_, s, gw := setup()
+
+		address := flow.HexToAddress("01")
+		onChain := &flow.Account{Address: address}
+		treasury := flowkit.NewAccount("treasury").SetAddress(address)
+		for i := 0; i < 3; i++ {
+			pk, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, []byte(strings.Repeat(fmt.Sprint(i), 32)))
+			assert.NoError(t, err)
+
+			onChain.Keys = append(onChain.Keys, &flow.AccountKey{
+				Index:     i,
+				PublicKey: pk.PublicKey(),
+				SigAlgo:   crypto.ECDSA_P256,
+				HashAlgo:  crypto.SHA3_256,
+				Weight:    334,
+			})
+			treasury.AddKey(flowkit.NewHexAccountKeyFromPrivateKey(i, crypto.SHA3_256, pk))
+		}
+		gw.GetAccount.Run(func(args mock.Arguments) {
+			gw.GetAccount.Return(onChain, nil)
+		})
+
+		tx := flowkit.NewTransaction()
+		err := tx.SetProposer(onChain, 0)
+		assert.NoError(t, err)
+		tx.SetPayer(address)
+		tx.FlowTransaction().AddAuthorizer(address)
+
+		payload := []byte(fmt.Sprintf("%x", tx.FlowTransaction().Encode()))
+		for i, expectedWeight := range []int{334, 668, 1002} {
+			signed, err := s.Transactions.SignWithKey(treasury, i, payload, true)
+			assert.NoError(t, err)
+			payload = []byte(fmt.Sprintf("%x", signed.FlowTransaction().Encode()))
+
+			weights, err := s.Transactions.SignatureWeights(ctx, signed)
+			assert.NoError(t, err)
+			assert.Len(t, weights, 1)
+			assert.Equal(t, []string{RoleProposer, RoleAuthorizer, RolePayer}, weights[0].Roles)
+			assert.Equal(t, expectedWeight, weights[0].Weight)
+			assert.True(t, weights[0].ProposalKeySigned)
+			assert.Equal(t, expectedWeight >= flow.AccountKeyWeightThreshold, weights[0].Complete())
+
+			if i == 1 {
+				_, _, err = s.Transactions.SendSignedWithContext(ctx, payload, true, flow.TransactionStatusSealed)
+				assert.EqualError(t, err, fmt.Sprintf(
+					"transaction is not signed with enough weight to be sent:\naccount %s (proposer, authorizer, payer) has signature weight 668 of the required 1000",
+					address,
+				))
+			}
+		}
+
+		_, err = s.Transactions.SignWithKey(treasury, 1, payload, true)
+		assert.EqualError(t, err, "transaction is already signed by account 0000000000000001 with key index 1")
+
+		_, err = s.Transactions.SignWithKey(treasury, 5, payload, true)
+		assert.EqualError(t, err, "account treasury has no key with index 5")
+		gw.Mock.AssertNotCalled(t, tests.SendSignedTransactionFunc, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Build Transaction Offline", func(t *testing.T) {
+		t.Parallel()
+		state, s, gw := setup()
+
+		state.Contracts().AddOrUpdate(tests.ContractHelloString.Name, config.Contract{
+			Name:    tests.ContractHelloString.Name,
+			Source:  tests.ContractHelloString.Filename,
+			Network: "testnet",
+			Alias:   "9a0766d93b6608b7",
+		})
+
+		block := tests.NewBlock()
+		onChain := tests.NewAccountWithAddress(serviceAddress.String())
+		onChain.Keys[0].SequenceNumber = 11
+		reference, err := flowkit.NewTransactionReference(block, onChain, 0)
+		assert.NoError(t, err)
+
+		tx, err := s.Transactions.BuildOffline(
+			reference,
+			[]flow.Address{serviceAddress},
+			serviceAddress,
+			tests.TransactionImports.Source,
+			tests.TransactionImports.Filename,
+			gasLimit,
+			nil,
+			"testnet",
+			true,
+		)
+		assert.NoError(t, err)
+
+		ftx := tx.FlowTransaction()
+		assert.Equal(t, block.ID, ftx.ReferenceBlockID)
+		assert.Equal(t, flow.ProposalKey{Address: serviceAddress, KeyIndex: 0, SequenceNumber: 11}, ftx.ProposalKey)
+		assert.Contains(t, string(ftx.Script), "import Hello from 0x9a0766d93b6608b7")
+		gw.Mock.AssertNotCalled(t, tests.GetLatestBlockFunc, mock.Anything)
+		gw.Mock.AssertNotCalled(t, tests.GetAccountFunc, mock.Anything, mock.Anything)
+
+		_, err = s.Transactions.BuildOffline(
+			reference,
+			[]flow.Address{serviceAddress},
+			serviceAddress,
+			tests.TransactionImports.Source,
+			tests.TransactionImports.Filename,
+			0,
+			nil,
+			"testnet",
+			true,
+		)
+		assert.EqualError(t, err, "gas limit can't be estimated when building offline, specify the gas limit")
+	})
+
+	t.Run("Replay Transaction At Block Height", func(t *testing.T) {
+		t.Parallel()
+		_, s, gw := setup()
+
+		tx := tests.NewTransaction()
+		gw.GetTransaction.Return(tx, nil)
+
+		result := tests.NewTransactionResult(nil)
+		result.Status = flow.TransactionStatusSealed
+		result.BlockHeight = 10
+		gw.GetTransactionResult.Return(result, nil)
+
+		replay, err := s.Transactions.Replay(ctx, tx.ID())
+		assert.NoError(t, err)
+		assert.Equal(t, result, replay.Result)
+		for _, authorizer := range tx.Authorizers {
+			gw.Mock.AssertCalled(t, tests.GetAccountAtBlockHeightFunc, mock.Anything, authorizer, uint64(9))
+		}
+		gw.Mock.AssertNotCalled(t, tests.GetAccountFunc, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Replay Pending Transaction", func(t *testing.T) {
+		t.Parallel()
+		_, s, gw := setup()
+
+		result := tests.NewTransactionResult(nil)
+		result.Status = flow.TransactionStatusPending
+		gw.GetTransactionResult.Return(result, nil)
+
+		tx := tests.NewTransaction()
+		_, err := s.Transactions.Replay(ctx, tx.ID())
+		assert.EqualError(
+			t,
+			err,
+			fmt.Sprintf("transaction %s is pending, only executed transactions can be replayed", tx.ID()),
+		)
+	})
+
+	t.Run("Replay Differences", func(t *testing.T) {
+		replay := &TransactionReplay{
+			Result: &flow.TransactionResult{
+				Events: []flow.Event{
+					{Type: "A.01cf0e2f2f715450.ContractEvents.EventA"},
+					{Type: "flow.AccountContractAdded"},
+				},
+			},
+			Replayed: &types.TransactionResult{
+				Error: errors.New("panic"),
+				Events: []flow.Event{
+					{Type: "A.f8d6e0586b0a20c7.ContractEvents.EventA"},
+				},
+			},
+		}
+
+		assert.Equal(t, []ReplayDifference{
+			{Field: "status", Result: "succeeded", Replayed: "failed"},
+			{Field: "event 1", Result: "flow.AccountContractAdded", Replayed: ""},
+		}, replay.Differences())
+	})
+
+	t.Run("Replay Field And Error Differences", func(t *testing.T) {
+		networkAddress := flow.HexToAddress("01cf0e2f2f715450")
+		emulatorAddress := flow.HexToAddress("179b6b1cb6755e31")
+
+		eventType := &cadence.EventType{
+			QualifiedIdentifier: "ContractEvents.Deposited",
+			Fields: []cadence.Field{
+				{Identifier: "to", Type: cadence.AddressType{}},
+				{Identifier: "amount", Type: cadence.UInt64Type{}},
+			},
+		}
+		event := func(typeID string, address flow.Address, amount uint64) flow.Event {
+			return flow.Event{
+				Type: typeID,
+				Value: cadence.NewEvent([]cadence.Value{
+					cadence.NewAddress(address),
+					cadence.NewUInt64(amount),
+				}).WithType(eventType),
+			}
+		}
+
+		replay := &TransactionReplay{
+			Result: &flow.TransactionResult{
+				Error: fmt.Errorf("account %s has no vault", networkAddress.Hex()),
+				Events: []flow.Event{
+					event("A.01cf0e2f2f715450.ContractEvents.Deposited", networkAddress, 10),
+				},
+			},
+			Replayed: &types.TransactionResult{
+				Error: fmt.Errorf("account %s has no vault", emulatorAddress.Hex()),
+				Events: []flow.Event{
+					event("A.179b6b1cb6755e31.ContractEvents.Deposited", emulatorAddress, 20),
+				},
+			},
+			Addresses: map[flow.Address]flow.Address{emulatorAddress: networkAddress},
+		}
+
+		assert.Equal(t, []ReplayDifference{
+			{Field: "event 0 amount", Result: "10", Replayed: "20"},
+		}, replay.Differences())
+
+		replay.Replayed.Error = errors.New("out of computation")
+		assert.Equal(t, []ReplayDifference{
+			{Field: "error", Result: "account 01cf0e2f2f715450 has no vault", Replayed: "out of computation"},
+			{Field: "event 0 amount", Result: "10", Replayed: "20"},
+		}, replay.Differences())
+	})
+
 }
 
 func setupAccounts(state *flowkit.State, s *Services) {
@@ -108,12 +419,14 @@ func setupAccount(state *flowkit.State, s *Services, account *flowkit.Account) {
 
 	key := account.Key()
 	pk, _ := key.PrivateKey()
-	acc, _ := s.Accounts.Create(srv,
+	acc, _ := s.Accounts.CreateWithContext(ctx, srv,
 		[]crypto.PublicKey{(*pk).PublicKey()},
 		[]int{flow.AccountKeyWeightThreshold},
 		[]crypto.SignatureAlgorithm{key.SigAlgo()},
 		[]crypto.HashAlgorithm{key.HashAlgo()},
 		nil,
+		flowkit.MaxGasLimit,
+		DefaultGasMargin,
 	)
 
 	newAcc := flowkit.
@@ -174,7 +487,7 @@ func TestTransactions_Integration(t *testing.T) {
 		}}
 
 		for _, i := range txIns {
-			tx, err := s.Transactions.Build(i.prop, i.auth, i.payer, i.index, i.code, i.file, i.gas, i.args, i.network, i.yes)
+			tx, err := s.Transactions.BuildWithContext(ctx, i.prop, i.auth, i.payer, i.index, i.code, i.file, i.gas, DefaultGasMargin, i.args, i.network, i.yes)
 
 			assert.NoError(t, err)
 			ftx := tx.FlowTransaction()
@@ -218,13 +531,15 @@ func TestTransactions_Integration(t *testing.T) {
 			}},
 		}
 		state.Deployments().AddOrUpdate(d)
-		_, _ = s.Accounts.AddContract(
+		_, _ = s.Accounts.AddContractWithContext(
+			ctx,
 			srvAcc,
 			resourceToContract(tests.ContractHelloString),
 			false,
 		)
 
-		tx, err := s.Transactions.Build(
+		tx, err := s.Transactions.BuildWithContext(
+			ctx,
 			signer,
 			[]flow.Address{signer},
 			signer,
@@ -232,6 +547,7 @@ func TestTransactions_Integration(t *testing.T) {
 			tests.TransactionImports.Source,
 			tests.TransactionImports.Filename,
 			1000,
+			DefaultGasMargin,
 			nil,
 			n.Name,
 			true,
@@ -256,7 +572,8 @@ func TestTransactions_Integration(t *testing.T) {
 
 		a, _ := state.Accounts().ByName("Alice")
 
-		tx, err := s.Transactions.Build(
+		tx, err := s.Transactions.BuildWithContext(
+			ctx,
 			a.Address(),
 			nil,
 			a.Address(),
@@ -264,6 +581,7 @@ func TestTransactions_Integration(t *testing.T) {
 			tests.TransactionSimple.Source,
 			tests.TransactionSimple.Filename,
 			1000,
+			DefaultGasMargin,
 			nil,
 			"",
 			true,
@@ -293,7 +611,8 @@ func TestTransactions_Integration(t *testing.T) {
 
 		a, _ := state.Accounts().ByName("Alice")
 
-		tx, err := s.Transactions.Build(
+		tx, err := s.Transactions.BuildWithContext(
+			ctx,
 			a.Address(),
 			[]flow.Address{a.Address()},
 			a.Address(),
@@ -301,6 +620,7 @@ func TestTransactions_Integration(t *testing.T) {
 			tests.TransactionSingleAuth.Source,
 			tests.TransactionSingleAuth.Filename,
 			1000,
+			DefaultGasMargin,
 			nil,
 			"",
 			true,
@@ -317,9 +637,11 @@ func TestTransactions_Integration(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, txSigned)
 
-		txSent, txResult, err := s.Transactions.SendSigned(
+		txSent, txResult, err := s.Transactions.SendSignedWithContext(
+			ctx,
 			[]byte(fmt.Sprintf("%x", txSigned.FlowTransaction().Encode())),
 			true,
+			flow.TransactionStatusSealed,
 		)
 		assert.Nil(t, err)
 		assert.Equal(t, txResult.Status, flow.TransactionStatusSealed)
@@ -334,7 +656,8 @@ func TestTransactions_Integration(t *testing.T) {
 
 		a, _ := state.Accounts().ByName("Alice")
 
-		tx, err := s.Transactions.Build(
+		tx, err := s.Transactions.BuildWithContext(
+			ctx,
 			a.Address(),
 			[]flow.Address{a.Address()},
 			a.Address(),
@@ -342,6 +665,7 @@ func TestTransactions_Integration(t *testing.T) {
 			tests.TransactionSingleAuth.Source,
 			tests.TransactionSingleAuth.Filename,
 			1000,
+			DefaultGasMargin,
 			nil,
 			"",
 			true,
@@ -369,7 +693,8 @@ func TestTransactions_Integration(t *testing.T) {
 
 		a, _ := state.Accounts().ByName("Alice")
 
-		tx, err := s.Transactions.Build(
+		tx, err := s.Transactions.BuildWithContext(
+			ctx,
 			a.Address(),
 			[]flow.Address{a.Address()},
 			a.Address(),
@@ -377,6 +702,7 @@ func TestTransactions_Integration(t *testing.T) {
 			tests.TransactionTwoAuth.Source,
 			tests.TransactionTwoAuth.Filename,
 			1000,
+			DefaultGasMargin,
 			nil,
 			"",
 			true,
@@ -394,12 +720,15 @@ func TestTransactions_Integration(t *testing.T) {
 		a, _ := state.Accounts().ByName("Alice")
 
 		tx, txr, err := s.Transactions.Send(
+			ctx,
 			a,
 			tests.TransactionSingleAuth.Source,
 			tests.TransactionSingleAuth.Filename,
 			1000,
+			DefaultGasMargin,
 			nil,
 			"",
+			flow.TransactionStatusSealed,
 		)
 		assert.NoError(t, err)
 		assert.Equal(t, tx.Payer.String(), a.Address().String())
@@ -408,6 +737,148 @@ func TestTransactions_Integration(t *testing.T) {
 		assert.Equal(t, txr.Status, flow.TransactionStatusSealed)
 	})
 
+	t.Run("Send Transaction with Roles", func(t *testing.T) {
+		t.Parallel()
+		state, s := setupIntegration()
+		setupAccounts(state, s)
+
+		a, _ := state.Accounts().ByName("Alice")
+		b, _ := state.Accounts().ByName("Bob")
+		c, _ := state.Accounts().ByName("Charlie")
+
+		tx, txr, err := s.Transactions.SendWithRoles(
+			ctx,
+			a,
+			[]*flowkit.Account{a, b},
+			c,
+			tests.TransactionTwoAuth.Source,
+			tests.TransactionTwoAuth.Filename,
+			1000,
+			DefaultGasMargin,
+			nil,
+			"",
+			flow.TransactionStatusSealed,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, a.Address(), tx.ProposalKey.Address)
+		assert.Equal(t, []flow.Address{a.Address(), b.Address()}, tx.Authorizers)
+		assert.Equal(t, c.Address(), tx.Payer)
+		assert.Len(t, tx.PayloadSignatures, 2)
+		assert.Len(t, tx.EnvelopeSignatures, 1)
+		assert.Nil(t, txr.Error)
+		assert.Equal(t, txr.Status, flow.TransactionStatusSealed)
+	})
+
+	t.Run("Send Transactions with Proposer Key Pool", func(t *testing.T) {
+		t.Parallel()
+		state, s := setupIntegration()
+		setupAccounts(state, s)
+
+		a, _ := state.Accounts().ByName("Alice")
+		b, _ := state.Accounts().ByName("Bob")
+
+		pool, err := s.Accounts.AddProposerKeys(ctx, a, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, pool.Size())
+		s.Transactions.SetProposerKeyPool(pool)
+
+		var keyIndexes []int
+		for i := 0; i < 4; i++ {
+			tx, txr, err := s.Transactions.SendWithRoles(
+				ctx,
+				a,
+				[]*flowkit.Account{b},
+				b,
+				tests.TransactionSingleAuth.Source,
+				tests.TransactionSingleAuth.Filename,
+				1000,
+				DefaultGasMargin,
+				nil,
+				"",
+				flow.TransactionStatusSealed,
+			)
+			assert.NoError(t, err)
+			assert.Nil(t, txr.Error)
+			assert.Equal(t, uint64(i/2), tx.ProposalKey.SequenceNumber)
+			keyIndexes = append(keyIndexes, tx.ProposalKey.KeyIndex)
+		}
+
+		assert.Equal(t, []int{1, 2, 1, 2}, keyIndexes)
+	})
+
+	t.Run("Send Transaction with Estimated Gas Limit", func(t *testing.T) {
+		t.Parallel()
+		state, s := setupIntegration()
+
+		srvAcc, _ := state.EmulatorServiceAccount()
+
+		c := config.Contract{
+			Name:    tests.ContractHelloString.Name,
+			Source:  tests.ContractHelloString.Filename,
+			Network: "emulator",
+		}
+		state.Contracts().AddOrUpdate(c.Name, c)
+
+		n := config.Network{
+			Name: "emulator",
+			Host: "127.0.0.1:3569",
+		}
+		state.Networks().AddOrUpdate(n.Name, n)
+
+		state.Deployments().AddOrUpdate(config.Deployment{
+			Network: n.Name,
+			Account: srvAcc.Name(),
+			Contracts: []config.ContractDeployment{{
+				Name: c.Name,
+			}},
+		})
+		_, err := s.Accounts.AddContractWithContext(ctx, srvAcc, resourceToContract(tests.ContractHelloString), false)
+		assert.NoError(t, err)
+
+		// the imported contract is copied to the emulator the gas limit is estimated on
+		tx, txr, err := s.Transactions.Send(
+			ctx,
+			srvAcc,
+			tests.TransactionImports.Source,
+			tests.TransactionImports.Filename,
+			0,
+			DefaultGasMargin,
+			nil,
+			n.Name,
+			flow.TransactionStatusSealed,
+		)
+		assert.NoError(t, err)
+		assert.Nil(t, txr.Error)
+		assert.Greater(t, tx.GasLimit, uint64(0))
+		assert.Less(t, tx.GasLimit, flowkit.MaxGasLimit)
+	})
+
+	t.Run("Send Transaction with Estimated Gas Limit Importing a Core Contract Name", func(t *testing.T) {
+		t.Parallel()
+		state, s := setupIntegration()
+		setupAccounts(state, s)
+
+		a, _ := state.Accounts().ByName("Alice")
+
+		// the contract shares the name of a core contract, so it must be copied instead of using the core contract
+		contract := &Contract{
+			Name:   "FungibleToken",
+			Source: []byte(`pub contract FungibleToken { pub fun hello(): String { return "Hello" } }`),
+		}
+		_, err := s.Accounts.AddContractWithContext(ctx, a, contract, false)
+		require.NoError(t, err)
+
+		code := []byte(fmt.Sprintf(`
+			import FungibleToken from 0x%s
+			transaction { prepare(signer: AuthAccount) { log(FungibleToken.hello()) } }
+		`, a.Address()))
+
+		tx, txr, err := s.Transactions.Send(ctx, a, code, "", 0, DefaultGasMargin, nil, "", flow.TransactionStatusSealed)
+		require.NoError(t, err)
+		assert.Nil(t, txr.Error)
+		assert.Less(t, tx.GasLimit, flowkit.MaxGasLimit)
+	})
+
 	t.Run("Send Transaction with arguments", func(t *testing.T) {
 		t.Parallel()
 		state, s := setupIntegration()
@@ -419,12 +890,15 @@ func TestTransactions_Integration(t *testing.T) {
 		}
 
 		tx, txr, err := s.Transactions.Send(
+			ctx,
 			a,
 			tests.TransactionArgString.Source,
 			tests.TransactionArgString.Filename,
 			1000,
+			DefaultGasMargin,
 			args,
 			"",
+			flow.TransactionStatusSealed,
 		)
 		assert.NoError(t, err)
 		assert.Equal(t, tx.Payer.String(), a.Address().String())
@@ -442,12 +916,15 @@ func TestTransactions_Integration(t *testing.T) {
 		a, _ := state.Accounts().ByName("Alice")
 
 		tx, txr, err := s.Transactions.Send(
+			ctx,
 			a,
 			tests.TransactionMultipleDeclarations.Source,
 			tests.TransactionMultipleDeclarations.Filename,
 			1000,
+			DefaultGasMargin,
 			nil,
 			"",
+			flow.TransactionStatusSealed,
 		)
 		assert.NoError(t, err)
 		assert.Equal(t, tx.Payer.String(), a.Address().String())
@@ -455,4 +932,32 @@ func TestTransactions_Integration(t *testing.T) {
 		assert.Nil(t, txr.Error)
 		assert.Equal(t, txr.Status, flow.TransactionStatusSealed)
 	})
+
+	t.Run("Replay Transaction", func(t *testing.T) {
+		t.Parallel()
+		state, s := setupIntegration()
+		setupAccounts(state, s)
+
+		a, _ := state.Accounts().ByName("Alice")
+
+		tx, _, err := s.Transactions.Send(
+			ctx,
+			a,
+			tests.TransactionArgString.Source,
+			tests.TransactionArgString.Filename,
+			1000,
+			DefaultGasMargin,
+			[]cadence.Value{cadence.String("Bar")},
+			"",
+			flow.TransactionStatusSealed,
+		)
+		assert.NoError(t, err)
+
+		replay, err := s.Transactions.Replay(ctx, tx.ID())
+		assert.NoError(t, err)
+		assert.Nil(t, replay.Replayed.Error)
+		assert.Len(t, replay.Replayed.Logs, 1)
+		assert.Contains(t, replay.Replayed.Logs[0], "Bar,0x")
+		assert.Len(t, replay.Differences(), 0)
+	})
 }
@@ -27,32 +27,40 @@ import (
 )
 
 const (
-	GetAccountFunc            = "GetAccount"
-	SendSignedTransactionFunc = "SendSignedTransaction"
-	GetCollectionFunc         = "GetCollection"
-	GetTransactionResultFunc  = "GetTransactionResult"
-	GetEventsFunc             = "GetEvents"
-	GetLatestBlockFunc        = "GetLatestBlock"
-	GetBlockByHeightFunc      = "GetBlockByHeight"
-	GetBlockByIDFunc          = "GetBlockByID"
-	ExecuteScriptFunc         = "ExecuteScript"
-	GetTransactionFunc        = "GetTransaction"
+	GetAccountFunc                 = "GetAccount"
+	SendSignedTransactionFunc      = "SendSignedTransaction"
+	GetCollectionFunc              = "GetCollection"
+	GetTransactionResultFunc       = "GetTransactionResult"
+	GetEventsFunc                  = "GetEvents"
+	GetLatestBlockFunc             = "GetLatestBlock"
+	GetBlockByHeightFunc           = "GetBlockByHeight"
+	GetBlockByIDFunc               = "GetBlockByID"
+	ExecuteScriptFunc              = "ExecuteScript"
+	GetTransactionFunc             = "GetTransaction"
+	GetAccountAtBlockHeightFunc    = "GetAccountAtBlockHeight"
+	GetAccountAtBlockIDFunc        = "GetAccountAtBlockID"
+	ExecuteScriptAtBlockHeightFunc = "ExecuteScriptAtBlockHeight"
+	ExecuteScriptAtBlockIDFunc     = "ExecuteScriptAtBlockID"
 )
 
 // go:generate
 
 type TestGateway struct {
-	Mock                  *mocks.Gateway
-	SendSignedTransaction *mock.Call
-	GetAccount            *mock.Call
-	GetCollection         *mock.Call
-	GetTransactionResult  *mock.Call
-	GetEvents             *mock.Call
-	GetLatestBlock        *mock.Call
-	GetBlockByHeight      *mock.Call
-	GetBlockByID          *mock.Call
-	ExecuteScript         *mock.Call
-	GetTransaction        *mock.Call
+	Mock                       *mocks.Gateway
+	SendSignedTransaction      *mock.Call
+	GetAccount                 *mock.Call
+	GetCollection              *mock.Call
+	GetTransactionResult       *mock.Call
+	GetEvents                  *mock.Call
+	GetLatestBlock             *mock.Call
+	GetBlockByHeight           *mock.Call
+	GetBlockByID               *mock.Call
+	ExecuteScript              *mock.Call
+	GetTransaction             *mock.Call
+	GetAccountAtBlockHeight    *mock.Call
+	GetAccountAtBlockID        *mock.Call
+	ExecuteScriptAtBlockHeight *mock.Call
+	ExecuteScriptAtBlockID     *mock.Call
 }
 
 func DefaultMockGateway() *TestGateway {
@@ -61,27 +69,33 @@ func DefaultMockGateway() *TestGateway {
 		Mock: m,
 		SendSignedTransaction: m.On(
 			SendSignedTransactionFunc,
+			mock.Anything,
 			mock.AnythingOfType("*flowkit.Transaction"),
 		),
 		GetAccount: m.On(
 			GetAccountFunc,
+			mock.Anything,
 			mock.AnythingOfType("flow.Address"),
 		),
 		GetCollection: m.On(
 			GetCollectionFunc,
+			mock.Anything,
 			mock.AnythingOfType("flow.Identifier"),
 		),
 		GetTransactionResult: m.On(
 			GetTransactionResultFunc,
+			mock.Anything,
 			mock.AnythingOfType("flow.Identifier"),
 			mock.AnythingOfType("bool"),
 		),
 		GetTransaction: m.On(
 			GetTransactionFunc,
+			mock.Anything,
 			mock.AnythingOfType("flow.Identifier"),
 		),
 		GetEvents: m.On(
 			GetEventsFunc,
+			mock.Anything,
 			mock.AnythingOfType("string"),
 			mock.AnythingOfType("uint64"),
 			mock.AnythingOfType("uint64"),
@@ -90,10 +104,37 @@ func DefaultMockGateway() *TestGateway {
 			ExecuteScriptFunc,
 			mock.Anything,
 			mock.Anything,
+			mock.Anything,
+		),
+		GetAccountAtBlockHeight: m.On(
+			GetAccountAtBlockHeightFunc,
+			mock.Anything,
+			mock.AnythingOfType("flow.Address"),
+			mock.AnythingOfType("uint64"),
+		),
+		GetAccountAtBlockID: m.On(
+			GetAccountAtBlockIDFunc,
+			mock.Anything,
+			mock.AnythingOfType("flow.Address"),
+			mock.AnythingOfType("flow.Identifier"),
+		),
+		ExecuteScriptAtBlockHeight: m.On(
+			ExecuteScriptAtBlockHeightFunc,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.AnythingOfType("uint64"),
 		),
-		GetBlockByHeight: m.On(GetBlockByHeightFunc, mock.Anything),
-		GetBlockByID:     m.On(GetBlockByIDFunc, mock.Anything),
-		GetLatestBlock:   m.On(GetLatestBlockFunc),
+		ExecuteScriptAtBlockID: m.On(
+			ExecuteScriptAtBlockIDFunc,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.AnythingOfType("flow.Identifier"),
+		),
+		GetBlockByHeight: m.On(GetBlockByHeightFunc, mock.Anything, mock.Anything),
+		GetBlockByID:     m.On(GetBlockByIDFunc, mock.Anything, mock.Anything),
+		GetLatestBlock:   m.On(GetLatestBlockFunc, mock.Anything),
 	}
 
 	// default return values
@@ -102,10 +143,23 @@ func DefaultMockGateway() *TestGateway {
 	})
 
 	t.GetAccount.Run(func(args mock.Arguments) {
-		addr := args.Get(0).(flow.Address)
+		addr := args.Get(1).(flow.Address)
 		t.GetAccount.Return(NewAccountWithAddress(addr.String()), nil)
 	})
 
+	t.GetAccountAtBlockHeight.Run(func(args mock.Arguments) {
+		addr := args.Get(1).(flow.Address)
+		t.GetAccountAtBlockHeight.Return(NewAccountWithAddress(addr.String()), nil)
+	})
+
+	t.GetAccountAtBlockID.Run(func(args mock.Arguments) {
+		addr := args.Get(1).(flow.Address)
+		t.GetAccountAtBlockID.Return(NewAccountWithAddress(addr.String()), nil)
+	})
+
+	t.ExecuteScriptAtBlockHeight.Return(cadence.MustConvertValue(""), nil)
+	t.ExecuteScriptAtBlockID.Return(cadence.MustConvertValue(""), nil)
+
 	t.ExecuteScript.Run(func(args mock.Arguments) {
 		t.ExecuteScript.Return(cadence.MustConvertValue(""), nil)
 	})
@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	context "context"
+
 	cadence "github.com/onflow/cadence"
+
 	flow "github.com/onflow/flow-go-sdk"
 
 	flowkit "github.com/onflow/flow-cli/pkg/flowkit"
@@ -16,13 +19,59 @@ type Gateway struct {
 	mock.Mock
 }
 
-// ExecuteScript provides a mock function with given fields: _a0, _a1
-func (_m *Gateway) ExecuteScript(_a0 []byte, _a1 []cadence.Value) (cadence.Value, error) {
-	ret := _m.Called(_a0, _a1)
+// ExecuteScript provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Gateway) ExecuteScript(_a0 context.Context, _a1 []byte, _a2 []cadence.Value) (cadence.Value, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 cadence.Value
-	if rf, ok := ret.Get(0).(func([]byte, []cadence.Value) cadence.Value); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []cadence.Value) cadence.Value); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(cadence.Value)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, []cadence.Value) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExecuteScriptAtBlockHeight provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Gateway) ExecuteScriptAtBlockHeight(_a0 context.Context, _a1 []byte, _a2 []cadence.Value, _a3 uint64) (cadence.Value, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 cadence.Value
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []cadence.Value, uint64) cadence.Value); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(cadence.Value)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, []cadence.Value, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExecuteScriptAtBlockID provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Gateway) ExecuteScriptAtBlockID(_a0 context.Context, _a1 []byte, _a2 []cadence.Value, _a3 flow.Identifier) (cadence.Value, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 cadence.Value
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []cadence.Value, flow.Identifier) cadence.Value); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(cadence.Value)
@@ -30,7 +79,30 @@ func (_m *Gateway) ExecuteScript(_a0 []byte, _a1 []cadence.Value) (cadence.Value
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func([]byte, []cadence.Value) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, []cadence.Value, flow.Identifier) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccount provides a mock function with given fields: _a0, _a1
+func (_m *Gateway) GetAccount(_a0 context.Context, _a1 flow.Address) (*flow.Account, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *flow.Account
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address) *flow.Account); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.Account)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -39,13 +111,13 @@ func (_m *Gateway) ExecuteScript(_a0 []byte, _a1 []cadence.Value) (cadence.Value
 	return r0, r1
 }
 
-// GetAccount provides a mock function with given fields: _a0
-func (_m *Gateway) GetAccount(_a0 flow.Address) (*flow.Account, error) {
-	ret := _m.Called(_a0)
+// GetAccountAtBlockHeight provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Gateway) GetAccountAtBlockHeight(_a0 context.Context, _a1 flow.Address, _a2 uint64) (*flow.Account, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 *flow.Account
-	if rf, ok := ret.Get(0).(func(flow.Address) *flow.Account); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, uint64) *flow.Account); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*flow.Account)
@@ -53,8 +125,8 @@ func (_m *Gateway) GetAccount(_a0 flow.Address) (*flow.Account, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(flow.Address) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -62,13 +134,36 @@ func (_m *Gateway) GetAccount(_a0 flow.Address) (*flow.Account, error) {
 	return r0, r1
 }
 
-// GetBlockByHeight provides a mock function with given fields: _a0
-func (_m *Gateway) GetBlockByHeight(_a0 uint64) (*flow.Block, error) {
-	ret := _m.Called(_a0)
+// GetAccountAtBlockID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Gateway) GetAccountAtBlockID(_a0 context.Context, _a1 flow.Address, _a2 flow.Identifier) (*flow.Account, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *flow.Account
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, flow.Identifier) *flow.Account); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.Account)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, flow.Identifier) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlockByHeight provides a mock function with given fields: _a0, _a1
+func (_m *Gateway) GetBlockByHeight(_a0 context.Context, _a1 uint64) (*flow.Block, error) {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 *flow.Block
-	if rf, ok := ret.Get(0).(func(uint64) *flow.Block); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *flow.Block); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*flow.Block)
@@ -76,8 +171,8 @@ func (_m *Gateway) GetBlockByHeight(_a0 uint64) (*flow.Block, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint64) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -85,13 +180,13 @@ func (_m *Gateway) GetBlockByHeight(_a0 uint64) (*flow.Block, error) {
 	return r0, r1
 }
 
-// GetBlockByID provides a mock function with given fields: _a0
-func (_m *Gateway) GetBlockByID(_a0 flow.Identifier) (*flow.Block, error) {
-	ret := _m.Called(_a0)
+// GetBlockByID provides a mock function with given fields: _a0, _a1
+func (_m *Gateway) GetBlockByID(_a0 context.Context, _a1 flow.Identifier) (*flow.Block, error) {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 *flow.Block
-	if rf, ok := ret.Get(0).(func(flow.Identifier) *flow.Block); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) *flow.Block); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*flow.Block)
@@ -99,8 +194,8 @@ func (_m *Gateway) GetBlockByID(_a0 flow.Identifier) (*flow.Block, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(flow.Identifier) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -108,13 +203,13 @@ func (_m *Gateway) GetBlockByID(_a0 flow.Identifier) (*flow.Block, error) {
 	return r0, r1
 }
 
-// GetCollection provides a mock function with given fields: _a0
-func (_m *Gateway) GetCollection(_a0 flow.Identifier) (*flow.Collection, error) {
-	ret := _m.Called(_a0)
+// GetCollection provides a mock function with given fields: _a0, _a1
+func (_m *Gateway) GetCollection(_a0 context.Context, _a1 flow.Identifier) (*flow.Collection, error) {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 *flow.Collection
-	if rf, ok := ret.Get(0).(func(flow.Identifier) *flow.Collection); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) *flow.Collection); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*flow.Collection)
@@ -122,8 +217,8 @@ func (_m *Gateway) GetCollection(_a0 flow.Identifier) (*flow.Collection, error)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(flow.Identifier) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -131,13 +226,13 @@ func (_m *Gateway) GetCollection(_a0 flow.Identifier) (*flow.Collection, error)
 	return r0, r1
 }
 
-// GetEvents provides a mock function with given fields: _a0, _a1, _a2
-func (_m *Gateway) GetEvents(_a0 string, _a1 uint64, _a2 uint64) ([]flow.BlockEvents, error) {
-	ret := _m.Called(_a0, _a1, _a2)
+// GetEvents provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Gateway) GetEvents(_a0 context.Context, _a1 string, _a2 uint64, _a3 uint64) ([]flow.BlockEvents, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
 
 	var r0 []flow.BlockEvents
-	if rf, ok := ret.Get(0).(func(string, uint64, uint64) []flow.BlockEvents); ok {
-		r0 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, uint64) []flow.BlockEvents); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]flow.BlockEvents)
@@ -145,8 +240,8 @@ func (_m *Gateway) GetEvents(_a0 string, _a1 uint64, _a2 uint64) ([]flow.BlockEv
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, uint64, uint64) error); ok {
-		r1 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -154,13 +249,13 @@ func (_m *Gateway) GetEvents(_a0 string, _a1 uint64, _a2 uint64) ([]flow.BlockEv
 	return r0, r1
 }
 
-// GetLatestBlock provides a mock function with given fields:
-func (_m *Gateway) GetLatestBlock() (*flow.Block, error) {
-	ret := _m.Called()
+// GetLatestBlock provides a mock function with given fields: _a0
+func (_m *Gateway) GetLatestBlock(_a0 context.Context) (*flow.Block, error) {
+	ret := _m.Called(_a0)
 
 	var r0 *flow.Block
-	if rf, ok := ret.Get(0).(func() *flow.Block); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) *flow.Block); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*flow.Block)
@@ -168,8 +263,8 @@ func (_m *Gateway) GetLatestBlock() (*flow.Block, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -177,13 +272,13 @@ func (_m *Gateway) GetLatestBlock() (*flow.Block, error) {
 	return r0, r1
 }
 
-// GetLatestProtocolStateSnapshot provides a mock function with given fields:
-func (_m *Gateway) GetLatestProtocolStateSnapshot() ([]byte, error) {
-	ret := _m.Called()
+// GetLatestProtocolStateSnapshot provides a mock function with given fields: _a0
+func (_m *Gateway) GetLatestProtocolStateSnapshot(_a0 context.Context) ([]byte, error) {
+	ret := _m.Called(_a0)
 
 	var r0 []byte
-	if rf, ok := ret.Get(0).(func() []byte); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []byte); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
@@ -191,8 +286,8 @@ func (_m *Gateway) GetLatestProtocolStateSnapshot() ([]byte, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -200,13 +295,13 @@ func (_m *Gateway) GetLatestProtocolStateSnapshot() ([]byte, error) {
 	return r0, r1
 }
 
-// GetTransaction provides a mock function with given fields: _a0
-func (_m *Gateway) GetTransaction(_a0 flow.Identifier) (*flow.Transaction, error) {
-	ret := _m.Called(_a0)
+// GetTransaction provides a mock function with given fields: _a0, _a1
+func (_m *Gateway) GetTransaction(_a0 context.Context, _a1 flow.Identifier) (*flow.Transaction, error) {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 *flow.Transaction
-	if rf, ok := ret.Get(0).(func(flow.Identifier) *flow.Transaction); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) *flow.Transaction); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*flow.Transaction)
@@ -214,8 +309,8 @@ func (_m *Gateway) GetTransaction(_a0 flow.Identifier) (*flow.Transaction, error
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(flow.Identifier) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -223,13 +318,13 @@ func (_m *Gateway) GetTransaction(_a0 flow.Identifier) (*flow.Transaction, error
 	return r0, r1
 }
 
-// GetTransactionResult provides a mock function with given fields: _a0, _a1
-func (_m *Gateway) GetTransactionResult(_a0 flow.Identifier, _a1 bool) (*flow.TransactionResult, error) {
-	ret := _m.Called(_a0, _a1)
+// GetTransactionResult provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Gateway) GetTransactionResult(_a0 context.Context, _a1 flow.Identifier, _a2 bool) (*flow.TransactionResult, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 *flow.TransactionResult
-	if rf, ok := ret.Get(0).(func(flow.Identifier, bool) *flow.TransactionResult); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier, bool) *flow.TransactionResult); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*flow.TransactionResult)
@@ -237,8 +332,8 @@ func (_m *Gateway) GetTransactionResult(_a0 flow.Identifier, _a1 bool) (*flow.Tr
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(flow.Identifier, bool) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -246,13 +341,13 @@ func (_m *Gateway) GetTransactionResult(_a0 flow.Identifier, _a1 bool) (*flow.Tr
 	return r0, r1
 }
 
-// Ping provides a mock function with given fields:
-func (_m *Gateway) Ping() error {
-	ret := _m.Called()
+// Ping provides a mock function with given fields: _a0
+func (_m *Gateway) Ping(_a0 context.Context) error {
+	ret := _m.Called(_a0)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(_a0)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -274,13 +369,13 @@ func (_m *Gateway) SecureConnection() bool {
 	return r0
 }
 
-// SendSignedTransaction provides a mock function with given fields: _a0
-func (_m *Gateway) SendSignedTransaction(_a0 *flowkit.Transaction) (*flow.Transaction, error) {
-	ret := _m.Called(_a0)
+// SendSignedTransaction provides a mock function with given fields: _a0, _a1
+func (_m *Gateway) SendSignedTransaction(_a0 context.Context, _a1 *flowkit.Transaction) (*flow.Transaction, error) {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 *flow.Transaction
-	if rf, ok := ret.Get(0).(func(*flowkit.Transaction) *flow.Transaction); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *flowkit.Transaction) *flow.Transaction); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*flow.Transaction)
@@ -288,8 +383,8 @@ func (_m *Gateway) SendSignedTransaction(_a0 *flowkit.Transaction) (*flow.Transa
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*flowkit.Transaction) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, *flowkit.Transaction) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
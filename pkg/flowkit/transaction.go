@@ -28,11 +28,13 @@ import (
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/cmd"
 	"github.com/onflow/cadence/runtime/common"
+	tmpl "github.com/onflow/flow-core-contracts/lib/go/templates"
 	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/templates"
 )
 
-const maxGasLimit uint64 = 9999
+// MaxGasLimit is the maximum gas limit of a transaction.
+const MaxGasLimit uint64 = 9999
 
 // NewTransaction create new instance of transaction.
 func NewTransaction() *Transaction {
@@ -42,7 +44,18 @@ func NewTransaction() *Transaction {
 }
 
 // NewTransactionFromPayload build transaction from payload.
+//
+// The payload is either a hex encoded RLP transaction or a JSON transaction bundle.
 func NewTransactionFromPayload(payload []byte) (*Transaction, error) {
+	if IsTransactionBundle(payload) {
+		bundle, err := ParseTransactionBundle(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		return bundle.Transaction()
+	}
+
 	partialTxBytes, err := hex.DecodeString(string(payload))
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode partial transaction from %s: %v", payload, err)
@@ -127,7 +140,7 @@ func addAccountContractWithArgs(
 
 	script := fmt.Sprintf(addAccountContractTemplate, txArgs, addArgs)
 	tx.SetScript([]byte(script))
-	tx.SetGasLimit(maxGasLimit)
+	tx.SetGasLimit(MaxGasLimit)
 
 	t := &Transaction{tx: tx}
 	err := t.SetSigner(signer)
@@ -153,6 +166,145 @@ func NewCreateAccountTransaction(
 	return newTransactionFromTemplate(template, signer)
 }
 
+// NewAddProposerKeysTransaction creates new transaction adding count copies of the key to the signer account.
+func NewAddProposerKeysTransaction(signer *Account, key *flow.AccountKey, count int) (*Transaction, error) {
+	const addProposerKeysTemplate = `
+	import Crypto
+
+	transaction(key: Crypto.KeyListEntry, count: Int) {
+		prepare(signer: AuthAccount) {
+			var i = 0
+			while i < count {
+				signer.keys.add(publicKey: key.publicKey, hashAlgorithm: key.hashAlgorithm, weight: key.weight)
+				i = i + 1
+			}
+		}
+	}`
+
+	cadenceKey, err := templates.AccountKeyToCadenceCryptoKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := flow.NewTransaction().
+		SetScript([]byte(addProposerKeysTemplate)).
+		AddRawArgument(jsoncdc.MustEncode(cadenceKey)).
+		AddRawArgument(jsoncdc.MustEncode(cadence.NewInt(count))).
+		AddAuthorizer(signer.Address())
+
+	return newTransactionFromTemplate(tx, signer)
+}
+
+// NewAddAccountKeyTransaction creates a transaction adding the key to the signer account.
+func NewAddAccountKeyTransaction(signer *Account, key *flow.AccountKey) (*Transaction, error) {
+	const addAccountKeyTemplate = `
+	import Crypto
+
+	transaction(key: Crypto.KeyListEntry) {
+		prepare(signer: AuthAccount) {
+			signer.keys.add(publicKey: key.publicKey, hashAlgorithm: key.hashAlgorithm, weight: key.weight)
+		}
+	}`
+
+	cadenceKey, err := templates.AccountKeyToCadenceCryptoKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := flow.NewTransaction().
+		SetScript([]byte(addAccountKeyTemplate)).
+		AddRawArgument(jsoncdc.MustEncode(cadenceKey)).
+		AddAuthorizer(signer.Address())
+
+	return newTransactionFromTemplate(tx, signer)
+}
+
+// NewRevokeAccountKeyTransaction creates a transaction revoking the key at the index from the signer account.
+func NewRevokeAccountKeyTransaction(signer *Account, index int) (*Transaction, error) {
+	const revokeAccountKeyTemplate = `
+	transaction(keyIndex: Int) {
+		prepare(signer: AuthAccount) {
+			signer.keys.revoke(keyIndex: keyIndex) ?? panic("account key doesn't exist")
+		}
+	}`
+
+	tx := flow.NewTransaction().
+		SetScript([]byte(revokeAccountKeyTemplate)).
+		AddRawArgument(jsoncdc.MustEncode(cadence.NewInt(index))).
+		AddAuthorizer(signer.Address())
+
+	return newTransactionFromTemplate(tx, signer)
+}
+
+// NewRotateAccountKeyTransaction creates a transaction adding the key to the signer account and
+// revoking the key at the index, so the key is replaced atomically.
+func NewRotateAccountKeyTransaction(signer *Account, key *flow.AccountKey, revokeIndex int) (*Transaction, error) {
+	const rotateAccountKeyTemplate = `
+	import Crypto
+
+	transaction(key: Crypto.KeyListEntry, revokeIndex: Int) {
+		prepare(signer: AuthAccount) {
+			signer.keys.add(publicKey: key.publicKey, hashAlgorithm: key.hashAlgorithm, weight: key.weight)
+			signer.keys.revoke(keyIndex: revokeIndex) ?? panic("account key doesn't exist")
+		}
+	}`
+
+	cadenceKey, err := templates.AccountKeyToCadenceCryptoKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := flow.NewTransaction().
+		SetScript([]byte(rotateAccountKeyTemplate)).
+		AddRawArgument(jsoncdc.MustEncode(cadenceKey)).
+		AddRawArgument(jsoncdc.MustEncode(cadence.NewInt(revokeIndex))).
+		AddAuthorizer(signer.Address())
+
+	return newTransactionFromTemplate(tx, signer)
+}
+
+// NewMintFlowTokensTransaction creates a transaction minting FLOW tokens to the recipient,
+// the signer must be the FlowToken administrator of the network environment.
+func NewMintFlowTokensTransaction(
+	signer *Account,
+	env tmpl.Environment,
+	recipient flow.Address,
+	amount cadence.UFix64,
+) (*Transaction, error) {
+	const mintFlowTokensTemplate = `
+	import FungibleToken from 0xFUNGIBLETOKENADDRESS
+	import FlowToken from 0xFLOWTOKENADDRESS
+
+	transaction(recipient: Address, amount: UFix64) {
+		let tokenAdmin: &FlowToken.Administrator
+		let tokenReceiver: &{FungibleToken.Receiver}
+
+		prepare(signer: AuthAccount) {
+			self.tokenAdmin = signer.borrow<&FlowToken.Administrator>(from: /storage/flowTokenAdmin)
+				?? panic("signer is not the FlowToken administrator")
+
+			self.tokenReceiver = getAccount(recipient)
+				.getCapability(/public/flowTokenReceiver)
+				.borrow<&{FungibleToken.Receiver}>()
+				?? panic("recipient doesn't have a FlowToken receiver")
+		}
+
+		execute {
+			let minter <- self.tokenAdmin.createNewMinter(allowedAmount: amount)
+			self.tokenReceiver.deposit(from: <-minter.mintTokens(amount: amount))
+			destroy minter
+		}
+	}`
+
+	tx := flow.NewTransaction().
+		SetScript([]byte(tmpl.ReplaceAddresses(mintFlowTokensTemplate, env))).
+		AddRawArgument(jsoncdc.MustEncode(cadence.NewAddress(recipient))).
+		AddRawArgument(jsoncdc.MustEncode(amount)).
+		AddAuthorizer(signer.Address())
+
+	return newTransactionFromTemplate(tx, signer)
+}
+
 func newTransactionFromTemplate(templateTx *flow.Transaction, signer *Account) (*Transaction, error) {
 	tx := &Transaction{tx: templateTx}
 
@@ -161,16 +313,17 @@ func newTransactionFromTemplate(templateTx *flow.Transaction, signer *Account) (
 		return nil, err
 	}
 	tx.SetPayer(signer.Address())
-	tx.SetGasLimit(maxGasLimit) // todo change this to calculated limit
+	tx.SetGasLimit(MaxGasLimit) // the services lower it to the estimated gas limit when estimation is requested
 
 	return tx, nil
 }
 
 // Transaction builder of flow transactions.
 type Transaction struct {
-	signer   *Account
-	proposer *flow.Account
-	tx       *flow.Transaction
+	signer    *Account
+	signerKey AccountKey
+	proposer  *flow.Account
+	tx        *flow.Transaction
 }
 
 // Signer get signer.
@@ -193,9 +346,29 @@ func (t *Transaction) SetScriptWithArgs(script []byte, args []cadence.Value) err
 	return t.AddArguments(args)
 }
 
-// SetSigner sets the signer for transaction.
+// SetSigner sets the signer for transaction, the transaction is signed with the default account key.
 func (t *Transaction) SetSigner(account *Account) error {
-	err := account.Key().Validate()
+	return t.setSigner(account, account.Key())
+}
+
+// SetSignerWithKey sets the signer for transaction, the transaction is signed with the account key at the key index.
+//
+// Use it to add partial signatures to a transaction signed by multiple keys of the same account.
+func (t *Transaction) SetSignerWithKey(account *Account, keyIndex int) error {
+	key, err := account.KeyByIndex(keyIndex)
+	if err != nil {
+		return err
+	}
+
+	return t.setSigner(account, key)
+}
+
+func (t *Transaction) setSigner(account *Account, key AccountKey) error {
+	if key == nil {
+		return fmt.Errorf("account %s has no key", account.Name())
+	}
+
+	err := key.Validate()
 	if err != nil {
 		return err
 	}
@@ -211,6 +384,7 @@ func (t *Transaction) SetSigner(account *Account) error {
 	}
 
 	t.signer = account
+	t.signerKey = key
 	return nil
 }
 
@@ -334,8 +508,17 @@ func (t *Transaction) AddAuthorizers(authorizers []flow.Address) (*Transaction,
 
 // Sign signs transaction using signer account.
 func (t *Transaction) Sign() (*Transaction, error) {
-	keyIndex := t.signer.Key().Index()
-	signer, err := t.signer.Key().Signer(context.Background())
+	key := t.signerKey
+	if key == nil {
+		key = t.signer.Key()
+	}
+
+	keyIndex := key.Index()
+	if t.hasSignature(t.signer.address, keyIndex) {
+		return nil, fmt.Errorf("transaction is already signed by account %s with key index %d", t.signer.address, keyIndex)
+	}
+
+	signer, err := key.Signer(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -355,6 +538,22 @@ func (t *Transaction) Sign() (*Transaction, error) {
 	return t, nil
 }
 
+// hasSignature checks whether the transaction is already signed by the account key.
+func (t *Transaction) hasSignature(address flow.Address, keyIndex int) bool {
+	signatures := t.tx.PayloadSignatures
+	if t.shouldSignEnvelope() {
+		signatures = t.tx.EnvelopeSignatures
+	}
+
+	for _, sig := range signatures {
+		if sig.Address == address && sig.KeyIndex == keyIndex {
+			return true
+		}
+	}
+
+	return false
+}
+
 // shouldSignEnvelope checks if signer should sign envelope or payload
 func (t *Transaction) shouldSignEnvelope() bool {
 	return t.signer.address == t.tx.Payer
@@ -0,0 +1,304 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go-sdk"
+)
+
+// transactionBundleVersion is the version of the transaction bundle format.
+const transactionBundleVersion = 1
+
+// TransactionBundle is a self-describing JSON representation of a transaction, used as an
+// alternative to the hex encoded RLP payload passed between the build, sign and send steps.
+//
+// The bundle holds every field of the transaction, so it converts to the same RLP
+// payload and the collected signatures stay valid. Account names are informational,
+// they are resolved from the configuration when the bundle is created.
+type TransactionBundle struct {
+	Version            int                        `json:"version"`
+	Code               string                     `json:"code"`
+	Arguments          []json.RawMessage          `json:"arguments"`
+	ReferenceBlockID   string                     `json:"referenceBlockId"`
+	GasLimit           uint64                     `json:"gasLimit"`
+	Proposer           TransactionBundleProposer  `json:"proposer"`
+	Payer              TransactionBundleAccount   `json:"payer"`
+	Authorizers        []TransactionBundleAccount `json:"authorizers"`
+	PayloadSignatures  []TransactionBundleSig     `json:"payloadSignatures"`
+	EnvelopeSignatures []TransactionBundleSig     `json:"envelopeSignatures"`
+}
+
+// TransactionBundleAccount is an account with a role in the bundled transaction.
+type TransactionBundleAccount struct {
+	Address string `json:"address"`
+	Name    string `json:"name,omitempty"`
+}
+
+// TransactionBundleProposer is the proposer account and proposal key of the bundled transaction.
+type TransactionBundleProposer struct {
+	TransactionBundleAccount
+	KeyIndex       int    `json:"keyIndex"`
+	SequenceNumber uint64 `json:"sequenceNumber"`
+}
+
+// TransactionBundleSig is a signature collected for the bundled transaction.
+type TransactionBundleSig struct {
+	TransactionBundleAccount
+	SignerIndex int    `json:"signerIndex"`
+	KeyIndex    int    `json:"keyIndex"`
+	Signature   string `json:"signature"`
+}
+
+// NewTransactionBundle creates a bundle of the transaction, the account names are resolved from the accounts if provided.
+//
+// An error is returned if the transaction can't be represented losslessly, e.g. if the arguments
+// aren't encoded the way the Flow SDK encodes them.
+func NewTransactionBundle(tx *Transaction, accounts *Accounts) (*TransactionBundle, error) {
+	flowTx := tx.FlowTransaction()
+
+	if !utf8.Valid(flowTx.Script) {
+		return nil, fmt.Errorf("transaction code is not valid UTF-8 and can't be bundled")
+	}
+
+	bundleAccount := func(address flow.Address) TransactionBundleAccount {
+		account := TransactionBundleAccount{Address: address.Hex()}
+		if accounts != nil {
+			if acc, err := accounts.ByAddress(address); err == nil {
+				account.Name = acc.Name()
+			}
+		}
+		return account
+	}
+
+	bundleSigs := func(sigs []flow.TransactionSignature) []TransactionBundleSig {
+		bundled := make([]TransactionBundleSig, 0, len(sigs))
+		for _, sig := range sigs {
+			bundled = append(bundled, TransactionBundleSig{
+				TransactionBundleAccount: bundleAccount(sig.Address),
+				SignerIndex:              sig.SignerIndex,
+				KeyIndex:                 sig.KeyIndex,
+				Signature:                hex.EncodeToString(sig.Signature),
+			})
+		}
+		return bundled
+	}
+
+	arguments := make([]json.RawMessage, 0, len(flowTx.Arguments))
+	for _, arg := range flowTx.Arguments {
+		arguments = append(arguments, bytes.TrimSpace(arg))
+	}
+
+	authorizers := make([]TransactionBundleAccount, 0, len(flowTx.Authorizers))
+	for _, authorizer := range flowTx.Authorizers {
+		authorizers = append(authorizers, bundleAccount(authorizer))
+	}
+
+	bundle := &TransactionBundle{
+		Version:          transactionBundleVersion,
+		Code:             string(flowTx.Script),
+		Arguments:        arguments,
+		ReferenceBlockID: flowTx.ReferenceBlockID.Hex(),
+		GasLimit:         flowTx.GasLimit,
+		Proposer: TransactionBundleProposer{
+			TransactionBundleAccount: bundleAccount(flowTx.ProposalKey.Address),
+			KeyIndex:                 flowTx.ProposalKey.KeyIndex,
+			SequenceNumber:           flowTx.ProposalKey.SequenceNumber,
+		},
+		Payer:              bundleAccount(flowTx.Payer),
+		Authorizers:        authorizers,
+		PayloadSignatures:  bundleSigs(flowTx.PayloadSignatures),
+		EnvelopeSignatures: bundleSigs(flowTx.EnvelopeSignatures),
+	}
+
+	// make sure the bundle converts back to exactly the same transaction
+	converted, err := bundle.FlowTransaction()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(converted.Encode(), flowTx.Encode()) {
+		return nil, fmt.Errorf("transaction can't be bundled without changing its encoding, use the RLP payload instead")
+	}
+
+	return bundle, nil
+}
+
+// ParseTransactionBundle parses the JSON encoded bundle.
+func ParseTransactionBundle(data []byte) (*TransactionBundle, error) {
+	var bundle TransactionBundle
+	err := json.Unmarshal(data, &bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transaction bundle: %w", err)
+	}
+
+	if bundle.Version != transactionBundleVersion {
+		return nil, fmt.Errorf("unsupported transaction bundle version %d", bundle.Version)
+	}
+
+	return &bundle, nil
+}
+
+// FlowTransaction converts the bundle to the transaction it represents.
+//
+// The arguments are re-encoded the way the Flow SDK encodes them, so formatting
+// changes to the bundle file don't change the transaction and invalidate the signatures.
+func (b *TransactionBundle) FlowTransaction() (*flow.Transaction, error) {
+	tx := flow.NewTransaction().
+		SetScript([]byte(b.Code)).
+		SetGasLimit(b.GasLimit)
+
+	for i, arg := range b.Arguments {
+		value, err := jsoncdc.Decode(nil, arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode argument %d of the transaction bundle: %w", i, err)
+		}
+
+		encoded, err := jsoncdc.Encode(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode argument %d of the transaction bundle: %w", i, err)
+		}
+		tx.AddRawArgument(encoded)
+	}
+
+	referenceBlockID, err := ParseID(b.ReferenceBlockID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference block ID in the transaction bundle: %w", err)
+	}
+	tx.SetReferenceBlockID(referenceBlockID)
+
+	proposer, err := hexToAddress(b.Proposer.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proposer in the transaction bundle: %w", err)
+	}
+	tx.SetProposalKey(proposer, b.Proposer.KeyIndex, b.Proposer.SequenceNumber)
+
+	payer, err := hexToAddress(b.Payer.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payer in the transaction bundle: %w", err)
+	}
+	tx.SetPayer(payer)
+
+	for _, authorizer := range b.Authorizers {
+		address, err := hexToAddress(authorizer.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid authorizer in the transaction bundle: %w", err)
+		}
+		tx.AddAuthorizer(address)
+	}
+
+	tx.PayloadSignatures, err = bundleSigsToFlow(b.PayloadSignatures)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.EnvelopeSignatures, err = bundleSigsToFlow(b.EnvelopeSignatures)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// Transaction converts the bundle to a flowkit transaction.
+func (b *TransactionBundle) Transaction() (*Transaction, error) {
+	tx, err := b.FlowTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{tx: tx}, nil
+}
+
+// MarshalIndent returns the bundle as indented JSON.
+func (b *TransactionBundle) MarshalIndent() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// IsTransactionBundle checks whether the payload is a JSON transaction bundle instead of a hex encoded RLP payload.
+func IsTransactionBundle(payload []byte) bool {
+	trimmed := bytes.TrimSpace(payload)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+func bundleSigsToFlow(sigs []TransactionBundleSig) ([]flow.TransactionSignature, error) {
+	var flowSigs []flow.TransactionSignature
+	for _, sig := range sigs {
+		address, err := hexToAddress(sig.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature address in the transaction bundle: %w", err)
+		}
+
+		signature, err := hex.DecodeString(sig.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature in the transaction bundle: %w", err)
+		}
+
+		flowSigs = append(flowSigs, flow.TransactionSignature{
+			Address:     address,
+			SignerIndex: sig.SignerIndex,
+			KeyIndex:    sig.KeyIndex,
+			Signature:   signature,
+		})
+	}
+
+	return flowSigs, nil
+}
+
+func hexToAddress(value string) (flow.Address, error) {
+	b, err := hex.DecodeString(trimHexPrefix(value))
+	if err != nil || len(b) != flow.AddressLength {
+		return flow.EmptyAddress, fmt.Errorf("invalid address %s", value)
+	}
+
+	return flow.BytesToAddress(b), nil
+}
+
+// ParseID parses a hex encoded identifier, with or without the 0x prefix,
+// unlike flow.HexToID it returns an error if the value isn't valid hex or has the wrong length.
+func ParseID(value string) (flow.Identifier, error) {
+	b, err := hex.DecodeString(trimHexPrefix(value))
+	if err != nil || len(b) != len(flow.EmptyID) {
+		return flow.EmptyID, fmt.Errorf("invalid identifier %s", value)
+	}
+
+	return flow.BytesToID(b), nil
+}
+
+func trimHexPrefix(value string) string {
+	if len(value) > 2 && value[:2] == "0x" {
+		return value[2:]
+	}
+	return value
+}
@@ -0,0 +1,125 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+)
+
+func TestTransactionBundle(t *testing.T) {
+	proposer := flow.HexToAddress("01cf0e2f2f715450")
+	payer := flow.HexToAddress("f8d6e0586b0a20c7")
+
+	flowTx := flow.NewTransaction().
+		SetScript([]byte(`transaction(greeting: String, amount: UFix64) { prepare(signer: AuthAccount) { log("<" + greeting + ">") } }`)).
+		SetReferenceBlockID(flow.HexToID("7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7")).
+		SetGasLimit(1000).
+		SetProposalKey(proposer, 1, 42).
+		SetPayer(payer).
+		AddAuthorizer(proposer)
+
+	amount, _ := cadence.NewUFix64("10.5")
+	require.NoError(t, flowTx.AddArgument(cadence.String("Hello & welcome")))
+	require.NoError(t, flowTx.AddArgument(amount))
+
+	flowTx.AddPayloadSignature(proposer, 1, []byte{1, 2, 3})
+	flowTx.AddEnvelopeSignature(payer, 0, []byte{4, 5, 6})
+
+	tx, err := flowkit.NewTransactionFromPayload([]byte(hex.EncodeToString(flowTx.Encode())))
+	require.NoError(t, err)
+
+	accounts := &flowkit.Accounts{}
+	accounts.AddOrUpdate(flowkit.NewAccount("alice").SetAddress(proposer))
+
+	t.Run("Round Trip", func(t *testing.T) {
+		bundle, err := flowkit.NewTransactionBundle(tx, accounts)
+		require.NoError(t, err)
+
+		assert.Equal(t, "alice", bundle.Proposer.Name)
+		assert.Equal(t, "alice", bundle.Authorizers[0].Name)
+		assert.Equal(t, "", bundle.Payer.Name)
+		assert.Len(t, bundle.Arguments, 2)
+		assert.Equal(t, "010203", bundle.PayloadSignatures[0].Signature)
+
+		data, err := bundle.MarshalIndent()
+		require.NoError(t, err)
+		assert.True(t, flowkit.IsTransactionBundle(data))
+
+		decoded, err := flowkit.NewTransactionFromPayload(data)
+		require.NoError(t, err)
+		assert.Equal(t, flowTx.Encode(), decoded.FlowTransaction().Encode())
+		assert.Equal(t, flowTx.ID(), decoded.FlowTransaction().ID())
+	})
+
+	t.Run("Reformatted Arguments", func(t *testing.T) {
+		bundle, err := flowkit.NewTransactionBundle(tx, nil)
+		require.NoError(t, err)
+
+		// html escaping and indentation of the arguments must not change the transaction
+		data, err := json.MarshalIndent(bundle, "", "    ")
+		require.NoError(t, err)
+		assert.True(t, bytes.Contains(data, []byte(`\u0026`)))
+
+		decoded, err := flowkit.NewTransactionFromPayload(data)
+		require.NoError(t, err)
+		assert.Equal(t, flowTx.Encode(), decoded.FlowTransaction().Encode())
+	})
+
+	t.Run("Unsupported Version", func(t *testing.T) {
+		_, err := flowkit.NewTransactionFromPayload([]byte(`{"version": 2}`))
+		assert.EqualError(t, err, "unsupported transaction bundle version 2")
+	})
+
+	t.Run("Invalid Address", func(t *testing.T) {
+		bundle, err := flowkit.NewTransactionBundle(tx, nil)
+		require.NoError(t, err)
+
+		bundle.Payer.Address = "0x1234"
+		_, err = bundle.FlowTransaction()
+		assert.EqualError(t, err, "invalid payer in the transaction bundle: invalid address 0x1234")
+	})
+}
+
+func TestParseID(t *testing.T) {
+	id := flow.HexToID("a1b2")
+
+	parsed, err := flowkit.ParseID(id.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, id, parsed)
+
+	parsed, err = flowkit.ParseID("0x" + id.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, id, parsed)
+
+	_, err = flowkit.ParseID("a1b2")
+	assert.EqualError(t, err, "invalid identifier a1b2")
+
+	_, err = flowkit.ParseID("zz")
+	assert.EqualError(t, err, "invalid identifier zz")
+}
@@ -0,0 +1,339 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/spf13/afero"
+)
+
+// DefaultTransactionHistoryPath is the path of the transaction history file, relative to the project configuration.
+const DefaultTransactionHistoryPath = ".flow-history.ndjson"
+
+// TransactionHistoryStatusFailed is the status filter matching the transactions that failed with an error.
+const TransactionHistoryStatusFailed = "failed"
+
+// TransactionHistoryEntry is a transaction sent from the project, with the latest known status.
+type TransactionHistoryEntry struct {
+	ID          string            `json:"id"`
+	Network     string            `json:"network"`
+	SentAt      time.Time         `json:"sentAt"`
+	Proposer    string            `json:"proposer"`
+	Payer       string            `json:"payer"`
+	Authorizers []string          `json:"authorizers"`
+	Signers     []string          `json:"signers"`
+	CodeHash    string            `json:"codeHash"`
+	Arguments   []json.RawMessage `json:"arguments"`
+	Status      string            `json:"status"`
+	Error       string            `json:"error,omitempty"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// NewTransactionHistoryEntry creates a new pending history entry of the transaction sent to the network.
+func NewTransactionHistoryEntry(network string, tx *flow.Transaction, sentAt time.Time) *TransactionHistoryEntry {
+	authorizers := make([]string, 0, len(tx.Authorizers))
+	for _, authorizer := range tx.Authorizers {
+		authorizers = append(authorizers, authorizer.Hex())
+	}
+
+	signers := make([]string, 0)
+	seen := make(map[flow.Address]bool)
+	for _, sig := range append(append([]flow.TransactionSignature{}, tx.PayloadSignatures...), tx.EnvelopeSignatures...) {
+		if !seen[sig.Address] {
+			seen[sig.Address] = true
+			signers = append(signers, sig.Address.Hex())
+		}
+	}
+
+	arguments := make([]json.RawMessage, 0, len(tx.Arguments))
+	for _, arg := range tx.Arguments {
+		arguments = append(arguments, bytes.TrimSpace(arg))
+	}
+
+	codeHash := sha256.Sum256(tx.Script)
+
+	return &TransactionHistoryEntry{
+		ID:          tx.ID().Hex(),
+		Network:     network,
+		SentAt:      sentAt,
+		Proposer:    tx.ProposalKey.Address.Hex(),
+		Payer:       tx.Payer.Hex(),
+		Authorizers: authorizers,
+		Signers:     signers,
+		CodeHash:    hex.EncodeToString(codeHash[:]),
+		Arguments:   arguments,
+		Status:      strings.ToLower(flow.TransactionStatusPending.String()),
+		UpdatedAt:   sentAt,
+	}
+}
+
+// hasAccount checks whether the account has a role in the transaction or signed it.
+func (e *TransactionHistoryEntry) hasAccount(address string) bool {
+	address = strings.TrimPrefix(address, "0x")
+	accounts := append([]string{e.Proposer, e.Payer}, e.Authorizers...)
+	for _, account := range append(accounts, e.Signers...) {
+		if account == address {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Final checks whether the status of the transaction can't change anymore.
+func (e *TransactionHistoryEntry) Final() bool {
+	return e.Status == strings.ToLower(flow.TransactionStatusSealed.String()) ||
+		e.Status == strings.ToLower(flow.TransactionStatusExpired.String())
+}
+
+// TransactionHistoryFilter selects history entries, empty fields match every entry.
+type TransactionHistoryFilter struct {
+	Network string
+	// Account is the address of an account with a role in the transaction or a signer of it.
+	Account string
+	Since   time.Time
+	Until   time.Time
+	// Status is the transaction status, or TransactionHistoryStatusFailed to match the transactions with an error.
+	Status string
+}
+
+// Match checks whether the filter selects the entry.
+func (f TransactionHistoryFilter) Match(entry *TransactionHistoryEntry) bool {
+	if f.Network != "" && entry.Network != f.Network {
+		return false
+	}
+	if f.Account != "" && !entry.hasAccount(f.Account) {
+		return false
+	}
+	if !f.Since.IsZero() && entry.SentAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && entry.SentAt.After(f.Until) {
+		return false
+	}
+	if f.Status == TransactionHistoryStatusFailed {
+		return entry.Error != ""
+	}
+	if f.Status != "" && entry.Status != strings.ToLower(f.Status) {
+		return false
+	}
+
+	return true
+}
+
+// TransactionHistory is a per-project store of the sent transactions, written as newline delimited JSON.
+//
+// The history is only ever appended to: new entries are appended when the transactions are sent and
+// status changes are appended as status records of the entry, which are applied when the history is read.
+// Each record is appended with a single write, so processes sharing the history don't overwrite each other.
+type TransactionHistory struct {
+	fs   afero.Fs
+	path string
+	mu   sync.Mutex
+	// statuses are the latest status and error of the entries read on the first update and the entries added since
+	statuses map[string]transactionHistoryStatus
+}
+
+// transactionHistoryStatus is a status record, appended when the status of a history entry changes.
+type transactionHistoryStatus struct {
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// NewTransactionHistory returns the transaction history stored at the path.
+func NewTransactionHistory(fs afero.Fs, path string) *TransactionHistory {
+	return &TransactionHistory{
+		fs:   fs,
+		path: path,
+	}
+}
+
+// Add adds the entry to the end of the history.
+func (h *TransactionHistory) Add(entry *TransactionHistoryEntry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	err := h.append(entry)
+	if err != nil {
+		return err
+	}
+
+	if h.statuses != nil {
+		h.statuses[entry.ID] = transactionHistoryStatus{ID: entry.ID, Status: entry.Status, Error: entry.Error}
+	}
+
+	return nil
+}
+
+// Update updates the status of the history entry with the result, results of transactions not in the history are ignored.
+//
+// The history is read on the first update only, so polling the results of transactions which aren't in the history
+// doesn't read it again. Entries added to this history afterwards are updated too.
+func (h *TransactionHistory) Update(id flow.Identifier, result *flow.TransactionResult, updatedAt time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	status := transactionHistoryStatus{
+		ID:        id.Hex(),
+		Status:    strings.ToLower(result.Status.String()),
+		UpdatedAt: updatedAt,
+	}
+	if result.Error != nil {
+		status.Error = result.Error.Error()
+	}
+
+	if h.statuses == nil {
+		err := h.loadStatuses()
+		if err != nil {
+			return err
+		}
+	}
+
+	// the history is only read once, entries added by other processes afterwards are updated by those processes
+	current, ok := h.statuses[status.ID]
+	if !ok {
+		return nil
+	}
+
+	if current.Status == status.Status && current.Error == status.Error {
+		return nil
+	}
+
+	err := h.append(status)
+	if err != nil {
+		return err
+	}
+
+	h.statuses[status.ID] = status
+	return nil
+}
+
+// Search returns the history entries selected by the filter, in the order they were sent.
+func (h *TransactionHistory) Search(filter TransactionHistoryFilter) ([]*TransactionHistoryEntry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries, err := h.read()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]*TransactionHistoryEntry, 0)
+	for _, entry := range entries {
+		if filter.Match(entry) {
+			matched = append(matched, entry)
+		}
+	}
+
+	return matched, nil
+}
+
+func (h *TransactionHistory) loadStatuses() error {
+	entries, err := h.read()
+	if err != nil {
+		return err
+	}
+
+	h.statuses = make(map[string]transactionHistoryStatus, len(entries))
+	for _, entry := range entries {
+		h.statuses[entry.ID] = transactionHistoryStatus{ID: entry.ID, Status: entry.Status, Error: entry.Error}
+	}
+
+	return nil
+}
+
+// read reads the history entries with the status records applied.
+func (h *TransactionHistory) read() ([]*TransactionHistoryEntry, error) {
+	data, err := afero.ReadFile(h.fs, h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transaction history: %w", err)
+	}
+
+	var entries []*TransactionHistoryEntry
+	byID := make(map[string]*TransactionHistoryEntry)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var entry TransactionHistoryEntry
+		err := json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse transaction history %s at line %d: %w", h.path, line, err)
+		}
+
+		// status records only have the status fields, records of unknown entries are ignored
+		if entry.SentAt.IsZero() {
+			if existing, ok := byID[entry.ID]; ok {
+				existing.Status = entry.Status
+				existing.Error = entry.Error
+				existing.UpdatedAt = entry.UpdatedAt
+			}
+			continue
+		}
+
+		byID[entry.ID] = &entry
+		entries = append(entries, &entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// append appends the record to the history with a single write.
+func (h *TransactionHistory) append(record interface{}) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	file, err := h.fs.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write transaction history: %w", err)
+	}
+
+	_, err = file.Write(append(line, '\n'))
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write transaction history: %w", err)
+	}
+
+	return nil
+}
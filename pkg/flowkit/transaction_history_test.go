@@ -0,0 +1,198 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+)
+
+func TestTransactionHistory(t *testing.T) {
+	alice := flow.HexToAddress("01cf0e2f2f715450")
+	bob := flow.HexToAddress("179b6b1cb6755e31")
+	sentAt := time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC)
+
+	newTransaction := func(t *testing.T, authorizer flow.Address) *flow.Transaction {
+		arg, err := jsoncdc.Encode(cadence.String("Foo"))
+		require.NoError(t, err)
+
+		tx := flow.NewTransaction().
+			SetScript([]byte(`transaction(greeting: String) {}`)).
+			SetProposalKey(alice, 0, 1).
+			SetPayer(alice).
+			AddAuthorizer(authorizer).
+			AddRawArgument(arg)
+		tx.AddEnvelopeSignature(alice, 0, []byte{1})
+
+		return tx
+	}
+
+	newHistory := func(t *testing.T) *flowkit.TransactionHistory {
+		history := flowkit.NewTransactionHistory(afero.NewMemMapFs(), flowkit.DefaultTransactionHistoryPath)
+
+		require.NoError(t, history.Add(flowkit.NewTransactionHistoryEntry("emulator", newTransaction(t, alice), sentAt)))
+		require.NoError(t, history.Add(flowkit.NewTransactionHistoryEntry("testnet", newTransaction(t, bob), sentAt.Add(time.Hour))))
+
+		return history
+	}
+
+	t.Run("Add Entries", func(t *testing.T) {
+		tx := newTransaction(t, alice)
+		entries, err := newHistory(t).Search(flowkit.TransactionHistoryFilter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		entry := entries[0]
+		assert.Equal(t, tx.ID().Hex(), entry.ID)
+		assert.Equal(t, "emulator", entry.Network)
+		assert.Equal(t, alice.Hex(), entry.Proposer)
+		assert.Equal(t, []string{alice.Hex()}, entry.Signers)
+		codeHash := sha256.Sum256(tx.Script)
+		assert.Equal(t, hex.EncodeToString(codeHash[:]), entry.CodeHash)
+		assert.JSONEq(t, `{"type":"String","value":"Foo"}`, string(entry.Arguments[0]))
+		assert.Equal(t, "pending", entry.Status)
+		assert.False(t, entry.Final())
+	})
+
+	t.Run("Search Entries", func(t *testing.T) {
+		history := newHistory(t)
+
+		entries, err := history.Search(flowkit.TransactionHistoryFilter{Network: "testnet"})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, []string{bob.Hex()}, entries[0].Authorizers)
+
+		entries, err = history.Search(flowkit.TransactionHistoryFilter{Account: "0x" + bob.Hex()})
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+
+		entries, err = history.Search(flowkit.TransactionHistoryFilter{Since: sentAt.Add(time.Minute)})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "testnet", entries[0].Network)
+
+		entries, err = history.Search(flowkit.TransactionHistoryFilter{Until: sentAt})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "emulator", entries[0].Network)
+	})
+
+	t.Run("Update Status", func(t *testing.T) {
+		history := newHistory(t)
+		id := newTransaction(t, alice).ID()
+
+		err := history.Update(id, &flow.TransactionResult{
+			Status: flow.TransactionStatusSealed,
+			Error:  errors.New("execution reverted"),
+		}, sentAt.Add(time.Minute))
+		require.NoError(t, err)
+
+		// results of unknown transactions are ignored
+		err = history.Update(flow.HexToID("01"), &flow.TransactionResult{Status: flow.TransactionStatusSealed}, sentAt)
+		require.NoError(t, err)
+
+		entries, err := history.Search(flowkit.TransactionHistoryFilter{Status: "sealed"})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, id.Hex(), entries[0].ID)
+		assert.Equal(t, "execution reverted", entries[0].Error)
+		assert.Equal(t, sentAt.Add(time.Minute), entries[0].UpdatedAt)
+		assert.True(t, entries[0].Final())
+
+		entries, err = history.Search(flowkit.TransactionHistoryFilter{Status: flowkit.TransactionHistoryStatusFailed})
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+
+		entries, err = history.Search(flowkit.TransactionHistoryFilter{Status: "pending"})
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("Append Status Records", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		history := flowkit.NewTransactionHistory(fs, flowkit.DefaultTransactionHistoryPath)
+		tx := newTransaction(t, alice)
+		require.NoError(t, history.Add(flowkit.NewTransactionHistoryEntry("emulator", tx, sentAt)))
+
+		sealed := &flow.TransactionResult{Status: flow.TransactionStatusSealed}
+		require.NoError(t, history.Update(tx.ID(), sealed, sentAt.Add(time.Minute)))
+		// an unchanged status isn't appended again
+		require.NoError(t, history.Update(tx.ID(), sealed, sentAt.Add(2*time.Minute)))
+
+		data, err := afero.ReadFile(fs, flowkit.DefaultTransactionHistoryPath)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 2)
+		assert.JSONEq(t, fmt.Sprintf(
+			`{"id":"%s","status":"sealed","updatedAt":"2022-10-10T12:01:00Z"}`, tx.ID().Hex(),
+		), lines[1])
+
+		// entries added by another process after the history was read are updated by that process only
+		other := flowkit.NewTransactionHistory(fs, flowkit.DefaultTransactionHistoryPath)
+		otherTx := newTransaction(t, bob)
+		require.NoError(t, other.Add(flowkit.NewTransactionHistoryEntry("emulator", otherTx, sentAt)))
+		require.NoError(t, history.Update(otherTx.ID(), sealed, sentAt.Add(time.Minute)))
+
+		entries, err := other.Search(flowkit.TransactionHistoryFilter{Status: "sealed"})
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+
+		require.NoError(t, other.Update(otherTx.ID(), sealed, sentAt.Add(time.Minute)))
+		entries, err = history.Search(flowkit.TransactionHistoryFilter{Status: "sealed"})
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("Read Once For Unknown Transactions", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		history := flowkit.NewTransactionHistory(fs, flowkit.DefaultTransactionHistoryPath)
+		tx := newTransaction(t, alice)
+		require.NoError(t, history.Add(flowkit.NewTransactionHistoryEntry("emulator", tx, sentAt)))
+
+		unknown := &flow.TransactionResult{Status: flow.TransactionStatusSealed}
+		require.NoError(t, history.Update(flow.HexToID("01"), unknown, sentAt))
+
+		// the history isn't read again, even if it can't be parsed anymore
+		require.NoError(t, afero.WriteFile(fs, flowkit.DefaultTransactionHistoryPath, []byte("invalid"), 0644))
+		require.NoError(t, history.Update(flow.HexToID("01"), unknown, sentAt))
+		require.NoError(t, history.Update(flow.HexToID("02"), unknown, sentAt))
+	})
+
+	t.Run("Invalid History", func(t *testing.T) {
+		rw := afero.Afero{Fs: afero.NewMemMapFs()}
+		require.NoError(t, rw.WriteFile(flowkit.DefaultTransactionHistoryPath, []byte("{}\nfoo\n"), 0644))
+
+		_, err := flowkit.NewTransactionHistory(rw.Fs, flowkit.DefaultTransactionHistoryPath).Search(flowkit.TransactionHistoryFilter{})
+		assert.ErrorContains(t, err, "failed to parse transaction history .flow-history.ndjson at line 2")
+	})
+}
@@ -0,0 +1,135 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// transactionReferenceVersion is the version of the transaction reference format.
+const transactionReferenceVersion = 1
+
+// TransactionExpiry is the number of blocks after the reference block in which a transaction can be included.
+const TransactionExpiry = 600
+
+// TransactionExpiryDuration is the approximate time it takes the network to produce TransactionExpiry blocks.
+const TransactionExpiryDuration = 10 * time.Minute
+
+// TransactionReference is the network data a transaction is built with, exported on an online
+// machine so the transaction can be built on a machine without network access.
+//
+// It holds the reference block and the sequence number of the proposal key, a transaction built
+// from the reference is valid until the reference block expires.
+type TransactionReference struct {
+	Version                 int                       `json:"version"`
+	ReferenceBlockID        string                    `json:"referenceBlockId"`
+	ReferenceBlockHeight    uint64                    `json:"referenceBlockHeight"`
+	ReferenceBlockTimestamp time.Time                 `json:"referenceBlockTimestamp"`
+	Proposer                TransactionBundleProposer `json:"proposer"`
+}
+
+// NewTransactionReference creates a reference of the block and the proposer account key at the key index.
+func NewTransactionReference(block *flow.Block, proposer *flow.Account, keyIndex int) (*TransactionReference, error) {
+	if keyIndex < 0 || keyIndex >= len(proposer.Keys) {
+		return nil, fmt.Errorf("failed to retrieve proposer key at index %d", keyIndex)
+	}
+
+	return &TransactionReference{
+		Version:                 transactionReferenceVersion,
+		ReferenceBlockID:        block.ID.Hex(),
+		ReferenceBlockHeight:    block.Height,
+		ReferenceBlockTimestamp: block.Timestamp.UTC(),
+		Proposer: TransactionBundleProposer{
+			TransactionBundleAccount: TransactionBundleAccount{Address: proposer.Address.Hex()},
+			KeyIndex:                 keyIndex,
+			SequenceNumber:           proposer.Keys[keyIndex].SequenceNumber,
+		},
+	}, nil
+}
+
+// ParseTransactionReference parses the JSON encoded reference.
+func ParseTransactionReference(data []byte) (*TransactionReference, error) {
+	var reference TransactionReference
+	err := json.Unmarshal(data, &reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transaction reference: %w", err)
+	}
+
+	if reference.Version != transactionReferenceVersion {
+		return nil, fmt.Errorf("unsupported transaction reference version %d", reference.Version)
+	}
+
+	if _, err := reference.BlockID(); err != nil {
+		return nil, err
+	}
+
+	if _, err := reference.ProposerAddress(); err != nil {
+		return nil, err
+	}
+
+	return &reference, nil
+}
+
+// BlockID returns the reference block ID.
+func (r *TransactionReference) BlockID() (flow.Identifier, error) {
+	id, err := ParseID(r.ReferenceBlockID)
+	if err != nil {
+		return flow.EmptyID, fmt.Errorf("invalid reference block ID in the transaction reference: %w", err)
+	}
+
+	return id, nil
+}
+
+// ProposerAddress returns the address of the proposer.
+func (r *TransactionReference) ProposerAddress() (flow.Address, error) {
+	address, err := hexToAddress(r.Proposer.Address)
+	if err != nil {
+		return flow.EmptyAddress, fmt.Errorf("invalid proposer in the transaction reference: %w", err)
+	}
+
+	return address, nil
+}
+
+// ExpiresAt returns the approximate time the reference block expires, zero if the block timestamp is unknown.
+func (r *TransactionReference) ExpiresAt() time.Time {
+	if r.ReferenceBlockTimestamp.IsZero() {
+		return time.Time{}
+	}
+
+	return r.ReferenceBlockTimestamp.Add(TransactionExpiryDuration)
+}
+
+// MarshalIndent returns the reference as indented JSON.
+func (r *TransactionReference) MarshalIndent() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,84 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-cli/pkg/flowkit"
+)
+
+func TestTransactionReference(t *testing.T) {
+	block := &flow.Block{
+		BlockHeader: flow.BlockHeader{
+			ID:        flow.HexToID("7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7"),
+			Height:    42,
+			Timestamp: time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	proposer := &flow.Account{
+		Address: flow.HexToAddress("01cf0e2f2f715450"),
+		Keys: []*flow.AccountKey{
+			{Index: 0, SequenceNumber: 3},
+			{Index: 1, SequenceNumber: 7},
+		},
+	}
+
+	t.Run("Round Trip", func(t *testing.T) {
+		reference, err := flowkit.NewTransactionReference(block, proposer, 1)
+		require.NoError(t, err)
+
+		data, err := reference.MarshalIndent()
+		require.NoError(t, err)
+
+		parsed, err := flowkit.ParseTransactionReference(data)
+		require.NoError(t, err)
+		assert.Equal(t, reference, parsed)
+
+		id, err := parsed.BlockID()
+		require.NoError(t, err)
+		assert.Equal(t, block.ID, id)
+
+		address, err := parsed.ProposerAddress()
+		require.NoError(t, err)
+		assert.Equal(t, proposer.Address, address)
+
+		assert.Equal(t, 1, parsed.Proposer.KeyIndex)
+		assert.Equal(t, uint64(7), parsed.Proposer.SequenceNumber)
+		assert.Equal(t, block.Timestamp.Add(flowkit.TransactionExpiryDuration), parsed.ExpiresAt())
+	})
+
+	t.Run("Missing Key", func(t *testing.T) {
+		_, err := flowkit.NewTransactionReference(block, proposer, 2)
+		assert.EqualError(t, err, "failed to retrieve proposer key at index 2")
+	})
+
+	t.Run("Invalid Reference", func(t *testing.T) {
+		_, err := flowkit.ParseTransactionReference([]byte(`{"version": 2}`))
+		assert.EqualError(t, err, "unsupported transaction reference version 2")
+
+		_, err = flowkit.ParseTransactionReference([]byte(`{"version": 1, "referenceBlockId": "1234"}`))
+		assert.EqualError(t, err, "invalid reference block ID in the transaction reference: invalid identifier 1234")
+	})
+}
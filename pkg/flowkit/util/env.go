@@ -44,5 +44,14 @@ func EnvFromNetwork(network flow.ChainID) templates.Environment {
 		}
 	}
 
+	if network == flow.Emulator {
+		return templates.Environment{
+			FungibleTokenAddress:  "ee82856bf20e2aa6",
+			FlowTokenAddress:      "0ae53cb6e3f42a79",
+			FlowFeesAddress:       "e5a8b7f23e8b548f",
+			ServiceAccountAddress: "f8d6e0586b0a20c7",
+		}
+	}
+
 	return templates.Environment{}
 }
@@ -165,13 +165,12 @@ type ReaderWriter interface {
 	WriteFile(filename string, data []byte, perm os.FileMode) error
 }
 
-// AddToGitIgnore adds a new line to the .gitignore if one doesn't exist it creates it.
-func AddToGitIgnore(filename string, loader ReaderWriter) error {
-	currentWd, err := os.Getwd()
-	if err != nil {
-		return err
-	}
-	gitIgnorePath := path.Join(currentWd, ".gitignore")
+// AddToGitIgnore adds a new line to the .gitignore unless the file is already ignored, if one doesn't exist it creates it.
+//
+// The .gitignore is the one in the directory of the file, so the file is ignored wherever the command is run from.
+func AddToGitIgnore(filePath string, loader ReaderWriter) error {
+	gitIgnorePath := filepath.Join(filepath.Dir(filePath), ".gitignore")
+	filename := filepath.Base(filePath)
 	gitIgnoreFiles := ""
 	filePermissions := os.FileMode(0644)
 
@@ -183,6 +182,12 @@ func AddToGitIgnore(filename string, loader ReaderWriter) error {
 		}
 		gitIgnoreFiles = string(gitIgnoreFilesRaw)
 		filePermissions = fileStat.Mode().Perm()
+
+		for _, line := range strings.Split(gitIgnoreFiles, "\n") {
+			if strings.TrimSpace(line) == filename {
+				return nil // already ignored
+			}
+		}
 	}
 	return loader.WriteFile(
 		gitIgnorePath,